@@ -4,9 +4,13 @@ go 1.16
 
 require (
 	github.com/alecthomas/chroma/v2 v2.3.0
-	github.com/charmbracelet/bubbles v0.10.3
-	github.com/charmbracelet/bubbletea v0.20.0
+	github.com/atotto/clipboard v0.1.4
+	github.com/charmbracelet/bubbles v0.14.0
+	github.com/charmbracelet/bubbletea v0.21.0
 	github.com/charmbracelet/lipgloss v0.7.1
+	github.com/fsnotify/fsnotify v1.5.4
 	github.com/lucasb-eyer/go-colorful v1.2.0
 	github.com/muesli/termenv v0.15.1
+	github.com/pkg/sftp v1.13.5
+	golang.org/x/crypto v0.0.0-20220525230936-793ad666bf5e
 )
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"sort"
+	"time"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// bucket is a due date grouping of the agenda view.
+type bucket int
+
+const (
+	overdue bucket = iota
+	dueToday
+	dueThisWeek
+	dueLater
+	noDate
+)
+
+func (b bucket) String() string {
+	switch b {
+	case overdue:
+		return "Overdue"
+	case dueToday:
+		return "Today"
+	case dueThisWeek:
+		return "This Week"
+	case dueLater:
+		return "Later"
+	}
+	return "No Date"
+}
+
+var bucketStyle lg.Style = lg.NewStyle().Bold(true).Underline(true)
+
+// overdueStyle marks the header of items past their due date
+var overdueStyle lg.Style = bucketStyle.Copy().Foreground(lg.Color("#ff5f5f"))
+
+// dueBucket places item by its due date. This week is the coming seven days.
+func dueBucket(item *tuido.Item, now time.Time) bucket {
+	due := item.Due()
+	if due == nil || due.IsZero() {
+		return noDate
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch days := int(day.Sub(today).Hours() / 24); {
+	case days < 0:
+		return overdue
+	case days == 0:
+		return dueToday
+	case days <= 7:
+		return dueThisWeek
+	}
+	return dueLater
+}
+
+// agendaActive reports whether the list is grouped into due date buckets.
+// The agenda groups pending items only.
+func (t tui) agendaActive() bool {
+	return t.agenda && t.itemsFilter == todo && !t.board
+}
+
+// applyAgenda groups the (sorted) render selection into due date
+// buckets, keeping the existing order within each bucket.
+func (t *tui) applyAgenda() {
+	now := time.Now()
+	sort.SliceStable(t.renderSelection, func(i, j int) bool {
+		return dueBucket(t.renderSelection[i], now) < dueBucket(t.renderSelection[j], now)
+	})
+	t.depths = map[*tuido.Item]int{}
+}
+
+// bucketHeader returns the header to render above the i'th listed item,
+// or "" if the item is not the first of its bucket.
+func (t tui) bucketHeader(i int, now time.Time) string {
+	b := dueBucket(t.renderSelection[i], now)
+	if i > 0 && dueBucket(t.renderSelection[i-1], now) == b {
+		return ""
+	}
+	if b == overdue {
+		return overdueStyle.Render(b.String())
+	}
+	return bucketStyle.Render(b.String())
+}
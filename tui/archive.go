@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// setArchiveMode asks for confirmation to archive the listed done items.
+func (t *tui) setArchiveMode() {
+	if t.itemsFilter != done || len(t.listed()) == 0 {
+		return
+	}
+	t.mode = archiving
+}
+
+func (t tui) archivePrompt() string {
+	return fmt.Sprintf("move %d done items out of their files and into %s? [y] - Archive,  [any key] - Cancel",
+		len(t.listed()), t.config.archive)
+}
+
+// archiveListed moves the listed done items to the archive, and re-reads
+// the files they were removed from.
+func (t *tui) archiveListed() error {
+	items := append([]*tuido.Item{}, t.listed()...)
+
+	changed, err := tuido.Archive(items, t.config.archive)
+	for _, file := range changed {
+		t.reloadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	t.notice = fmt.Sprintf("archived %d items", len(items))
+	return nil
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// pathArgs are the directories and files named as positional arguments.
+type pathArgs struct {
+	dirs  []string
+	files []string
+}
+
+// parsePathArgs sorts the positional arguments into directories, which
+// are scanned, and files, which are parsed whatever their extension.
+// Each path must exist.
+func parsePathArgs(args []string) (pathArgs, error) {
+	paths := pathArgs{}
+	seen := map[string]bool{}
+
+	for _, arg := range args {
+		abs, err := filepath.Abs(arg)
+		if err != nil {
+			return paths, err
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return paths, fmt.Errorf("cannot scan %s: %w", arg, err)
+		}
+		if seen[abs] {
+			continue
+		}
+		seen[abs] = true
+
+		if info.IsDir() {
+			paths.dirs = append(paths.dirs, abs)
+		} else {
+			paths.files = append(paths.files, abs)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// batchKeys maps keys in batch mode to the status they apply.
+var batchKeys = map[string]tuido.Status{
+	" ": tuido.Open,
+	"a": tuido.Ongoing,
+	"r": tuido.Review,
+	"x": tuido.Checked,
+	"s": tuido.Obsolete,
+}
+
+func (t *tui) setBatchMode() {
+	if len(t.listed()) == 0 {
+		return
+	}
+	t.mode = batch
+}
+
+func (t tui) batchPrompt() string {
+	custom := ""
+	for _, def := range t.config.statuses {
+		if def.key != "" {
+			custom += fmt.Sprintf(", [%s] %s", def.key, def.Name)
+		}
+	}
+	return fmt.Sprintf("set %d items to: [space] open, [a] ongoing, [r] review, [x] checked, [s] obsolete%s. [esc] - Cancel",
+		len(t.listed()), custom)
+}
+
+// setBatchStatus applies status s to every listed item. The change is
+// undone as a unit.
+func (t *tui) setBatchStatus(s tuido.Status) error {
+	return t.setItemsStatus(append([]*tuido.Item{}, t.listed()...), s)
+}
+
+// setItemsStatus applies status s to each of items, undone as a unit.
+// Each affected file is written once.
+func (t *tui) setItemsStatus(items []*tuido.Item, s tuido.Status) error {
+	var firstErr error
+	pending := unchecked(items)
+	err := t.track(items, func() error {
+		return tuido.Batch(func() error {
+			for _, item := range items {
+				was := item.Satus()
+				err := item.SetStatus(s)
+				if err == nil {
+					err = t.timers.clock(item, was)
+				}
+				t.stats.record(item, err)
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					continue
+				}
+				t.queueHook(item)
+				if s == tuido.Checked {
+					t.stats.checked++
+				}
+			}
+			return firstErr
+		})
+	})
+	if s == tuido.Checked {
+		if rerr := t.recur(pending); err == nil {
+			err = rerr
+		}
+	}
+
+	t.populateRenderSelection()
+	return err
+}
@@ -0,0 +1,33 @@
+package tui
+
+import "github.com/nilock/tuido/tuido"
+
+// blameListed reads from git when the listed items' lines were committed,
+// for their ages, if ages are shown, sorted by, or flagged as stale. Each
+// item is blamed once.
+func (t *tui) blameListed() {
+	if !t.showAges && t.sortMode != sortAge && t.config.stale == 0 {
+		return
+	}
+	if t.blamed == nil {
+		t.blamed = map[*tuido.Item]bool{}
+	}
+	items := []*tuido.Item{}
+	for _, item := range t.renderSelection {
+		if !t.blamed[item] {
+			t.blamed[item] = true
+			items = append(items, item)
+		}
+	}
+	tuido.Blame(items)
+}
+
+// stale reports whether item is pending, and older than the stale
+// config.
+func (t tui) stale(item tuido.Item) bool {
+	if t.config.stale == 0 || item.Satus().Finished() {
+		return false
+	}
+	age, ok := item.Age()
+	return ok && age > t.config.stale
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// boardColumn is a column of the board, holding the items of its
+// statuses.
+type boardColumn struct {
+	name     string
+	statuses []tuido.Status
+}
+
+// boardColumns are the columns of the board, from left to right.
+var boardColumns = []boardColumn{
+	{"Open", []tuido.Status{tuido.Open}},
+	{"Ongoing", []tuido.Status{tuido.Ongoing, tuido.Review}},
+	{"Done", []tuido.Status{tuido.Checked, tuido.Obsolete}},
+}
+
+// columnOf returns the index of the board column holding item. Items of
+// custom statuses are held in the Open column, if pending, or else in
+// the Done column.
+func columnOf(item *tuido.Item) int {
+	for c, column := range boardColumns {
+		for _, s := range column.statuses {
+			if item.Satus() == s {
+				return c
+			}
+		}
+	}
+	if item.Satus().Finished() {
+		return len(boardColumns) - 1
+	}
+	return 0
+}
+
+// toggleBoard switches the board view on or off, keeping the selection.
+func (t *tui) toggleBoard() {
+	current := t.currentSelection()
+	t.board = !t.board
+	t.populateRenderSelection()
+	if current != nil {
+		t.selectItem(current)
+	}
+}
+
+// applyBoard orders the (sorted) render selection by board column,
+// keeping the existing order within each column.
+func (t *tui) applyBoard() {
+	sort.SliceStable(t.renderSelection, func(i, j int) bool {
+		return columnOf(t.renderSelection[i]) < columnOf(t.renderSelection[j])
+	})
+}
+
+// boardStarts returns the index in the render selection of the first
+// item of each board column, and then the length of the selection.
+func (t tui) boardStarts() []int {
+	starts := make([]int, len(boardColumns)+1)
+	c := 0
+	for i, item := range t.renderSelection {
+		for c < columnOf(item) {
+			c++
+			starts[c] = i
+		}
+	}
+	for c++; c <= len(boardColumns); c++ {
+		starts[c] = len(t.renderSelection)
+	}
+	return starts
+}
+
+// selectedColumn returns the board column of the selection.
+func (t tui) selectedColumn() int {
+	if t.selection < len(t.renderSelection) {
+		return columnOf(t.renderSelection[t.selection])
+	}
+	return 0
+}
+
+// moveInColumn moves the selection delta items up or down its board
+// column, stopping at the column's first and last items.
+func (t *tui) moveInColumn(delta int) {
+	starts := t.boardStarts()
+	c := t.selectedColumn()
+	if starts[c] == starts[c+1] {
+		return
+	}
+	t.setSelection(min(max(t.selection+delta, starts[c]), starts[c+1]-1))
+}
+
+// moveBoardColumn moves the selection to the next column delta columns
+// to the left or right which has items, at the same row where possible.
+func (t *tui) moveBoardColumn(delta int) {
+	if len(t.renderSelection) == 0 {
+		return
+	}
+	starts := t.boardStarts()
+	c := t.selectedColumn()
+	row := t.selection - starts[c]
+	for target := c + delta; target >= 0 && target < len(boardColumns); target += delta {
+		if starts[target] < starts[target+1] {
+			t.setSelection(min(starts[target]+row, starts[target+1]-1))
+			return
+		}
+	}
+}
+
+// renderBoard renders the listed items as cards in a column per status.
+// Each column scrolls to keep the selection in view.
+func (t *tui) renderBoard(height, width int) string {
+	t.currentPage, t.pages = 0, 1
+	colWidth := width / len(boardColumns)
+	starts := t.boardStarts()
+	selected := lg.NewStyle().Bold(true)
+
+	columns := []string{}
+	for c, column := range boardColumns {
+		count := starts[c+1] - starts[c]
+		header := lg.NewStyle().Bold(true).Underline(true).Render(fmt.Sprintf("%s (%d)", column.name, count))
+
+		cards := []string{}
+		for i := starts[c]; i < starts[c+1]; i++ {
+			item := t.renderSelection[i]
+			cursor := "  "
+			if t.marked[item] {
+				cursor = markStyle.Render("*") + " "
+			}
+			card := t.renderTuido(*item, colWidth-3)
+			if i == t.selection {
+				cursor = "> "
+				card = selected.Render(card)
+			}
+			cards = append(cards, lg.JoinHorizontal(lg.Top, cursor, card))
+		}
+
+		// scroll the selected column so that the selection is in view
+		stacks, stackStarts := stackItems(cards, max(height-1, 1))
+		shown := 0
+		for s, start := range stackStarts {
+			if t.selection >= starts[c]+start && t.selection < starts[c+1] {
+				shown = s
+			}
+		}
+		body := ""
+		if len(cards) > 0 {
+			body = stacks[shown]
+		}
+
+		columns = append(columns, lg.NewStyle().
+			Width(colWidth-1).
+			Height(height).
+			MaxHeight(height).
+			MarginRight(1).
+			Render(lg.JoinVertical(lg.Left, header, body)))
+	}
+	return lg.JoinHorizontal(lg.Top, columns...)
+}
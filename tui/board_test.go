@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestBoard(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] a open\n[@] b ongoing\n[x] c done\n[ ] d open\n[r] e review\n"), 0644)
+	items, _ := getItems(file)
+
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.toggleBoard()
+
+	expected := []*tuido.Item{items[0], items[3], items[1], items[4], items[2]}
+	for i, item := range m.renderSelection {
+		if item != expected[i] {
+			t.Fatalf("expected items ordered by column, but found %q at %d", item.Text(), i)
+		}
+	}
+	if starts := m.boardStarts(); starts[1] != 2 || starts[2] != 4 || starts[3] != 5 {
+		t.Errorf("unexpected column starts %v", starts)
+	}
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			model, _ := m.Update(k)
+			m = model.(tui)
+		}
+	}
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	// down stops at the end of the column, and right keeps the row
+	press(down, down)
+	if m.currentSelection() != items[3] {
+		t.Errorf("expected the last open item selected, but found %q", m.currentSelection().Text())
+	}
+	press(right)
+	if m.currentSelection() != items[4] {
+		t.Errorf("expected the second ongoing item selected, but found %q", m.currentSelection().Text())
+	}
+
+	// a status key moves the card, and the selection with it
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if m.currentSelection() != items[4] || m.selectedColumn() != 2 {
+		t.Errorf("expected the checked card selected in the done column, but found %q", m.currentSelection().Text())
+	}
+}
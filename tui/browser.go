@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// folder is a directory containing items, as listed in the file browser.
+type folder struct {
+	path  string
+	depth int
+	count int
+}
+
+// folderBrowser is the side panel listing scanned directories,
+// used to scope the item list to a subtree.
+type folderBrowser struct {
+	folders []folder
+	cursor  int
+}
+
+// browserWidth is the width of the file browser side panel.
+const browserWidth = 32
+
+// newFolderBrowser lists every directory holding items, along with
+// the ancestors of those directories up to root. Each folder is counted
+// with the number of items beneath it that pass the inView filter.
+func newFolderBrowser(root string, items []*tuido.Item, inView func(*tuido.Item) bool) folderBrowser {
+	counts := map[string]int{}
+
+	for _, item := range items {
+		dir := filepath.Dir(item.File())
+		if _, ok := counts[dir]; !ok {
+			counts[dir] = 0
+		}
+
+		// register ancestors under the root, so that the tree is complete
+		for d := dir; isUnder(d, root) && d != root; d = filepath.Dir(d) {
+			if _, ok := counts[filepath.Dir(d)]; !ok && isUnder(filepath.Dir(d), root) {
+				counts[filepath.Dir(d)] = 0
+			}
+		}
+	}
+
+	for _, item := range items {
+		if !inView(item) {
+			continue
+		}
+		for dir := range counts {
+			if isUnder(item.File(), dir) {
+				counts[dir]++
+			}
+		}
+	}
+
+	folders := []folder{}
+	for dir, count := range counts {
+		depth := 0
+		if rel, err := filepath.Rel(root, dir); err == nil && isUnder(dir, root) && rel != "." {
+			depth = strings.Count(rel, string(filepath.Separator)) + 1
+		}
+		folders = append(folders, folder{dir, depth, count})
+	}
+	sort.Slice(folders, func(i, j int) bool {
+		return folders[i].path < folders[j].path
+	})
+
+	return folderBrowser{folders: folders}
+}
+
+// isUnder reports whether path is dir, or is inside of dir.
+func isUnder(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+func (b *folderBrowser) move(delta int) {
+	b.cursor = min(max(b.cursor+delta, 0), len(b.folders)-1)
+}
+
+func (b folderBrowser) selected() string {
+	if len(b.folders) == 0 {
+		return ""
+	}
+	return b.folders[b.cursor].path
+}
+
+func (b folderBrowser) View(root string, height int) string {
+	rows := []string{}
+	for i, f := range b.folders {
+		name := filepath.Base(f.path)
+		if f.depth == 0 && f.path != root {
+			name = f.path
+		} else if f.path == root {
+			name = "."
+		}
+
+		row := fmt.Sprintf("%s%s (%d)", strings.Repeat("  ", f.depth), name, f.count)
+		if i == b.cursor {
+			row = lg.NewStyle().Bold(true).Render("> " + row)
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+
+	return lg.NewStyle().
+		Width(browserWidth).
+		Height(height).
+		MaxHeight(height).
+		Border(themeBorder(lg.NormalBorder()), false, true, false, false).
+		Render(strings.Join(rows, "\n"))
+}
+
+// setBrowseMode opens the file browser side panel.
+func (t *tui) setBrowseMode() {
+	now := time.Now()
+	t.browser = newFolderBrowser(t.config.root, t.items, func(i *tuido.Item) bool {
+		return t.inView(i, now)
+	})
+	for i, f := range t.browser.folders {
+		if f.path == t.dirScope {
+			t.browser.cursor = i
+		}
+	}
+	t.mode = browse
+}
+
+func (t *tui) applyDirScope() {
+	if t.dirScope == "" {
+		return
+	}
+
+	filtered := []*tuido.Item{}
+	for _, item := range t.renderSelection {
+		if isUnder(item.File(), t.dirScope) {
+			filtered = append(filtered, item)
+		}
+	}
+	t.renderSelection = filtered
+}
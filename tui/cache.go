@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// indexEntry is the cached scan of one file: the items read from it, as
+// of its size and modification time.
+type indexEntry struct {
+	Size     int64        `json:"size"`
+	Modified int64        `json:"modified"`
+	Items    []cachedItem `json:"items"`
+}
+
+// cachedItem is an item of an indexEntry. Front matter items are kept
+// read-only.
+type cachedItem struct {
+	Line     int    `json:"line"`
+	Raw      string `json:"raw"`
+	ReadOnly bool   `json:"readOnly,omitempty"`
+}
+
+// indexCache is the on-disk index of scanned files, so that files which
+// are unchanged since the last run are not parsed again. It is kept in
+// `tuido/index` in the user cache directory (eg, `~/.cache` in linux).
+//
+// The index is only valid for the configuration it was made with: a
+// change to the markers or todo comments, which decide what is an item,
+// or to the index format, discards it.
+//
+// A file is taken to be unchanged if its size and modification time are
+// as indexed, so an edit which keeps the file's size, made within the
+// resolution of the filesystem's modification times of the indexed scan,
+// is not noticed.
+type indexCache struct {
+	path     string
+	settings string
+
+	mu      sync.Mutex
+	entries map[string]indexEntry
+	// fresh are the entries of this run's files, which are saved over
+	// those of the index
+	fresh map[string]indexEntry
+	// dropped are the files of this run which are no longer indexed
+	dropped map[string]bool
+}
+
+// indexFile is the on-disk form of an indexCache.
+type indexFile struct {
+	Settings string                `json:"settings"`
+	Files    map[string]indexEntry `json:"files"`
+}
+
+// itemCache is the index of the current run, or nil if items are not
+// cached (eg, with -no-cache, or in tests).
+var itemCache *indexCache
+
+// indexPath returns the location of the index cache, or "" if there is no
+// user cache directory.
+func indexPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tuido", "index")
+}
+
+// indexVersion is the version of the index format, and of the parsing
+// of the items it holds. It is bumped by changes to either, so that older
+// indexes are not used.
+const indexVersion = 1
+
+// indexSettings summarizes the settings which decide how files are
+// parsed, so that an index made with other settings is not used.
+func indexSettings() string {
+	return fmt.Sprint(indexVersion, tuido.Markers, tuido.CustomStatuses, tuido.TodoComments)
+}
+
+// loadIndex reads the index cache at path. A missing or malformed index,
+// or one made with other settings, gives an empty cache.
+func loadIndex(path string) *indexCache {
+	c := &indexCache{
+		path:     path,
+		settings: indexSettings(),
+		entries:  map[string]indexEntry{},
+		fresh:    map[string]indexEntry{},
+		dropped:  map[string]bool{},
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	index := indexFile{}
+	if json.Unmarshal(data, &index) == nil && index.Settings == c.settings && index.Files != nil {
+		c.entries = index.Files
+	}
+	return c
+}
+
+// getItems returns the items of file, from the index if the file is
+// unchanged since it was indexed, and otherwise as read by getItems. A
+// nil cache always reads the file.
+func (c *indexCache) getItems(file string) ([]*tuido.Item, error) {
+	if c == nil {
+		return getItems(file)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return getItems(file)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[file]
+	c.mu.Unlock()
+	if ok && entry.Size == info.Size() && entry.Modified == info.ModTime().UnixNano() {
+		items := restoreItems(file, entry)
+		c.keep(file, entry)
+		return items, nil
+	}
+
+	// skipped files are not indexed, so that they are reported again, and
+	// nor are files read while every item is read-only (the file is not
+	// writable, or in -read-only mode), so that front matter items can be
+	// told from the rest
+	items, err := getItems(file)
+	if err != nil || skipped.has(file) || tuido.ReadOnly || !tuido.Writable(file) {
+		c.drop(file)
+		return items, err
+	}
+	entry = indexEntry{Size: info.Size(), Modified: info.ModTime().UnixNano()}
+	for _, item := range items {
+		entry.Items = append(entry.Items, cachedItem{item.Line(), item.Raw(), item.ReadOnly()})
+	}
+	c.keep(file, entry)
+	return items, nil
+}
+
+// restoreItems rebuilds the items of an index entry. Items of files which
+// are no longer writable are read-only, as getItems would make them.
+func restoreItems(file string, entry indexEntry) []*tuido.Item {
+	items := []*tuido.Item{}
+	for _, cached := range entry.Items {
+		item := tuido.FromCache(file, cached.Line, cached.Raw)
+		if cached.ReadOnly {
+			item.SetReadOnly()
+		}
+		items = append(items, item)
+	}
+	if len(items) > 0 && !tuido.Writable(file) {
+		for _, item := range items {
+			item.SetReadOnly()
+		}
+	}
+	tuido.Nest(items)
+	return items
+}
+
+func (c *indexCache) keep(file string, entry indexEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fresh[file] = entry
+	delete(c.dropped, file)
+}
+
+// drop removes file from the index, when it is saved.
+func (c *indexCache) drop(file string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.fresh, file)
+	c.dropped[file] = true
+}
+
+// save writes the entries of this run's files to the index, merged with
+// the entries of the files of other runs, eg of other directories.
+// Entries of files which no longer exist are pruned.
+func (c *indexCache) save() error {
+	if c == nil || c.path == "" {
+		return nil
+	}
+	c.mu.Lock()
+	files := map[string]indexEntry{}
+	for file, entry := range c.entries {
+		if _, err := os.Stat(file); err == nil && !c.dropped[file] {
+			files[file] = entry
+		}
+	}
+	for file, entry := range c.fresh {
+		files[file] = entry
+	}
+	data, err := json.Marshal(indexFile{Settings: c.settings, Files: files})
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	// a temp file of its own keeps concurrent runs from writing over one
+	// another's partial index
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
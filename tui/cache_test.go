@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestIndexCache(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "todo.md")
+	os.WriteFile(file, []byte("---\ntodos:\n  - front matter\n---\n- [ ] parent\n  - [x] child\n"), 0644)
+	path := filepath.Join(dir, "cache", "index")
+
+	c := loadIndex(path)
+	if _, err := c.getItems(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// an unchanged file is restored from the index, even if its lines are
+	// not read again
+	c = loadIndex(path)
+	entry := c.entries[file]
+	if len(entry.Items) != 3 {
+		t.Fatalf("expected 3 indexed items, but found %v", entry.Items)
+	}
+	entry.Items[1].Raw = "- [ ] parent, as indexed"
+	c.entries[file] = entry
+	items, _ := c.getItems(file)
+	if len(items) != 3 || items[1].Raw() != "- [ ] parent, as indexed" {
+		t.Fatalf("expected the indexed items, but found %v", items)
+	}
+	if !items[0].ReadOnly() || items[1].ReadOnly() || items[2].Parent() != items[1] {
+		t.Errorf("expected the front matter item read-only, and the child nested")
+	}
+
+	// a changed file is parsed again
+	os.WriteFile(file, []byte("[ ] changed\n"), 0644)
+	os.Chtimes(file, time.Now(), time.Now().Add(time.Minute))
+	if items, _ := c.getItems(file); len(items) != 1 || items[0].Raw() != "[ ] changed" {
+		t.Errorf("expected the changed file parsed, but found %v", items)
+	}
+
+	// an index made with other markers is discarded
+	c.save()
+	tuido.Markers = map[string]tuido.Status{"[>]": tuido.Ongoing}
+	defer func() { tuido.Markers = map[string]tuido.Status{} }()
+	if c := loadIndex(path); len(c.entries) != 0 {
+		t.Errorf("expected the index discarded, but found %v", c.entries)
+	}
+}
+
+func TestIndexCacheMerge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache", "index")
+	first, second := filepath.Join(dir, "one", "todo.xit"), filepath.Join(dir, "two", "todo.xit")
+	for _, file := range []string{first, second} {
+		os.MkdirAll(filepath.Dir(file), 0755)
+		os.WriteFile(file, []byte("[ ] item\n"), 0644)
+	}
+
+	// runs in other directories keep one another's entries
+	for _, file := range []string{first, second} {
+		c := loadIndex(path)
+		c.getItems(file)
+		if err := c.save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c := loadIndex(path); len(c.entries) != 2 {
+		t.Errorf("expected the entries of both runs, but found %v", c.entries)
+	}
+
+	// the entries of files which no longer exist are pruned
+	os.Remove(first)
+	loadIndex(path).save()
+	if c := loadIndex(path); len(c.entries) != 1 || c.entries[second].Size == 0 {
+		t.Errorf("expected only the entry of the remaining file, but found %v", c.entries)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "cache", "*.tmp")); len(matches) != 0 {
+		t.Errorf("expected no temp files left, but found %v", matches)
+	}
+}
@@ -0,0 +1,285 @@
+package tui
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// tagList is a repeatable flag of comma separated tag names.
+type tagList []string
+
+func (l *tagList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *tagList) Set(v string) error {
+	for _, tag := range strings.Split(v, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), runConfig.tagSigil)
+		if tag == "" {
+			return fmt.Errorf("empty tag in %q", v)
+		}
+		*l = append(*l, tag)
+	}
+	return nil
+}
+
+// listCommand prints the pending (or done) items, as listed by the app:
+// `tuido list [-tag name] [-done] [-json]`.
+func listCommand(fs *flag.FlagSet) func(args []string) int {
+	tags := tagList{}
+	fs.Var(&tags, "tag", "list only items with this tag. May be repeated, to list items with all of the tags")
+	doneFlag := fs.Bool("done", false, "list done items, rather than pending ones")
+	jsonFlag := fs.Bool("json", false, "print items as JSON lines")
+	return func(args []string) int {
+		ws := openWorkspace(args)
+		defer ws.close()
+
+		t := newTUI(ws.items(), runConfig)
+		if *doneFlag {
+			t.itemsFilter = done
+		}
+		terms := []string{}
+		for _, tag := range tags {
+			terms = append(terms, runConfig.tagSigil+tag)
+		}
+		t.filterAll = true
+		t.filter.SetValue(strings.Join(terms, " ") + " ") // trailing space: exact tags
+		t.populateRenderSelection()
+
+		enc := json.NewEncoder(os.Stdout)
+		for _, item := range t.renderSelection {
+			if *jsonFlag {
+				enc.Encode(item)
+				continue
+			}
+			fmt.Printf("%s  %s  %s\n", item.ID(), item.String(), item.Location())
+		}
+		return 0
+	}
+}
+
+// addCommand appends a new item to the writeto location, as the app's
+// insert prompt does: `tuido add text...`.
+func addCommand(args []string) int {
+	return appendItem(args, "usage: tuido add <item text>", func() string { return runConfig.writeto })
+}
+
+// captureCommand appends a new item to the inbox, and prints it, without
+// launching the app: `tuido -a <item text>`, eg from a shell alias or a
+// launcher. Arguments after the flag's are part of the text.
+func captureCommand(args []string) int {
+	return appendItem(args, "usage: tuido -a <item text>", func() string { return runConfig.inbox })
+}
+
+// appendItem appends the item text of args to the target location, once
+// the configuration is read, and prints the new item. It returns the
+// exit code.
+func appendItem(args []string, usage string, target func() string) int {
+	text := strings.TrimSpace(strings.Join(args, " "))
+	if text == "" {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error reading the working directory: %s\n", err)
+		return 1
+	}
+	if _, local := dirFlag.split(); len(local) > 0 {
+		if root, err = filepath.Abs(local[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "error reading -dir %s: %s\n", local[0], err)
+			return 1
+		}
+	}
+	configure(root)
+
+	item, err := createItem(target(), text, runConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("%s  %s  %s\n", item.ID(), item.String(), item.Location())
+	return 0
+}
+
+// doneCommand checks off the items with the given ids, or unique id
+// prefixes: `tuido done id...`. The onchange hook is run for each.
+func doneCommand(ids []string) int {
+	if len(ids) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: tuido done <id>...")
+		return 2
+	}
+
+	ws := openWorkspace(nil)
+	defer ws.close()
+	items := ws.items()
+	timers := loadTimers(timersPath())
+
+	code := 0
+	for _, id := range ids {
+		item, err := findItem(items, id)
+		var was tuido.Status
+		if err == nil {
+			was = item.Satus()
+			err = item.SetStatus(tuido.Checked)
+		}
+		if err == nil {
+			err = timers.clock(item, was)
+		}
+		wasChecked := was == tuido.Checked
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", id, err)
+			code = 1
+			continue
+		}
+		fmt.Printf("%s  %s  %s\n", item.ID(), item.String(), item.Location())
+
+		if !wasChecked && item.Satus() == tuido.Checked {
+			next, err := item.Recur()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", id, err)
+				code = 1
+			} else if next != nil {
+				fmt.Printf("%s  %s  %s\n", next.ID(), next.String(), next.Location())
+			}
+		}
+
+		if runConfig.onChange != "" {
+			msg := runHook(runConfig.onChange, hookCall{item.File(), item.Line(), item.Satus()})()
+			if failed, ok := msg.(hookFailedMsg); ok {
+				fmt.Fprintln(os.Stderr, failed.err)
+				code = 1
+			}
+		}
+	}
+	return code
+}
+
+// exportCommand prints every scanned item, of both tabs, or those
+// matching a filter, in an export format:
+// `tuido export [-format json|csv|md|ics] [-filter expr] [path...]`.
+func exportCommand(fs *flag.FlagSet) func(args []string) int {
+	formatFlag := fs.String("format", "json", "the export format: json, csv, md, or ics")
+	filterFlag := fs.String("filter", "", "export only items matching this filter, as typed at the app's filter prompt, eg \"#work !#someday\"")
+	return func(args []string) int {
+		format, ok := exportFormats[*formatFlag]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown export format %s: use json, csv, md, or ics\n", *formatFlag)
+			return 2
+		}
+
+		ws := openWorkspace(args)
+		defer ws.close()
+		items := ws.items()
+		sortItems(items)
+
+		q := parseFilter(*filterFlag+" ", false) // trailing space: exact tags
+		matched := []*tuido.Item{}
+		for _, item := range items {
+			if q.matches(item) {
+				matched = append(matched, item)
+			}
+		}
+
+		data, err := format.render(matched)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		os.Stdout.Write(data)
+		return 0
+	}
+}
+
+// archiveCommand moves the done items, or those matching a filter, to the
+// archive location, as the app's archive prompt does:
+// `tuido archive [-filter expr] [path...]`.
+func archiveCommand(fs *flag.FlagSet) func(args []string) int {
+	filterFlag := fs.String("filter", "", "archive only done items matching this filter, as typed at the app's filter prompt")
+	return func(args []string) int {
+		ws := openWorkspace(args)
+		defer ws.close()
+		items := ws.items()
+		sortItems(items)
+
+		q := parseFilter(*filterFlag+" ", false) // trailing space: exact tags
+		archived := []*tuido.Item{}
+		for _, item := range items {
+			finished := item.Satus().Finished()
+			if finished && !item.ReadOnly() && q.matches(item) {
+				archived = append(archived, item)
+			}
+		}
+
+		if _, err := tuido.Archive(archived, runConfig.archive); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, item := range archived {
+			fmt.Printf("%s  %s  %s\n", item.ID(), item.String(), item.Location())
+		}
+		return 0
+	}
+}
+
+// findItem returns the item with id, or else the one item whose id
+// begins with id.
+func findItem(items []*tuido.Item, id string) (*tuido.Item, error) {
+	matches := []*tuido.Item{}
+	for _, item := range items {
+		if item.ID() == id {
+			return item, nil
+		}
+		if strings.HasPrefix(item.ID(), id) {
+			matches = append(matches, item)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no item with this id")
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("%d items have ids beginning %s", len(matches), id)
+}
+
+// retagCommand renames a tag on every scanned item which has it, and
+// prints the renamed items: `tuido retag <from> <to> [path...]`.
+func retagCommand(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: tuido retag <from> <to> [path...]")
+		return 2
+	}
+	from, err := tagName(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	to, err := tagName(args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ws := openWorkspace(args[2:])
+	defer ws.close()
+	items := tagged(ws.items(), from)
+
+	err = renameTag(items, from, to)
+	for _, item := range items {
+		fmt.Printf("%s  %s  %s\n", item.ID(), item.String(), item.Location())
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
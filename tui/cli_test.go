@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindItem(t *testing.T) {
+	items := newItems("[ ] first", "[ ] second", "[ ] third")
+	ids := []string{items[0].ID(), items[1].ID(), items[2].ID()}
+
+	if item, err := findItem(items, ids[1]); err != nil || item != items[1] {
+		t.Errorf("expected to find the second item by its id, but found %v, %v", item, err)
+	}
+	if item, err := findItem(items, ids[2][:6]); err != nil || item != items[2] {
+		t.Errorf("expected to find the third item by an id prefix, but found %v, %v", item, err)
+	}
+	if _, err := findItem(items, "zz"); err == nil {
+		t.Errorf("expected an error for an unknown id")
+	}
+	if _, err := findItem(items, ""); err == nil {
+		t.Errorf("expected an error for a prefix shared by several items")
+	}
+}
+
+func TestCaptureCommand(t *testing.T) {
+	dir := t.TempDir()
+	savedConfig, savedDirs, savedInbox := runConfig, dirFlag, *inboxFlag
+	defer func() { runConfig, dirFlag, *inboxFlag = savedConfig, savedDirs, savedInbox }()
+	dirFlag = dirList{dir}
+
+	for _, tc := range []struct {
+		name, content, expected string
+	}{
+		{"existing", "[ ] first\n", "[ ] first\n[ ] buy milk\n"},
+		{"missing", "", "[ ] buy milk\n"},
+		{"unterminated", "[ ] first", "[ ] first\n[ ] buy milk\n"},
+	} {
+		file := filepath.Join(dir, tc.name+".xit")
+		if tc.content != "" {
+			os.WriteFile(file, []byte(tc.content), 0644)
+		}
+		*inboxFlag = file
+		if code := captureCommand([]string{"buy", "milk"}); code != 0 {
+			t.Fatalf("%s: expected the item captured, but found exit code %d", tc.name, code)
+		}
+		expectContent(t, file, tc.expected)
+	}
+
+	if code := captureCommand(nil); code != 2 {
+		t.Errorf("expected a usage error for an empty item, but found exit code %d", code)
+	}
+}
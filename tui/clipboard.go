@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardMsg reports a copy to the clipboard, with its notice, or the
+// error which stopped it.
+type clipboardMsg struct {
+	notice string
+	err    error
+}
+
+// copyCmd copies s to the clipboard, as writeClipboard does, reporting
+// notice once it is copied. It runs as a command, rather than within
+// Update, so that an OSC 52 sequence is written to the terminal by itself,
+// in a single write, rather than among the lines of the frame being
+// rendered.
+func copyCmd(s string, notice string) tea.Cmd {
+	return func() tea.Msg {
+		if err := writeClipboard(s); err != nil {
+			return clipboardMsg{err: fmt.Errorf("could not copy to clipboard: %s", err)}
+		}
+		return clipboardMsg{notice: notice}
+	}
+}
+
+// writeClipboard copies s to the system clipboard: with the platform's
+// clipboard (pbcopy, xclip, etc), or else, or when run over ssh, with an
+// OSC 52 escape sequence, which the terminal copies to its own clipboard.
+func writeClipboard(s string) error {
+	if os.Getenv("SSH_TTY") == "" && !clipboard.Unsupported {
+		if err := clipboard.WriteAll(s); err == nil {
+			return nil
+		}
+	}
+	return writeOSC52(os.Stdout, s, os.Getenv("TMUX") != "")
+}
+
+// writeOSC52 writes the OSC 52 sequence copying s to w. Within tmux, the
+// sequence is wrapped to be passed through to the outer terminal.
+func writeOSC52(w io.Writer, s string, tmux bool) error {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(s)) + "\a"
+	if tmux {
+		seq = "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	}
+	_, err := fmt.Fprint(w, seq)
+	return err
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestWriteOSC52(t *testing.T) {
+	var b bytes.Buffer
+	writeOSC52(&b, "- [ ] one", false)
+	if b.String() != "\x1b]52;c;LSBbIF0gb25l\a" {
+		t.Errorf("expected the OSC 52 sequence, but found %q", b.String())
+	}
+
+	b.Reset()
+	writeOSC52(&b, "- [ ] one", true)
+	if b.String() != "\x1bPtmux;\x1b\x1b]52;c;LSBbIF0gb25l\a\x1b\\" {
+		t.Errorf("expected the sequence wrapped for tmux, but found %q", b.String())
+	}
+}
+
+func TestCopyReported(t *testing.T) {
+	m := newTUI(newItems("[ ] one"), runConfig)
+	m.populateRenderSelection()
+
+	// the copy is left to a command, which reports back
+	if cmd := m.copyReference(true); cmd == nil || m.notice != "" {
+		t.Fatalf("expected a copy command, and no notice until it is done")
+	}
+	model, _ := m.Update(clipboardMsg{notice: "copied one"})
+	m = model.(tui)
+	if m.notice != "copied one" || m.err != nil {
+		t.Errorf("expected the copy noticed, but found %q and %v", m.notice, m.err)
+	}
+	model, _ = m.Update(clipboardMsg{err: fmt.Errorf("no clipboard")})
+	if m = model.(tui); m.err == nil {
+		t.Errorf("expected the failed copy reported")
+	}
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// command is a headless subcommand, which reads and writes items like the
+// app does, but prints to stdout rather than launching it.
+type command struct {
+	name string
+	// synopsis is the usage after the command's name, eg "[-json] [path...]"
+	synopsis string
+	summary  string
+	// args is what the command's arguments complete to. See argKind.
+	args argKind
+
+	// setup, for commands with flags, declares them on fs, and returns
+	// the command, which is run with the arguments after the flags.
+	setup func(fs *flag.FlagSet) func(args []string) int
+	// run, for commands without flags, is run with every argument.
+	run func(args []string) int
+}
+
+// argKind is what a command's arguments complete to.
+type argKind int
+
+const (
+	// argPaths are files and directories, completed by the shell
+	argPaths argKind = iota
+	// argText is item text, whose #tags complete to the known tags
+	argText
+	// argTags are tag names, without the tag sigil
+	argTags
+	// argWords are the words of the command's choices
+	argWords
+	// argNone are not completed
+	argNone
+)
+
+// commands are the headless subcommands, in the order listed by help.
+// Each returns the process exit code. They are set by init, as help and
+// completion look them up.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "list", synopsis: "[-tag name] [-done] [-json] [path...]", summary: "print the pending (or done) items", setup: listCommand},
+		{name: "add", synopsis: "<item text>", summary: "append an item to the writeto location", args: argText, run: addCommand},
+		{name: "done", synopsis: "<id>...", summary: "check off items by id, or by a unique id prefix", args: argNone, run: doneCommand},
+		{name: "export", synopsis: "[-format json|csv|md|ics] [-filter expr] [path...]", summary: "print every item, in an export format", setup: exportCommand},
+		{name: "archive", synopsis: "[-filter expr] [path...]", summary: "move done items to the archive", setup: archiveCommand},
+		{name: "retag", synopsis: "<from> <to> [path...]", summary: "rename a tag on every item", args: argTags, run: retagCommand},
+		{name: "sync", synopsis: "github [path...]", summary: "import and close GitHub issues assigned to you", args: argWords, run: syncCommand},
+		{name: "serve", synopsis: "[-port 8080] [-addr localhost] [-write] [path...]", summary: "serve the items as JSON, and as a web page", setup: serveCommand},
+		{name: "remind", synopsis: "[-every 5m] [-once] [path...]", summary: "notify the desktop as items fall due", setup: remindCommand},
+		{name: "completion", synopsis: "bash|zsh|fish", summary: "print a shell completion script", args: argWords, run: completionCommand},
+		{name: "help", synopsis: "[command]", summary: "print the usage of tuido, or of a command", args: argWords, run: helpCommand},
+	}
+	flag.Usage = func() { usage(flag.CommandLine.Output()) }
+	commandWords["help"] = commandNames()
+}
+
+// commandWords are the choices of the argWords commands' first argument.
+var commandWords = map[string][]string{
+	"sync":       {"github"},
+	"completion": {"bash", "zsh", "fish"},
+}
+
+// findCommand returns the command named name, or nil if there is none.
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func commandNames() []string {
+	names := []string{}
+	for _, c := range commands {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// flags returns the command's flag set, with its flags declared, and the
+// command to run once they are parsed, or nil if it has no flags.
+func (c command) flags() (*flag.FlagSet, func(args []string) int) {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() { c.usage(fs.Output()) }
+	if c.setup == nil {
+		return fs, nil
+	}
+	return fs, c.setup(fs)
+}
+
+func runCommand(name string, args []string) int {
+	c := findCommand(name)
+	fs, run := c.flags()
+	if run == nil {
+		return c.run(args)
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	return run(fs.Args())
+}
+
+// usage writes the usage of the command, and its flags, to w.
+func (c command) usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: tuido %s %s\n\n%s\n", c.name, c.synopsis, c.summary)
+	fs, run := c.flags()
+	if run != nil {
+		fmt.Fprintln(w, "\nflags:")
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+	}
+}
+
+// usage writes the usage of tuido, its commands, and its flags, to w.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: tuido [flags] [path...]")
+	fmt.Fprintln(w, "       tuido <command> [args]")
+	fmt.Fprintln(w, "\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w, "\nflags:")
+	flag.CommandLine.SetOutput(w)
+	flag.PrintDefaults()
+}
+
+// helpCommand prints the usage of tuido, or of the named command:
+// `tuido help [command]`.
+func helpCommand(args []string) int {
+	if len(args) == 0 {
+		usage(os.Stdout)
+		return 0
+	}
+	c := findCommand(args[0])
+	if c == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %s - one of %s\n", args[0], strings.Join(commandNames(), ", "))
+		return 2
+	}
+	c.usage(os.Stdout)
+	return 0
+}
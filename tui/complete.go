@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// maxCompletions is the number of tag completions shown under the filter
+// prompt.
+const maxCompletions = 5
+
+// typedTag returns the start of the last token of the filter prompt, with
+// the token's negation or parenthesis (eg "!") and the tag name typed so
+// far, if the token is a tag still being typed.
+func (t tui) typedTag() (int, string, string, bool) {
+	value := t.filter.Value()
+	start := strings.LastIndex(value, " ") + 1
+	token := value[start:]
+	tag := strings.TrimLeft(token, "!-(")
+	if !strings.HasPrefix(tag, tuido.TagSigil) {
+		return 0, "", "", false
+	}
+	return start, token[:len(token)-len(tag)], tag[len(tuido.TagSigil):], true
+}
+
+// tagCompletions returns the tags of the items which begin with the tag
+// being typed in the filter prompt, the most used first, while the prompt
+// is focused.
+func (t tui) tagCompletions() []string {
+	if !t.filter.Focused() {
+		return nil
+	}
+	_, _, typed, ok := t.typedTag()
+	if !ok {
+		return nil
+	}
+
+	counts := map[string]int{}
+	for _, item := range t.items {
+		seen := map[string]bool{}
+		for _, tag := range item.Tags() {
+			if name := tag.Name(); !seen[name] && strings.HasPrefix(name, typed) {
+				seen[name] = true
+				counts[name]++
+			}
+		}
+	}
+	names := []string{}
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > maxCompletions {
+		names = names[:maxCompletions]
+	}
+	return names
+}
+
+// completeTag replaces the tag being typed in the filter prompt with its
+// top completion, followed by a space, so that it matches exactly. It
+// reports whether there was a completion.
+func (t *tui) completeTag() bool {
+	completions := t.tagCompletions()
+	if len(completions) == 0 {
+		return false
+	}
+	start, negation, _, _ := t.typedTag()
+	t.filter.SetValue(t.filter.Value()[:start] + negation + tuido.TagSigil + completions[0] + " ")
+	t.filter.CursorEnd()
+	return true
+}
+
+// completionsView renders the tag completions, the top one in bold, as
+// the line below the header, or "" if there are none.
+func (t tui) completionsView() string {
+	completions := t.tagCompletions()
+	if len(completions) == 0 {
+		return ""
+	}
+	faint := lg.NewStyle().Faint(true)
+	rendered := []string{}
+	for i, name := range completions {
+		tag := tuido.TagSigil + name
+		if i == 0 {
+			rendered = append(rendered, lg.NewStyle().Bold(true).Render(tag))
+		} else {
+			rendered = append(rendered, faint.Render(tag))
+		}
+	}
+	return faint.Render("  [tab] ") + strings.Join(rendered, "  ")
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTagCompletion(t *testing.T) {
+	items := newItems("[ ] one #docs", "[ ] two #done", "[ ] three #done #home", "[ ] four #do")
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.filter.Focus()
+	m.filter.SetValue("#home !#d")
+
+	if c := m.tagCompletions(); strings.Join(c, ",") != "done,do,docs" {
+		t.Errorf("expected the matching tags, most used first, but found %v", c)
+	}
+	if !strings.Contains(m.header(), "#done") {
+		t.Errorf("expected the completions under the filter prompt")
+	}
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = model.(tui)
+	if m.filter.Value() != "#home !#done " || !m.filter.Focused() {
+		t.Errorf("expected tab to complete the negated tag, but found %q", m.filter.Value())
+	}
+
+	// nothing to complete: tab leaves the prompt, as before
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = model.(tui)
+	if m.filter.Focused() || m.tagCompletions() != nil {
+		t.Errorf("expected tab without a completion to leave the prompt")
+	}
+}
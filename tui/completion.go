@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// completionCommand prints the completion script of a shell:
+// `tuido completion bash|zsh|fish`. The scripts complete the commands,
+// their flags and choices, and #tags, by calling `tuido __complete`.
+func completionCommand(args []string) int {
+	if len(args) != 1 || completionScripts[args[0]] == "" {
+		fmt.Fprintln(os.Stderr, "usage: tuido completion bash|zsh|fish")
+		return 2
+	}
+	fmt.Print(completionScripts[args[0]])
+	return 0
+}
+
+// completeCommand prints the completions of the last of args, the words
+// after `tuido` on a command line, one per line. It is run by the
+// completion scripts, and is not listed as a command.
+func completeCommand(args []string) int {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	for _, c := range completions(args, knownTags) {
+		fmt.Println(c)
+	}
+	return 0
+}
+
+// completions returns the completions of the last of words, the words of
+// a command line after `tuido`. tags is called for the known tags, only
+// if tags are to be completed. No completions leaves the shell to
+// complete paths.
+func completions(words []string, tags func() []string) []string {
+	words = skipFlags(flag.CommandLine, words)
+	if len(words) == 0 {
+		return nil // a flag's value
+	}
+	current := words[len(words)-1]
+	prev := ""
+	if len(words) > 1 {
+		prev = words[len(words)-2]
+	}
+
+	// the app's flags, and the command names, before any command
+	if len(words) == 1 {
+		if strings.HasPrefix(current, "-") {
+			return matching(flagNames(flag.CommandLine), current)
+		}
+		return matching(commandNames(), current)
+	}
+
+	c := findCommand(words[0])
+	if c == nil {
+		return nil
+	}
+	fs, run := c.flags()
+	if run != nil && strings.HasPrefix(current, "-") {
+		return matching(flagNames(fs), current)
+	}
+	if c.name == "list" && prev == "-tag" {
+		return matching(tags(), current)
+	}
+
+	sigil := runConfig.tagSigil
+	switch c.args {
+	case argText:
+		if strings.HasPrefix(current, sigil) {
+			return matching(prefixed(sigil, tags()), current)
+		}
+	case argTags:
+		// the tags renamed by retag, before its paths
+		if len(words) <= 3 {
+			return matching(tags(), current)
+		}
+	case argWords:
+		if len(words) == 2 {
+			return matching(commandWords[c.name], current)
+		}
+	}
+	return nil
+}
+
+// skipFlags returns words after their leading flags of fs, and the
+// flags' values, but for the last word, which is being completed. It
+// returns none if the last word is a flag's value.
+func skipFlags(fs *flag.FlagSet, words []string) []string {
+	for len(words) > 1 && strings.HasPrefix(words[0], "-") {
+		// flags given as -name=value, and unknown ones, take no value
+		f := fs.Lookup(strings.TrimLeft(words[0], "-"))
+		words = words[1:]
+		if f == nil {
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			continue
+		}
+		if len(words) == 1 {
+			return nil
+		}
+		words = words[1:]
+	}
+	return words
+}
+
+// flagNames returns the names of the flags of fs, each with its dash.
+func flagNames(fs *flag.FlagSet) []string {
+	names := []string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		names = append(names, "-"+f.Name)
+	})
+	return names
+}
+
+// matching returns the candidates which begin with prefix.
+func matching(candidates []string, prefix string) []string {
+	matched := []string{}
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+func prefixed(prefix string, names []string) []string {
+	words := []string{}
+	for _, name := range names {
+		words = append(words, prefix+name)
+	}
+	return words
+}
+
+// knownTags returns the names of the tags of the items of the working
+// directory, and of the global directory, sorted, by a quick scan: files
+// unchanged since they were indexed are not parsed again, and very large
+// trees are not scanned at all.
+func knownTags() []string {
+	root, err := os.Getwd()
+	if err != nil || isLargeScanRoot(root) {
+		return nil
+	}
+	configure(root)
+	if !*noCacheFlag {
+		itemCache = loadIndex(indexPath())
+	}
+	ws := workspace{root: root, dirs: []string{root}}
+	ws.files = ws.listFiles()
+
+	seen := map[string]bool{}
+	names := []string{}
+	for _, item := range ws.items() {
+		for _, tag := range item.Tags() {
+			if !seen[tag.Name()] {
+				seen[tag.Name()] = true
+				names = append(names, tag.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completionScripts are the completion scripts, by shell.
+var completionScripts = map[string]string{
+	"bash": `# tuido completion for bash: source <(tuido completion bash)
+_tuido() {
+	local IFS=$'\n'
+	COMPREPLY=($(tuido __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _tuido tuido
+`,
+	"zsh": `#compdef tuido
+# tuido completion for zsh: source <(tuido completion zsh), or save it as
+# _tuido in a directory of $fpath
+_tuido() {
+	local out
+	out=$(tuido __complete "${(@)words[2,CURRENT]}" 2>/dev/null)
+	if [[ -n $out ]]; then
+		compadd -- ${(f)out}
+	else
+		_files
+	fi
+}
+if [[ $funcstack[1] == _tuido ]]; then
+	_tuido "$@"
+else
+	compdef _tuido tuido
+fi
+`,
+	"fish": `# tuido completion for fish: tuido completion fish | source
+function __tuido_complete
+	set -l words (commandline -opc)[2..-1] (commandline -ct)
+	tuido __complete $words 2>/dev/null
+end
+complete -c tuido -a '(__tuido_complete)'
+`,
+}
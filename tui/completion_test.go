@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletions(t *testing.T) {
+	scanned := false
+	tags := func() []string {
+		scanned = true
+		return []string{"finance", "home", "house"}
+	}
+	complete := func(words ...string) string {
+		return strings.Join(completions(words, tags), ",")
+	}
+
+	if c := complete("re"); c != "retag,remind" {
+		t.Errorf("expected the commands beginning re, but found %s", c)
+	}
+	if c := complete("-no-s"); c != "-no-session" {
+		t.Errorf("expected the app's flags, but found %s", c)
+	}
+	if c := complete("export", "-f"); c != "-filter,-format" {
+		t.Errorf("expected the command's flags, but found %s", c)
+	}
+	if c := complete("completion", ""); c != "bash,zsh,fish" {
+		t.Errorf("expected the shells, but found %s", c)
+	}
+	if scanned {
+		t.Errorf("expected no scan for tags until tags are completed")
+	}
+
+	if c := complete("add", "fix", "the", "#ho"); c != "#home,#house" {
+		t.Errorf("expected the known tags, but found %s", c)
+	}
+	if c := complete("list", "-tag", "f"); c != "finance" {
+		t.Errorf("expected the tag names of -tag, but found %s", c)
+	}
+	if c := complete("retag", "home", "ho"); c != "home,house" {
+		t.Errorf("expected the tag names of retag, but found %s", c)
+	}
+	if c := complete("-dir", "notes", "-force", "re"); c != "retag,remind" {
+		t.Errorf("expected the commands after the app's flags, but found %s", c)
+	}
+	if c := complete("-dir", "no"); c != "" {
+		t.Errorf("expected a flag's value left to the shell, but found %s", c)
+	}
+	if c := complete("-force", "list", "-t"); c != "-tag" {
+		t.Errorf("expected the command's flags after the app's flags, but found %s", c)
+	}
+	if c := complete("list", "notes/"); c != "" {
+		t.Errorf("expected paths left to the shell, but found %s", c)
+	}
+}
@@ -4,13 +4,19 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/nilock/tuido/tuido"
 )
 
 type config struct {
 	// extensions is a collection of file extensions that will be parsed for items
 	//
-	// default value for extensions is ["xit", "md", "txt"].
+	// default value for extensions is ["xit", "md", "txt", "todo"].
 	extensions []string
 
 	// writeto is the location that items created in-app will be appended to.
@@ -19,11 +25,163 @@ type config struct {
 	//  - a file, which will have new items appended as new lines, or
 	//  - a directory, which will be written with YYYY-MM-DD.xit files for each day
 	writeto string
+
+	// inbox is the location that quickly captured items are appended to.
+	// Defaults to writeto.
+	inbox string
+
+	// archive is the location that archived (done) items are moved to.
+	// Defaults to the `archive` directory of the user's tuido directory.
+	archive string
+
+	// ics is a calendar file rewritten, when the app quits, with every
+	// item which has a due date. Disabled when empty.
+	ics string
+
+	// tagSigil is the prefix which marks tags in item text. Defaults to "#".
+	tagSigil string
+
+	// columns is the number of columns of items in the multi-column
+	// layout. 0 sizes columns automatically from the window width.
+	columns int
+
+	// newStatus is the status of items created in-app. Defaults to open.
+	newStatus tuido.Status
+	// newTags are tags added to items created in-app.
+	newTags []string
+
+	// tagColors are fixed colors (hex) for tags, by tag name. Tags
+	// without a fixed color are given a generated one.
+	tagColors map[string]string
+
+	// onChange is a shell command template run after each status change.
+	// Disabled when empty. Read from the user config file only.
+	onChange string
+
+	// skipDirs are the names of directories which are not scanned.
+	// Defaults to tuido.DefaultSkipDirs. An empty list scans every directory.
+	skipDirs []string
+
+	// dirs are the directories scanned when none are named by -dir or
+	// arguments. Read from the user config file only.
+	dirs []string
+
+	// githubRepos are the repos (eg "owner/repo") whose issues are imported
+	// by `tuido sync github`. Empty imports the issues of every repo.
+	githubRepos []string
+	// githubToken is the GitHub token of `tuido sync github`, unless
+	// $GITHUB_TOKEN is set. Read from the user config file only.
+	githubToken string
+
+	// global is the per-user directory scanned along with every project,
+	// wherever tuido is run from. Defaults to the writeto location, if it
+	// is a directory. Read from the user config file only.
+	global string
+
+	// maxDepth is the number of directory levels scanned, counting the
+	// scan root as the first. 0 is no limit.
+	maxDepth int
+
+	// maxTags caps the number of tags rendered per item. 0 is no limit.
+	maxTags int
+
+	// quietHours are the hours, eg `22-7`, in which `tuido remind` holds
+	// its notifications. Items override it with a `#quiet=` tag.
+	quietHours string
+
+	// stalled is the time after which an ongoing item is considered stalled.
+	stalled time.Duration
+
+	// stale is the age after which a pending item is dimmed. 0 is never.
+	stale time.Duration
+
+	// root is the directory that tuido was run from, and the root of the scan.
+	root string
+
+	// filterKey is the key which opens the filter prompt. Defaults to "/".
+	filterKey string
+
+	// views are saved filter expressions, by name, in the order saved.
+	views []savedView
+
+	// theme is the name of the built-in theme: dark (the default), light,
+	// mono, or plain. themeColors override its colors, by name. See themes.
+	theme       string
+	themeColors map[string]string
+
+	// sort is the name of the initial sort mode, eg "due". See sortMode.
+	sort string
+
+	// markers are additional status markers, eg "[>]", by marker.
+	markers map[string]tuido.Status
+
+	// statuses are additional statuses, eg blocked as "[?]", with their
+	// colors, views, and keys. See parseStatuses.
+	statuses []statusDef
+
+	// todoComments are comment keywords, eg "TODO", read as open items
+	// in source code files. None by default.
+	todoComments []string
+
+	// stamps are the dates written to items as they change: "completed",
+	// as they are checked or made obsolete, and "created", as they are
+	// created. Only "completed" by default.
+	stamps []string
+
+	// keys are navigation key bindings, by action. Actions without a
+	// binding keep their defaults. See defaultKeyMap.
+	keys map[string][]string
+
+	// chroma and lightness are the HCL parameters of generated #tag colors.
+	// Set via the -chroma and -lightness flags.
+	chroma    float64
+	lightness float64
+	// rainbow colors tags randomly, afresh on each run, rather than
+	// deriving each tag's color from its name. Set via -rainbow.
+	rainbow bool
 }
 
 func (cfg config) String() string {
-	return fmt.Sprintf("extensions=%s\nwriteto=%s\n",
-		strings.Join(cfg.extensions, ","), cfg.writeto)
+	return fmt.Sprintf("extensions=%s\nwriteto=%s\ninbox=%s\narchive=%s\nfilterkey=%s\nmaxtags=%d\n",
+		strings.Join(cfg.extensions, ","), cfg.writeto, cfg.inbox, cfg.archive, cfg.filterKey, cfg.maxTags)
+}
+
+// resolveTargets expands `~` and resolves relative write targets
+// against the scan root, and fills in defaults for unset targets.
+func (cfg *config) resolveTargets(root string) {
+	cfg.writeto = resolvePath(root, cfg.writeto)
+
+	if cfg.inbox == "" {
+		cfg.inbox = cfg.writeto
+	}
+	cfg.inbox = resolvePath(root, cfg.inbox)
+
+	if cfg.archive == "" {
+		cfg.archive = filepath.Join(appDir, "archive")
+	}
+	cfg.archive = resolvePath(root, cfg.archive)
+
+	if cfg.ics != "" {
+		cfg.ics = resolvePath(root, cfg.ics)
+	}
+
+	if cfg.global != "" {
+		cfg.global = resolvePath(root, cfg.global)
+	} else if stat, err := os.Stat(cfg.writeto); err == nil && stat.IsDir() {
+		cfg.global = cfg.writeto
+	}
+}
+
+func resolvePath(root, p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, p[1:])
+		}
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(root, p)
+	}
+	return filepath.Clean(p)
 }
 
 // runConfig is the initial, default values for the application configuration.
@@ -34,11 +192,39 @@ func (cfg config) String() string {
 // **all** values are overwritten in `loadFromDefaultConfigLocation()` via
 // `init()`, if a configuration file is found in the default location.
 var runConfig config = config{
-	extensions: []string{"xit", "md", "txt"},
+	extensions: tuido.DefaultExtensions,
 	writeto:    "~/.tuido",
+	filterKey:  "/",
+	tagSigil:   "#",
+	newStatus:  tuido.Open,
+	chroma:     0.9,
+	lightness:  0.85,
+	stalled:    14 * 24 * time.Hour,
+	skipDirs:   tuido.DefaultSkipDirs,
+	stamps:     []string{"completed"},
+}
+
+// findProjectConfig returns the nearest `.tuido` configuration file in
+// dir or its parents, or "" if there is none.
+func findProjectConfig(dir string) string {
+	for {
+		candidate := filepath.Join(dir, ".tuido")
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
 }
 
 func adoptConfigSettings(location string) {
+	if location == "" {
+		return
+	}
 	config := parseConfigIfExists(location)
 
 	if config != nil {
@@ -46,10 +232,195 @@ func adoptConfigSettings(location string) {
 		if config.writeto != "" {
 			runConfig.writeto = config.writeto
 		}
+		if config.inbox != "" {
+			runConfig.inbox = config.inbox
+		}
+		if config.archive != "" {
+			runConfig.archive = config.archive
+		}
+		if config.ics != "" {
+			runConfig.ics = config.ics
+		}
+		if config.quietHours != "" {
+			runConfig.quietHours = config.quietHours
+		}
+		if config.filterKey != "" {
+			runConfig.filterKey = config.filterKey
+		}
+		if config.maxTags != 0 {
+			runConfig.maxTags = config.maxTags
+		}
+		if config.maxDepth != 0 {
+			runConfig.maxDepth = config.maxDepth
+		}
+		if config.columns != 0 {
+			runConfig.columns = config.columns
+		}
+		if config.newStatus != "" {
+			runConfig.newStatus = config.newStatus
+		}
+		if len(config.newTags) != 0 {
+			runConfig.newTags = config.newTags
+		}
+		runConfig.tagColors = mergeTagColors(runConfig.tagColors, config.tagColors)
+		if config.theme != "" {
+			runConfig.theme = config.theme
+		}
+		runConfig.themeColors = mergeTagColors(runConfig.themeColors, config.themeColors)
+		if config.sort != "" {
+			runConfig.sort = config.sort
+		}
+		runConfig.markers = mergeMarkers(runConfig.markers, config.markers)
+		if config.statuses != nil {
+			runConfig.statuses = config.statuses
+		}
+		if config.stamps != nil {
+			runConfig.stamps = config.stamps
+		}
+		if config.todoComments != nil {
+			runConfig.todoComments = config.todoComments
+		}
+		if len(config.githubRepos) != 0 {
+			runConfig.githubRepos = config.githubRepos
+		}
+		runConfig.keys = mergeKeys(runConfig.keys, config.keys)
+		runConfig.views = mergeViews(runConfig.views, config.views)
+		if config.skipDirs != nil {
+			runConfig.skipDirs = config.skipDirs
+		}
+		// onchange is deliberately not adopted from project config: a
+		// checked-out .tuido file must not be able to run commands. Nor is
+		// githubtoken, a secret
+		if config.stalled != 0 {
+			runConfig.stalled = config.stalled
+		}
+		if config.stale != 0 {
+			runConfig.stale = config.stale
+		}
+		if config.tagSigil != "" {
+			runConfig.tagSigil = config.tagSigil
+		}
+	}
+}
+
+// configNames are the recognized config lines, by name.
+var configNames = map[string]bool{
+	"extensions": true, "writeto": true, "inbox": true, "archive": true,
+	"filterkey": true, "tagsigil": true, "stalled": true, "stale": true, "newstatus": true,
+	"newtags": true, "skipdirs": true, "tagcolors": true, "columns": true,
+	"maxtags": true, "maxdepth": true, "dirs": true, "markers": true, "statuses": true, "keys": true,
+	"todocomments": true, "theme": true, "themecolors": true,
+	"sort": true, "stamps": true, "global": true,
+	"github": true, "githubtoken": true, "ics": true,
+	"quiethours": true,
+}
+
+// parseMarkers reads a `c:status,c:status` list of marker characters,
+// eg `>:ongoing`. Malformed entries are skipped.
+func parseMarkers(s string) map[string]tuido.Status {
+	markers := map[string]tuido.Status{}
+	for _, pair := range strings.Split(s, ",") {
+		split := strings.SplitN(pair, ":", 2)
+		if len(split) != 2 || len(split[0]) != 1 || split[0] == "]" {
+			fmt.Fprintf(os.Stderr, "ignoring marker %s: expected a single character and a status\n", pair)
+			continue
+		}
+		status, err := tuido.ParseStatus(split[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ignoring marker %s: %s\n", pair, err)
+			continue
+		}
+		markers["["+split[0]+"]"] = status
+	}
+	return markers
+}
+
+// parseKeys reads an `action:key|key,action:key` list of key bindings.
+// Unknown actions are skipped.
+func parseKeys(s string) map[string][]string {
+	keys := map[string][]string{}
+	for _, pair := range strings.Split(s, ",") {
+		split := strings.SplitN(pair, ":", 2)
+		if len(split) != 2 || split[1] == "" {
+			fmt.Fprintf(os.Stderr, "ignoring key binding %s: expected action:key\n", pair)
+			continue
+		}
+		if !isAction(split[0]) {
+			fmt.Fprintf(os.Stderr, "ignoring key binding %s: unknown action %s\n", pair, split[0])
+			continue
+		}
+		keys[split[0]] = strings.Split(split[1], "|")
+	}
+	return keys
+}
+
+// mergeMarkers returns base with the markers of over layered on top.
+func mergeMarkers(base, over map[string]tuido.Status) map[string]tuido.Status {
+	if len(over) == 0 {
+		return base
+	}
+	merged := map[string]tuido.Status{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range over {
+		merged[k] = v
 	}
+	return merged
+}
+
+// mergeKeys returns base with the bindings of over layered on top.
+func mergeKeys(base, over map[string][]string) map[string][]string {
+	if len(over) == 0 {
+		return base
+	}
+	merged := map[string][]string{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range over {
+		merged[k] = v
+	}
+	return merged
+}
+
+// parseTagColors reads a `tag:#hex,tag:#hex` list. Malformed entries are
+// skipped.
+func parseTagColors(s string) map[string]string {
+	colors := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		split := strings.SplitN(pair, ":", 2)
+		if len(split) != 2 {
+			continue
+		}
+		if _, err := colorful.Hex(split[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "ignoring tag color %s: not a hex color\n", pair)
+			continue
+		}
+		colors[strings.TrimPrefix(split[0], runConfig.tagSigil)] = split[1]
+	}
+	return colors
+}
+
+// mergeTagColors returns base with the colors of over layered on top.
+func mergeTagColors(base, over map[string]string) map[string]string {
+	if len(over) == 0 {
+		return base
+	}
+	merged := map[string]string{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range over {
+		merged[k] = v
+	}
+	return merged
 }
 
 func parseConfigIfExists(configPath string) *config {
+	if info, err := os.Stat(configPath); err != nil || info.IsDir() {
+		return nil
+	}
 
 	if config, err := os.Open(configPath); err == nil {
 		cfg := parseConfig(config)
@@ -74,6 +445,17 @@ func parseConfig(file *os.File) config {
 	for scanner.Scan() {
 		line := scanner.Text()
 
+		// hook commands may themselves contain `=`
+		if strings.HasPrefix(line, "onchange=") {
+			cfg.onChange = strings.TrimPrefix(line, "onchange=")
+			continue
+		}
+		// as may view filters, eg `#due=2022-06-01`
+		if strings.HasPrefix(line, "views=") {
+			cfg.views = parseViews(strings.TrimPrefix(line, "views="))
+			continue
+		}
+
 		split := strings.Split(line, "=")
 
 		if len(split) == 2 { // all tuido config lines are of the form "flag=value[,value[,value...]]"
@@ -84,6 +466,138 @@ func parseConfig(file *os.File) config {
 			if split[0] == "writeto" {
 				cfg.writeto = split[1]
 			}
+			if split[0] == "inbox" {
+				cfg.inbox = split[1]
+			}
+			if split[0] == "global" {
+				cfg.global = split[1]
+			}
+			if split[0] == "github" && split[1] != "" {
+				cfg.githubRepos = strings.Split(split[1], ",")
+			}
+			if split[0] == "githubtoken" {
+				cfg.githubToken = split[1]
+			}
+			if split[0] == "archive" {
+				cfg.archive = split[1]
+			}
+			if split[0] == "ics" {
+				cfg.ics = split[1]
+			}
+			if split[0] == "filterkey" {
+				cfg.filterKey = split[1]
+			}
+			if split[0] == "tagsigil" && split[1] != "" {
+				cfg.tagSigil = split[1]
+			}
+			if split[0] == "quiethours" {
+				if _, _, err := parseQuietHours(split[1]); err == nil {
+					cfg.quietHours = split[1]
+				} else {
+					fmt.Fprintf(os.Stderr, "ignoring quiethours=%s: %s\n", split[1], err)
+				}
+			}
+			if split[0] == "stalled" {
+				if d := tuido.ToDuration(split[1]); d != nil {
+					cfg.stalled = *d
+				}
+			}
+			if split[0] == "stale" {
+				if d := tuido.ToDuration(split[1]); d != nil {
+					cfg.stale = *d
+				}
+			}
+			if split[0] == "newstatus" {
+				// custom statuses are set for the tuido package later, but
+				// those listed above can be named
+				s, err := tuido.ParseStatus(split[1])
+				if def, ok := cfg.status(tuido.Status(split[1])); err != nil && ok {
+					s, err = def.Name, nil
+				}
+				if err == nil {
+					cfg.newStatus = s
+				} else {
+					fmt.Fprintf(os.Stderr, "ignoring newstatus=%s: %s\n", split[1], err)
+				}
+			}
+			if split[0] == "newtags" {
+				cfg.newTags = strings.Split(split[1], ",")
+			}
+			if split[0] == "skipdirs" {
+				cfg.skipDirs = []string{}
+				if split[1] != "" {
+					cfg.skipDirs = strings.Split(split[1], ",")
+				}
+			}
+			if split[0] == "theme" {
+				if _, ok := themes[split[1]]; ok {
+					cfg.theme = split[1]
+				} else {
+					fmt.Fprintf(os.Stderr, "ignoring unknown theme %s: use dark, light, mono, or plain\n", split[1])
+				}
+			}
+			if split[0] == "sort" {
+				if _, ok := parseSortMode(split[1]); ok {
+					cfg.sort = split[1]
+				} else {
+					fmt.Fprintf(os.Stderr, "ignoring unknown sort %s: use %s\n", split[1], sortModeNames)
+				}
+			}
+			if split[0] == "themecolors" {
+				cfg.themeColors = parseThemeColors(split[1])
+			}
+			if split[0] == "tagcolors" {
+				cfg.tagColors = parseTagColors(split[1])
+			}
+			if split[0] == "columns" {
+				if n, err := strconv.Atoi(split[1]); err == nil && n >= 0 {
+					cfg.columns = n
+				}
+			}
+			if split[0] == "maxtags" {
+				if n, err := strconv.Atoi(split[1]); err == nil && n >= 0 {
+					cfg.maxTags = n
+				}
+			}
+			if split[0] == "dirs" && split[1] != "" {
+				cfg.dirs = strings.Split(split[1], ",")
+			}
+			if split[0] == "maxdepth" {
+				if n, err := strconv.Atoi(split[1]); err == nil && n >= 0 {
+					cfg.maxDepth = n
+				}
+			}
+			if split[0] == "markers" {
+				cfg.markers = parseMarkers(split[1])
+			}
+			if split[0] == "statuses" {
+				cfg.statuses = parseStatuses(split[1])
+			}
+			if split[0] == "keys" {
+				cfg.keys = parseKeys(split[1])
+			}
+			if split[0] == "todocomments" {
+				cfg.todoComments = []string{}
+				for _, keyword := range strings.Split(split[1], ",") {
+					if keyword = strings.TrimSpace(keyword); keyword != "" {
+						cfg.todoComments = append(cfg.todoComments, keyword)
+					}
+				}
+			}
+			if split[0] == "stamps" {
+				cfg.stamps = []string{}
+				for _, stamp := range strings.Split(split[1], ",") {
+					stamp = strings.TrimSpace(stamp)
+					if stamp == "completed" || stamp == "created" {
+						cfg.stamps = append(cfg.stamps, stamp)
+					} else if stamp != "" {
+						fmt.Fprintf(os.Stderr, "ignoring stamp %s: use completed, or created\n", stamp)
+					}
+				}
+			}
+			if !configNames[split[0]] {
+				fmt.Fprintf(os.Stderr, "ignoring unknown config %s\n", line)
+			}
 
 		} else {
 			// not a config line:
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// conflict is a change which was not written, as its item's line changed
+// on disk since the item was read. It is kept so that it can be forced.
+type conflict struct {
+	file  string
+	line  int
+	items []*tuido.Item
+	edit  func() error
+}
+
+// noteConflict keeps the change edit to items, if err reports that it
+// conflicts with a change on disk, for conflictPrompt.
+func (t *tui) noteConflict(items []*tuido.Item, edit func() error, err error) {
+	var c *tuido.ConflictError
+	if errors.As(err, &c) {
+		t.conflict = &conflict{c.File, c.Line, items, edit}
+	}
+}
+
+func (t tui) conflictPrompt() string {
+	return fmt.Sprintf("%s:%d has changed on disk since it was read. [r] - Re-scan the file,  [f] - Force the write,  [any key] - Cancel",
+		t.conflict.file, t.conflict.line)
+}
+
+// resolveConflict answers the conflict prompt: [r] re-reads the file, so
+// that the change can be made afresh, and [f] makes the change anyway,
+// over the line as it now is. Any other key drops the change.
+func (t *tui) resolveConflict(key string) {
+	c := t.conflict
+	t.conflict = nil
+	t.err = nil
+	switch key {
+	case "r":
+		t.reloadFile(c.file)
+		t.notice = "re-scanned " + c.file
+	case "f":
+		t.err = t.track(c.items, func() error { return tuido.Force(c.edit) })
+		t.conflict = nil // a line which is gone can't be forced
+		if t.err == nil {
+			t.notice = fmt.Sprintf("forced the write to %s:%d", c.file, c.line)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConflict(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] call mom\n"), 0644)
+	items, _ := getItems(file)
+
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	press := func(keys ...string) {
+		for _, k := range keys {
+			model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+			m = model.(tui)
+		}
+	}
+
+	// an edit in another program, then a status change here
+	os.WriteFile(file, []byte("[ ] call mom and dad\n"), 0644)
+	press("x")
+	if m.conflict == nil {
+		t.Fatalf("expected a conflict, but found err %v", m.err)
+	}
+	expectContent(t, file, "[ ] call mom and dad\n")
+
+	// re-scanning reads the other program's edit
+	press("r")
+	if m.conflict != nil || len(m.items) != 1 || m.items[0].Text() != "call mom and dad" {
+		t.Fatalf("expected the file re-scanned, but found %v", m.items)
+	}
+
+	// forcing writes over it
+	os.WriteFile(file, []byte("[@] call dad\n"), 0644)
+	press("x", "f")
+	if m.conflict != nil || m.err != nil {
+		t.Fatalf("expected the write forced, but found %v", m.err)
+	}
+	expectContent(t, file, "[x] call mom and dad #completed="+time.Now().Format("2006-01-02")+"\n")
+}
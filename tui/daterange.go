@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// dateRange is a preset window of completion dates for the done view.
+type dateRange int
+
+const (
+	anyTime dateRange = iota
+	today
+	thisWeek
+	thisMonth
+)
+
+func (r dateRange) String() string {
+	switch r {
+	case today:
+		return "today"
+	case thisWeek:
+		return "this week"
+	case thisMonth:
+		return "this month"
+	default:
+		return ""
+	}
+}
+
+// next cycles through the presets, returning to anyTime after thisMonth.
+func (r dateRange) next() dateRange {
+	return (r + 1) % (thisMonth + 1)
+}
+
+// since returns the beginning of the window, relative to now.
+func (r dateRange) since(now time.Time) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch r {
+	case today:
+		return midnight
+	case thisWeek:
+		// weeks begin on monday
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		return midnight.AddDate(0, 0, -daysSinceMonday)
+	case thisMonth:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Time{}
+	}
+}
+
+// contains reports whether the item was completed within the window.
+// Items without a completion date are only contained in anyTime.
+func (r dateRange) contains(item *tuido.Item, now time.Time) bool {
+	if r == anyTime {
+		return true
+	}
+
+	completed := item.Completed()
+	if completed == nil {
+		return false
+	}
+	return !completed.Before(r.since(now))
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// setDeleteMode asks for confirmation to delete the selected item.
+func (t *tui) setDeleteMode() {
+	current := t.currentSelection()
+	if current == nil {
+		return
+	}
+	if current.ReadOnly() {
+		t.err = fmt.Errorf("item is read-only - cannot delete %s", current.Location())
+		return
+	}
+	t.mode = deleting
+}
+
+func (t tui) deletePrompt() string {
+	return fmt.Sprintf("delete %q from %s? [y] - Delete,  [any key] - Cancel",
+		t.currentSelection().Text(), t.currentSelection().Location())
+}
+
+// deleteSelection removes the selected item's line from its file, and
+// the item from the list. The items after it in the file are moved up a
+// line in place, rather than re-read, so that they keep their marks and
+// undo history.
+func (t *tui) deleteSelection() error {
+	item := t.currentSelection()
+	if item == nil {
+		return nil
+	}
+	if err := tuido.Delete(item); err != nil {
+		return err
+	}
+
+	kept := []*tuido.Item{}
+	siblings := []*tuido.Item{}
+	for _, i := range t.items {
+		if i == item {
+			continue
+		}
+		kept = append(kept, i)
+		if i.File() == item.File() {
+			siblings = append(siblings, i)
+		}
+	}
+	t.items = kept
+	delete(t.marked, item)
+
+	tuido.Shift(siblings, item.File(), item.Line(), -1)
+	sort.SliceStable(siblings, func(a, b int) bool { return siblings[a].Line() < siblings[b].Line() })
+	tuido.Nest(siblings)
+
+	t.undo.push([]change{{item, deleted, ""}})
+	t.notice = fmt.Sprintf("deleted %s", item.Location())
+	t.populateRenderSelection()
+	return nil
+}
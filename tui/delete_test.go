@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeleteItem(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] first\n[ ] noise\n[ ] third\n"), 0644)
+	items, _ := getItems(file)
+
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.setSelection(1)
+
+	m.setDeleteMode()
+	if m.mode != deleting {
+		t.Fatalf("expected the delete prompt, but found mode %d", m.mode)
+	}
+	if err := m.deleteSelection(); err != nil {
+		t.Fatal(err)
+	}
+	expectContent(t, file, "[ ] first\n[ ] third\n")
+	if len(m.items) != 2 {
+		t.Errorf("expected the deleted item dropped from the list, but found %d items", len(m.items))
+	}
+
+	// the following item has moved up a line, and can still be written
+	third := items[2]
+	if third.Line() != 2 {
+		t.Errorf("expected the third item shifted to line 2, but found line %d", third.Line())
+	}
+	if err := third.SetText("third, still writable"); err != nil {
+		t.Fatal(err)
+	}
+	expectContent(t, file, "[ ] first\n[ ] third, still writable\n")
+
+	m.applyUndo(false)
+	if m.err != nil {
+		t.Fatal(m.err)
+	}
+	expectContent(t, file, "[ ] first\n[ ] noise\n[ ] third, still writable\n")
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorFinishedMsg reports the exit of an external editor, opened on file.
+type editorFinishedMsg struct {
+	file string
+	err  error
+}
+
+// editorArgs builds the arguments which open file at line, for the
+// editor named by the base name of its command. Editors not known to
+// take a line number are opened on the file alone.
+func editorArgs(editor, file string, line int) []string {
+	switch filepath.Base(editor) {
+	case "vi", "vim", "nvim", "nano", "emacs", "emacsclient", "micro", "kak":
+		return []string{fmt.Sprintf("+%d", line), file}
+	case "code", "codium", "code-insiders":
+		return []string{"-g", fmt.Sprintf("%s:%d", file, line)}
+	case "subl", "zed", "helix", "hx":
+		return []string{fmt.Sprintf("%s:%d", file, line)}
+	}
+	return []string{file}
+}
+
+// openInEditor suspends the app and opens the selected item in $VISUAL,
+// or else $EDITOR, or else vi. The item's file is re-read on return.
+func (t *tui) openInEditor() tea.Cmd {
+	current := t.currentSelection()
+	if current == nil {
+		return nil
+	}
+	if _, err := os.Stat(current.File()); err != nil { // eg, remote items
+		t.err = err
+		return nil
+	}
+
+	// the editor may carry its own arguments, eg `code --wait`
+	editor := strings.Fields(os.Getenv("VISUAL"))
+	if len(editor) == 0 {
+		editor = strings.Fields(os.Getenv("EDITOR"))
+	}
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	args := append(editor[1:], editorArgs(editor[0], current.File(), current.Line())...)
+
+	file := current.File()
+	t.touch()
+	return tea.ExecProcess(exec.Command(editor[0], args...), func(err error) tea.Msg {
+		return editorFinishedMsg{file, err}
+	})
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// exportFormat serializes items to a file of the format's name.
+type exportFormat struct {
+	file   string
+	render func([]*tuido.Item) ([]byte, error)
+}
+
+// exportFormats are the export formats, by name.
+var exportFormats = map[string]exportFormat{
+	"md": {"tuido-export.md", func(items []*tuido.Item) ([]byte, error) {
+		return []byte(tuido.Markdown(items) + "\n"), nil
+	}},
+	"json": {"tuido-export.json", func(items []*tuido.Item) ([]byte, error) {
+		data, err := tuido.JSON(items)
+		return append(data, '\n'), err
+	}},
+	"csv": {"tuido-export.csv", tuido.CSV},
+	"ics": {"tuido-export.ics", tuido.ICS},
+}
+
+// exportKeys maps keys in export mode to the format they write.
+var exportKeys = map[string]exportFormat{
+	"m": exportFormats["md"],
+	"j": exportFormats["json"],
+	"c": exportFormats["csv"],
+	"i": exportFormats["ics"],
+}
+
+func (t *tui) setExportMode() {
+	if len(t.listed()) == 0 {
+		return
+	}
+	t.mode = exporting
+}
+
+func (t tui) exportPrompt() string {
+	return fmt.Sprintf("export %d items to %s as: [m] markdown, [j] json, [c] csv, [i] ics (items with due dates). [esc] - Cancel",
+		len(t.listed()), t.config.root)
+}
+
+// writeCalendar rewrites the `ics` config's calendar file with items,
+// if it is set.
+func writeCalendar(path string, items []*tuido.Item) error {
+	if path == "" {
+		return nil
+	}
+	data, err := tuido.ICS(items)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// exportListed writes the listed items, in the current tab and filter,
+// to the format's file in the root directory.
+func (t *tui) exportListed(format exportFormat) error {
+	data, err := format.render(t.listed())
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(t.config.root, format.file)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	t.notice = fmt.Sprintf("exported %d items to %s", len(t.listed()), path)
+	return nil
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportListed(t *testing.T) {
+	items := newItems("[ ] fix the parser #bug", "[@] write docs", "[x] ship it #bug")
+
+	tui := newTUI(items, runConfig)
+	tui.config.root = t.TempDir()
+	tui.filter.SetValue("#bug")
+	tui.populateRenderSelection()
+
+	if err := tui.exportListed(exportKeys["m"]); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tui.config.root, "tuido-export.md"))
+	if expected := "- [ ] fix the parser #bug\n"; string(data) != expected {
+		t.Errorf("expected %q, but found %q", expected, data)
+	}
+
+	if err := tui.exportListed(exportKeys["j"]); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(filepath.Join(tui.config.root, "tuido-export.json"))
+	exported := []map[string]interface{}{}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 1 || exported[0]["text"] != "fix the parser #bug" || exported[0]["status"] != "open" {
+		t.Errorf("unexpected export %s", data)
+	}
+
+	if err := tui.exportListed(exportKeys["c"]); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(filepath.Join(tui.config.root, "tuido-export.csv"))
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "fix the parser #bug") {
+		t.Errorf("unexpected export %q", data)
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tuido.ics")
+	if err := writeCalendar(path, newItems("[ ] pay rent #due=2022-03-01", "[ ] undated")); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "SUMMARY:pay rent #due=2022-03-01") || strings.Contains(string(data), "undated") {
+		t.Errorf("unexpected calendar %q", data)
+	}
+}
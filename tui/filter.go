@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"strings"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// highlightStyle marks the text of listed items matched by the filter's
+// plain words
+var highlightStyle lg.Style = lg.NewStyle().Reverse(true)
+
+// filterQuery is the parsed contents of the filter prompt.
+//
+// Tags in the prompt match items carrying any of them, or, with all set,
+// items carrying every one of them. Tags negated with a leading `!` or
+// `-` (eg, `!#wontfix`) exclude the items carrying them. Plain words match
+// items whose text contains every one of them, ignoring case. When the
+// prompt holds both, items must match both.
+//
+// A prompt written as an expression, with `and`, `or`, `not`, parentheses,
+// or `is:` and `due:` predicates, is matched as a tuido.Query instead.
+type filterQuery struct {
+	tags     []tagTerm
+	excluded []tagTerm
+	words    []string
+	all      bool
+
+	expr *tuido.Query
+}
+
+// tagTerm is a tag of the filter prompt.
+type tagTerm struct {
+	name string
+	// prefix is set for a tag still being typed - the last token of the
+	// prompt, not yet followed by a space - which matches any tag that
+	// begins with it. A tag followed by a space matches exactly: `#do `
+	// matches #do, but not #done.
+	prefix bool
+}
+
+func parseFilter(s string, all bool) filterQuery {
+	if tuido.IsQuery(s) {
+		expr := tuido.ParseQuery(s)
+		return filterQuery{words: expr.Words(), all: all, expr: &expr}
+	}
+	q := filterQuery{all: all}
+
+	tokens := strings.Split(s, " ")
+	for i, token := range tokens {
+		if token == "" || token == tuido.TagSigil {
+			continue
+		}
+		if negated := strings.TrimLeft(token, "!-"); negated != token {
+			if tuido.IsTagToken(negated) {
+				q.excluded = append(q.excluded, tagTerm{
+					name:   tuido.Tags(negated)[0].Name(),
+					prefix: i == len(tokens)-1,
+				})
+				continue
+			}
+			if negated == "" || negated == tuido.TagSigil {
+				continue // a negation still being typed
+			}
+		}
+		if tuido.IsTagToken(token) {
+			q.tags = append(q.tags, tagTerm{
+				name:   tuido.Tags(token)[0].Name(),
+				prefix: i == len(tokens)-1,
+			})
+			continue
+		}
+		q.words = append(q.words, strings.ToLower(token))
+	}
+
+	return q
+}
+
+func (q filterQuery) empty() bool {
+	return q.expr == nil && len(q.tags) == 0 && len(q.excluded) == 0 && len(q.words) == 0
+}
+
+func (q filterQuery) matches(item *tuido.Item) bool {
+	if q.expr != nil {
+		return q.expr.Match(item)
+	}
+	for _, term := range q.excluded {
+		if term.matchesAny(item.Tags()) {
+			return false
+		}
+	}
+	return q.matchesTags(item) && q.matchesWords(item)
+}
+
+func (q filterQuery) matchesTags(item *tuido.Item) bool {
+	if len(q.tags) == 0 {
+		return true
+	}
+	for _, term := range q.tags {
+		matched := term.matchesAny(item.Tags())
+		if matched && !q.all {
+			return true
+		}
+		if !matched && q.all {
+			return false
+		}
+	}
+	return q.all
+}
+
+func (term tagTerm) matchesAny(tags []tuido.Tag) bool {
+	for _, tag := range tags {
+		if term.matches(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (term tagTerm) matches(tag tuido.Tag) bool {
+	if term.prefix {
+		return strings.HasPrefix(tag.Name(), term.name)
+	}
+	return tag.Name() == term.name
+}
+
+func (q filterQuery) matchesWords(item *tuido.Item) bool {
+	text := strings.ToLower(stripTags(item.Text()))
+	for _, w := range q.words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// highlightWords marks each match of words in s with mark, ignoring case.
+// Matches within the tokens of tags are left as they are, to be styled as
+// tags.
+func highlightWords(s string, words []string, tags []tuido.Tag, mark func(...string) string) string {
+	lower := strings.ToLower(s)
+	if len(words) == 0 || len(lower) != len(s) {
+		return s
+	}
+
+	// the byte ranges that are matched, and those that belong to tags
+	matched := make([]bool, len(s))
+	inTag := make([]bool, len(s))
+	for _, tag := range tags {
+		for from := 0; ; {
+			i := strings.Index(s[from:], tag.Token())
+			if i < 0 {
+				break
+			}
+			for j := from + i; j < from+i+len(tag.Token()); j++ {
+				inTag[j] = true
+			}
+			from += i + len(tag.Token())
+		}
+	}
+	for _, w := range words {
+		for from := 0; ; {
+			i := strings.Index(lower[from:], w)
+			if i < 0 {
+				break
+			}
+			for j := from + i; j < from+i+len(w); j++ {
+				matched[j] = !inTag[j]
+			}
+			from += i + len(w)
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		j := i
+		for j < len(s) && matched[j] == matched[i] {
+			j++
+		}
+		if matched[i] {
+			b.WriteString(mark(s[i:j]))
+		} else {
+			b.WriteString(s[i:j])
+		}
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// filterItems lists the items passing the filter prompt value.
+func filterItems(items []*tuido.Item, filter string, all bool) []string {
+	tui := newTUI(items, runConfig)
+	tui.filterAll = all
+	tui.filter.SetValue(filter)
+	tui.populateRenderSelection()
+
+	found := []string{}
+	for _, item := range tui.renderSelection {
+		found = append(found, item.Text())
+	}
+	sort.Strings(found)
+	return found
+}
+
+func newItems(raws ...string) []*tuido.Item {
+	items := []*tuido.Item{}
+	for n, raw := range raws {
+		item := tuido.New("todo.xit", n+1, raw)
+		items = append(items, &item)
+	}
+	return items
+}
+
+func expectFiltered(t *testing.T, items []*tuido.Item, all bool, tests map[string][]int) {
+	t.Helper()
+	for filter, expected := range tests {
+		wanted := []string{}
+		for _, i := range expected {
+			wanted = append(wanted, items[i].Text())
+		}
+		sort.Strings(wanted)
+
+		if found := filterItems(items, filter, all); strings.Join(found, "|") != strings.Join(wanted, "|") {
+			t.Errorf("filter %q: expected %v, but found %v", filter, wanted, found)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	items := newItems(
+		"[ ] refactor the parser #bug",
+		"[ ] Refactor the view #ui",
+		"[ ] fix the parser #bug #urgent",
+		"[ ] write docs about #bug triage",
+	)
+
+	expectFiltered(t, items, false, map[string][]int{
+		"":                {0, 1, 2, 3},
+		"#":               {0, 1, 2, 3},
+		"refactor":        {0, 1},
+		"REFACTOR view":   {1},
+		"#bug":            {0, 2, 3},
+		"#bug parser":     {0, 2},
+		"parser #bug fix": {2},
+		"bug":             {},
+	})
+}
+
+func TestFilterCompletedTags(t *testing.T) {
+	items := newItems(
+		"[ ] one #do",
+		"[ ] two #done",
+		"[ ] three #documentation",
+		"[ ] four #ui",
+	)
+
+	expectFiltered(t, items, false, map[string][]int{
+		"#do":      {0, 1, 2},
+		"#do ":     {0},
+		"#do #u":   {0, 3},
+		"#do #ui ": {0, 3},
+		"#don":     {1},
+		"#don ":    {},
+	})
+}
+
+func TestFilterExpressions(t *testing.T) {
+	items := newItems(
+		"[ ] one #work #urgent",
+		"[@] two #work",
+		"[ ] three #home #someday",
+		"[x] four #home",
+	)
+
+	// an expression ignores the any / all setting
+	for _, all := range []bool{false, true} {
+		expectFiltered(t, items, all, map[string][]int{
+			"#work and #urgent ":              {0},
+			"#work or #home not is:done":      {0, 1, 2},
+			"not #someday is:pending":         {0, 1},
+			"is:ongoing or (#home and three)": {1, 2},
+			"is:nonsense":                     {},
+		})
+	}
+}
+
+func TestFilterAllTags(t *testing.T) {
+	items := newItems(
+		"[ ] one #bug",
+		"[ ] two #bug #urgent",
+		"[ ] three #urgent",
+		"[ ] four #bug #urgently parser",
+	)
+
+	expectFiltered(t, items, false, map[string][]int{
+		"#bug #urgent ": {0, 1, 2, 3},
+	})
+	expectFiltered(t, items, true, map[string][]int{
+		"#bug #urgent ":      {1},
+		"#bug #urg":          {1, 3},
+		"parser #bug #urg":   {3},
+		"#bug":               {0, 1, 3},
+		"#bug #nope #urgent": {},
+	})
+}
+
+func TestFilterExcludedTags(t *testing.T) {
+	items := newItems(
+		"[ ] one #frontend",
+		"[ ] two #frontend #wontfix",
+		"[ ] three #backend",
+		"[ ] four #spam",
+	)
+
+	expectFiltered(t, items, false, map[string][]int{
+		"#frontend !#wontfix":  {0},
+		"#frontend -#wontfix ": {0},
+		"!#spam":               {0, 1, 2},
+		"!#spam !#wont":        {0, 2},
+		"#frontend !":          {0, 1},
+		"#frontend !#":         {0, 1},
+	})
+}
+
+func TestHighlightWords(t *testing.T) {
+	item := tuido.New("todo.xit", 1, "[ ] Fix the parser, then the #parser tests")
+	body := item.String()[4:]
+
+	expected := "[Fix] the [parser], then the #parser tests"
+	mark := func(s ...string) string { return "[" + strings.Join(s, "") + "]" }
+	if found := highlightWords(body, []string{"fix", "parser"}, item.Tags(), mark); found != expected {
+		t.Errorf("expected %q, but found %q", expected, found)
+	}
+}
@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	chromaFlag    = flag.Float64("chroma", 0.9, "chroma (0-1) of generated #tag colors")
+	lightnessFlag = flag.Float64("lightness", 0.85, "lightness (0-1) of generated #tag colors")
+	rainbowFlag   = flag.Bool("rainbow", false, "color #tags randomly on each run, rather than consistently by name")
+	jsonlFlag     = flag.Bool("jsonl", false, "print items to stdout as JSON lines, rather than launching the app")
+	captureFlag   = flag.String("a", "", "append this item to the inbox, and print it, rather than launching the app")
+
+	writetoFlag = flag.String("writeto", "", "file or directory that new items are written to")
+	inboxFlag   = flag.String("inbox", "", "file or directory that captured items are written to (default: writeto)")
+	archiveFlag = flag.String("archive", "", "file or directory that archived items are moved to")
+
+	errorsFlag = flag.Bool("errors", false, "print a report of files skipped while scanning, rather than launching the app")
+
+	watchFlag = flag.Bool("w", false, "watch scanned files, and reload items as the files change on disk")
+
+	readOnlyFlag = flag.Bool("read-only", false, "never write to scanned files: items can be browsed and filtered, but not changed")
+
+	noCacheFlag = flag.Bool("no-cache", false, "parse every file afresh, rather than reusing the index of unchanged files from the last run")
+
+	noSessionFlag = flag.Bool("no-session", false, "start with the default filter, sort order, and tab, rather than those left by the last run in this directory")
+
+	plainFlag = flag.Bool("plain", false, "plain rendering for screen readers and simple terminals: statuses as words, no colors or box drawing, one item per line. The same as the plain theme")
+
+	noMouseFlag = flag.Bool("no-mouse", false, "leave the mouse to the terminal, eg to select text, rather than clicking and scrolling items")
+
+	forceFlag = flag.Bool("force", false, "scan without confirmation, even from / or the home directory")
+
+	sortFlag = flag.String("sort", "", "the initial sort order: "+sortModeNames+" (default: the sort config, or importance)")
+
+	maxDepthFlag = flag.Int("max-depth", -1, "scan directories at most this many levels below each scan root. 1 scans only the root's own files, 0 is no limit (default: the maxdepth config)")
+
+	dirFlag  = dirList{}
+	extFlag  = extList{}
+	onlyFlag = extList{}
+
+	devFlag = flag.Bool("dev", false, "also parse items from .go files (tuido development). Also enabled by TUIDO_DEV=1")
+)
+
+func init() {
+	flag.Var(&dirFlag, "dir", "directory to scan (default: the working directory). May be repeated. sftp://[user@]host[:port]/path scans a remote directory, read-only")
+	flag.Var(&extFlag, "ext", "comma separated file extensions to parse, in addition to the defaults. May be repeated")
+	flag.Var(&onlyFlag, "only", "comma separated file extensions to parse, replacing the defaults. May be repeated")
+}
+
+// extList is a repeatable flag of comma separated file extensions.
+// Extensions are normalized to lower case, without a leading dot.
+type extList []string
+
+func (l *extList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *extList) Set(v string) error {
+	for _, ext := range strings.Split(v, ",") {
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		if ext == "" {
+			return fmt.Errorf("empty extension in %q", v)
+		}
+		*l = append(*l, ext)
+	}
+	return nil
+}
+
+// dirList is a repeatable flag of directories to scan.
+type dirList []string
+
+func (l *dirList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *dirList) Set(v string) error {
+	if v == "" {
+		return fmt.Errorf("empty directory")
+	}
+	*l = append(*l, v)
+	return nil
+}
+
+// split separates the remote (sftp://) directories of the list from the
+// local ones.
+func (l dirList) split() (remote, local []string) {
+	for _, dir := range l {
+		if isRemote(dir) {
+			remote = append(remote, dir)
+		} else {
+			local = append(local, dir)
+		}
+	}
+	return remote, local
+}
+
+// adoptFlagSettings applies command line flags over the runConfig.
+// Flags take precedence over all configuration files.
+func adoptFlagSettings() {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
+	runConfig.chroma = unitInterval("chroma", *chromaFlag)
+	runConfig.lightness = unitInterval("lightness", *lightnessFlag)
+	runConfig.rainbow = *rainbowFlag
+
+	if *writetoFlag != "" {
+		runConfig.writeto = *writetoFlag
+	}
+	if *inboxFlag != "" {
+		runConfig.inbox = *inboxFlag
+	}
+	if *archiveFlag != "" {
+		runConfig.archive = *archiveFlag
+	}
+
+	if *sortFlag != "" {
+		if _, ok := parseSortMode(*sortFlag); ok {
+			runConfig.sort = *sortFlag
+		} else {
+			fmt.Fprintf(os.Stderr, "ignoring unknown -sort %s: use %s\n", *sortFlag, sortModeNames)
+		}
+	}
+
+	if *maxDepthFlag >= 0 {
+		runConfig.maxDepth = *maxDepthFlag
+	}
+
+	if len(onlyFlag) != 0 {
+		runConfig.extensions = onlyFlag
+	}
+	runConfig.extensions = append(runConfig.extensions, extFlag...)
+
+	if *devFlag || os.Getenv("TUIDO_DEV") != "" {
+		runConfig.extensions = append(runConfig.extensions, "go")
+	}
+}
+
+// unitInterval clamps v into [0, 1], warning if it was out of range.
+func unitInterval(name string, v float64) float64 {
+	if v < 0 || v > 1 {
+		clamped := min64(max64(v, 0), 1)
+		fmt.Fprintf(os.Stderr, "-%s must be between 0 and 1; using %.2f\n", name, clamped)
+		return clamped
+	}
+	return v
+}
+
+func min64(a, b float64) float64 {
+	if a <= b {
+		return a
+	}
+	return b
+}
+func max64(a, b float64) float64 {
+	if a >= b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// focusKeys maps keys in focus mode to the status they apply.
+var focusKeys = map[string]tuido.Status{
+	" ": tuido.Open,
+	"a": tuido.Ongoing,
+	"@": tuido.Ongoing,
+	"R": tuido.Review,
+	"x": tuido.Checked,
+	"s": tuido.Obsolete,
+	"-": tuido.Obsolete,
+	"~": tuido.Obsolete,
+}
+
+// updateFocus processes keystrokes while in focus mode, where items are
+// triaged one at a time.
+func (t *tui) updateFocus(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "esc", "f", "q":
+		t.mode = navigation
+		return
+	case "up", "k":
+		t.setSelection(t.selection - 1)
+		return
+	case "down", "j":
+		t.setSelection(t.selection + 1)
+		return
+	}
+
+	s, ok := focusKeys[msg.String()]
+	if !ok {
+		s, ok = t.config.statusKey(msg.String())
+	}
+	if !ok || t.onCollapsedGroup(t.selection) {
+		return
+	}
+
+	current := t.currentSelection()
+	t.setStatus(s)
+	t.populateRenderSelection()
+
+	// advance to the next item. Items which left the view have
+	// already been replaced at the current selection.
+	for i, item := range t.renderSelection {
+		if item == current {
+			t.setSelection(i + 1)
+		}
+	}
+}
+
+func (t tui) focusView() string {
+	current := t.currentSelection()
+	if current == nil {
+		return lg.Place(t.w, t.h, lg.Center, lg.Center, "all clear!\n\n[esc] - Return to list view")
+	}
+
+	card := lg.NewStyle().
+		Bold(true).
+		Padding(2, 4).
+		Border(themeBorder(lg.RoundedBorder())).
+		Width(min(t.w-4, 72)).
+		Render(t.renderTuido(*current, min(t.w-4, 72)-8))
+
+	progress := lg.NewStyle().Faint(true).
+		Render(fmt.Sprintf("%d of %d", t.selection+1, len(t.renderSelection)))
+	controls := lg.NewStyle().Faint(true).
+		Render("x: done  s: obsolete  a: ongoing  R: review  [space]: open  [up]/[down]: skip  [esc]: exit")
+
+	return lg.Place(t.w, t.h, lg.Center, lg.Center,
+		lg.JoinVertical(lg.Center, progress, card, controls),
+	)
+}
@@ -0,0 +1,271 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// githubAPI is the root of the GitHub REST API.
+var githubAPI = "https://api.github.com"
+
+// githubIssue is an issue, as listed by the GitHub API.
+type githubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	// PullRequest is set for pull requests, which are listed as issues too
+	PullRequest *struct{} `json:"pull_request"`
+	Repository  struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// githubRef is an issue, by its repository (eg "owner/repo") and number.
+type githubRef struct {
+	repo   string
+	number int
+}
+
+func (r githubRef) String() string {
+	return fmt.Sprintf("%s#%d", r.repo, r.number)
+}
+
+// githubClient calls the GitHub API with a token.
+type githubClient struct {
+	api   string
+	token string
+	http  *http.Client
+}
+
+func newGithubClient(token string) githubClient {
+	return githubClient{api: githubAPI, token: token, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// call sends a request to the API, and decodes its JSON response into
+// result, if non-nil.
+func (c githubClient) call(method, path string, body, result interface{}) error {
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, c.api+path, &payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("github: %s %s: %s", method, path, resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// assignedIssues lists the open issues, not pull requests, assigned to
+// the token's user.
+func (c githubClient) assignedIssues() ([]githubIssue, error) {
+	issues := []githubIssue{}
+	for page := 1; ; page++ {
+		batch := []githubIssue{}
+		if err := c.call("GET", fmt.Sprintf("/issues?filter=assigned&state=open&per_page=100&page=%d", page), nil, &batch); err != nil {
+			return nil, err
+		}
+		for _, issue := range batch {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+		if len(batch) < 100 {
+			return issues, nil
+		}
+	}
+}
+
+// issueState returns the state of an issue: "open" or "closed".
+func (c githubClient) issueState(ref githubRef) (string, error) {
+	issue := githubIssue{}
+	err := c.call("GET", fmt.Sprintf("/repos/%s/issues/%d", ref.repo, ref.number), nil, &issue)
+	return issue.State, err
+}
+
+func (c githubClient) closeIssue(ref githubRef) error {
+	return c.call("PATCH", fmt.Sprintf("/repos/%s/issues/%d", ref.repo, ref.number), map[string]string{"state": "closed"}, nil)
+}
+
+// issueOf returns the issue an item was imported from, from its #gh-123
+// and #repo=owner/repo tags.
+func issueOf(item *tuido.Item) (githubRef, bool) {
+	ref := githubRef{}
+	for _, tag := range item.Tags() {
+		if n, err := strconv.Atoi(strings.TrimPrefix(tag.Name(), "gh-")); err == nil && strings.HasPrefix(tag.Name(), "gh-") {
+			ref.number = n
+		}
+		if tag.Name() == "repo" {
+			ref.repo = tag.Value()
+		}
+	}
+	return ref, ref.number > 0 && ref.repo != ""
+}
+
+// syncGithub brings items and the GitHub issues assigned to the user in
+// step, reporting each change to out. Open issues without an item are
+// imported, as open items appended to target, tagged #gh-123 and
+// #repo=owner/repo. The issues of checked or obsolete items are closed,
+// and pending items whose issue was closed are checked. Only issues of
+// repos are imported, or of any repo if repos is empty.
+func syncGithub(c githubClient, items []*tuido.Item, repos []string, target string, out io.Writer) error {
+	issues, err := c.assignedIssues()
+	if err != nil {
+		return err
+	}
+	open := map[githubRef]githubIssue{}
+	for _, issue := range issues {
+		repo := issue.Repository.FullName
+		if len(repos) == 0 || contains(repos, repo) {
+			open[githubRef{repo, issue.Number}] = issue
+		}
+	}
+
+	var firstErr error
+	fail := func(err error) {
+		fmt.Fprintln(out, err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	imported := map[githubRef]bool{}
+	for _, item := range items {
+		ref, ok := issueOf(item)
+		if !ok {
+			continue
+		}
+		imported[ref] = true
+		finished := item.Satus().Finished()
+		_, isOpen := open[ref]
+
+		switch {
+		case finished && isOpen:
+			if err := c.closeIssue(ref); err != nil {
+				fail(err)
+				continue
+			}
+			fmt.Fprintf(out, "closed %s  %s  %s\n", ref, item.String(), item.Location())
+		case !finished && !isOpen:
+			state, err := c.issueState(ref)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			if state != "closed" {
+				continue // open, but no longer assigned, or of another repo
+			}
+			if err := item.SetStatus(tuido.Checked); err != nil {
+				fail(err)
+				continue
+			}
+			fmt.Fprintf(out, "checked %s  %s  %s\n", ref, item.String(), item.Location())
+		}
+	}
+
+	refs := []githubRef{}
+	for ref := range open {
+		if !imported[ref] {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].repo != refs[j].repo {
+			return refs[i].repo < refs[j].repo
+		}
+		return refs[i].number < refs[j].number
+	})
+	for _, ref := range refs {
+		text := fmt.Sprintf("%s %sgh-%d %srepo=%s", open[ref].Title, tuido.TagSigil, ref.number, tuido.TagSigil, ref.repo)
+		item, err := tuido.Create(target, tuido.Open, text)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		fmt.Fprintf(out, "imported %s  %s  %s\n", ref, item.String(), item.Location())
+	}
+	return firstErr
+}
+
+func contains(list []string, s string) bool {
+	for _, entry := range list {
+		if entry == s {
+			return true
+		}
+	}
+	return false
+}
+
+// githubToken returns the GitHub token, from $GITHUB_TOKEN, or else the
+// githubtoken config.
+func githubToken() string {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	return runConfig.githubToken
+}
+
+// syncCommand brings items in step with an issue tracker:
+// `tuido sync github [path...]`. See syncGithub.
+func syncCommand(args []string) int {
+	if len(args) == 0 || args[0] != "github" {
+		fmt.Fprintln(os.Stderr, "usage: tuido sync github [path...]")
+		return 2
+	}
+
+	ws := openWorkspace(args[1:])
+	defer ws.close()
+	token := githubToken()
+	if token == "" {
+		fmt.Fprintln(os.Stderr, "tuido sync github needs a token: set $GITHUB_TOKEN, or githubtoken in tuido.conf")
+		return 2
+	}
+
+	// items imported earlier are found wherever they were imported to,
+	// even if the inbox is not scanned
+	items := ws.items()
+	scanned := map[string]bool{}
+	for _, file := range ws.files {
+		scanned[file] = true
+	}
+	inbox := []string{runConfig.inbox}
+	if info, err := os.Stat(runConfig.inbox); err == nil && info.IsDir() {
+		inbox = getFiles(runConfig.inbox, runConfig.extensions)
+	}
+	for _, file := range inbox {
+		if !scanned[file] {
+			more, _ := getItems(file)
+			items = append(items, more...)
+		}
+	}
+
+	if err := syncGithub(newGithubClient(token), items, runConfig.githubRepos, runConfig.inbox, os.Stdout); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSyncGithub(t *testing.T) {
+	closed := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/issues":
+			w.Write([]byte(`[
+				{"number": 1, "title": "fix login", "state": "open", "repository": {"full_name": "me/app"}},
+				{"number": 2, "title": "done here", "state": "open", "repository": {"full_name": "me/app"}},
+				{"number": 3, "title": "a pull request", "state": "open", "pull_request": {}, "repository": {"full_name": "me/app"}},
+				{"number": 4, "title": "elsewhere", "state": "open", "repository": {"full_name": "other/lib"}}
+			]`))
+		case r.Method == "PATCH":
+			body := map[string]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			closed = append(closed, r.URL.Path+" "+body["state"])
+			w.Write([]byte(`{}`))
+		case r.URL.Path == "/repos/me/app/issues/5":
+			w.Write([]byte(`{"number": 5, "state": "closed"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "work.xit")
+	os.WriteFile(file, []byte("[x] done here #gh-2 #repo=me/app\n[ ] closed upstream #gh-5 #repo=me/app\n"), 0644)
+	items, _ := getItems(file)
+	inbox := filepath.Join(dir, "inbox.xit")
+
+	c := newGithubClient("secret")
+	c.api = server.URL
+	out := bytes.Buffer{}
+	if err := syncGithub(c, items, []string{"me/app"}, inbox, &out); err != nil {
+		t.Fatalf("%s\n%s", err, out.String())
+	}
+
+	if strings.Join(closed, ",") != "/repos/me/app/issues/2 closed" {
+		t.Errorf("expected the checked item's issue closed, but found %v", closed)
+	}
+	if !strings.HasPrefix(items[1].Raw(), "[x] closed upstream") {
+		t.Errorf("expected the item of the closed issue checked, but found %q", items[1].Raw())
+	}
+	// the pull request, and the issue of another repo, are not imported
+	expectContent(t, inbox, "[ ] fix login #gh-1 #repo=me/app\n")
+
+	if !strings.Contains(out.String(), "imported me/app#1") || !strings.Contains(out.String(), "checked me/app#5") {
+		t.Errorf("unexpected report %q", out.String())
+	}
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"github.com/nilock/tuido/tuido"
+)
+
+// toggleProjectOnly switches the listed items between those of the
+// project alone, and of the project and the global directory.
+func (t *tui) toggleProjectOnly() {
+	if t.config.global == "" {
+		t.notice = "no global directory is configured"
+		return
+	}
+	t.projectOnly = !t.projectOnly
+	t.populateRenderSelection()
+}
+
+// applyProjectScope drops the items of the global directory while the
+// view is limited to the project. Items of a project run from within the
+// global directory are kept.
+func (t *tui) applyProjectScope() {
+	if !t.projectOnly || t.config.global == "" {
+		return
+	}
+
+	filtered := []*tuido.Item{}
+	for _, item := range t.renderSelection {
+		if !isUnder(item.File(), t.config.global) || isUnder(item.File(), t.config.root) {
+			filtered = append(filtered, item)
+		}
+	}
+	t.renderSelection = filtered
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestProjectOnly(t *testing.T) {
+	global, project := t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(global, "inbox.xit"), []byte("[ ] call mom\n"), 0644)
+	os.WriteFile(filepath.Join(project, "todo.xit"), []byte("[ ] fix the build\n"), 0644)
+	items := loadItems(append(getFiles(global, runConfig.extensions), getFiles(project, runConfig.extensions)...), nil)
+
+	cfg := runConfig
+	cfg.global, cfg.root = global, project
+	m := newTUI(items, cfg)
+	m.populateRenderSelection()
+	if len(m.renderSelection) != 2 {
+		t.Fatalf("expected the global and project items listed, but found %d", len(m.renderSelection))
+	}
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = model.(tui)
+	if len(m.renderSelection) != 1 || m.renderSelection[0].Text() != "fix the build" {
+		t.Errorf("expected only the project item listed, but found %v", m.renderSelection)
+	}
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// groupStyle is the header of a source file's group of items
+var groupStyle lg.Style = lg.NewStyle().Bold(true).Foreground(lg.Color("#87afd7"))
+
+// fileGroups is the group-by-file view: listed items are headed by their
+// source file, and a collapsed file's items are folded behind its
+// header.
+type fileGroups struct {
+	active bool
+	// collapsed files are listed by their header alone
+	collapsed map[string]bool
+	// counts are the listed item counts, by file
+	counts map[string]int
+	// folded are the listed items of collapsed files, by file
+	folded map[string][]*tuido.Item
+}
+
+// groupsActive reports whether the list is grouped by file. The agenda's
+// due date buckets take precedence.
+func (t tui) groupsActive() bool {
+	return t.groups.active && !t.agendaActive() && !t.board
+}
+
+// toggleGroups switches the group-by-file view on or off.
+func (t *tui) toggleGroups() {
+	t.groups.active = !t.groups.active
+	if t.groups.active {
+		t.agenda = false
+	}
+	t.populateRenderSelection()
+}
+
+// applyGroups orders the (sorted) render selection by file, keeping the
+// existing order within each file, and folds collapsed files into their
+// first item, which stands in for the group.
+func (t *tui) applyGroups() {
+	sort.SliceStable(t.renderSelection, func(i, j int) bool {
+		return t.renderSelection[i].File() < t.renderSelection[j].File()
+	})
+
+	t.groups.counts = map[string]int{}
+	t.groups.folded = map[string][]*tuido.Item{}
+	shown := []*tuido.Item{}
+	for _, item := range t.renderSelection {
+		file := item.File()
+		t.groups.counts[file]++
+		if t.groups.collapsed[file] {
+			if len(t.groups.folded[file]) == 0 {
+				shown = append(shown, item)
+			}
+			t.groups.folded[file] = append(t.groups.folded[file], item)
+			continue
+		}
+		shown = append(shown, item)
+	}
+	t.renderSelection = shown
+}
+
+// toggleCollapse collapses or expands the group of the selected item,
+// and keeps the selection on the group.
+func (t *tui) toggleCollapse() {
+	current := t.currentSelection()
+	if current == nil || !t.groupsActive() {
+		return
+	}
+	file := current.File()
+	if t.groups.collapsed == nil {
+		t.groups.collapsed = map[string]bool{}
+	}
+	t.groups.collapsed[file] = !t.groups.collapsed[file]
+	t.populateRenderSelection()
+
+	for i, item := range t.renderSelection {
+		if item.File() == file {
+			t.setSelection(i)
+			return
+		}
+	}
+}
+
+// groupSafeCommands are the navigation mode commands which act on a
+// collapsed group's stand-in item as they would on any other.
+var groupSafeCommands = map[string]bool{
+	"collapse": true, "groups": true, "help": true, "sort": true, "anyall": true, "agenda": true,
+	"dates": true, "layout": true, "donerange": true, "batch": true, "markdown": true, "summary": true,
+	"export": true, "columns": true, "prevcolumn": true, "nextcolumn": true, "ages": true,
+	"files": true, "browse": true, "legend": true, "colors": true, "palette": true, "board": true, "global": true, "new": true, "undo": true, "redo": true,
+}
+
+// onCollapsedGroup reports whether the i'th listed item is the stand-in
+// for a collapsed group, rather than an item of its own.
+func (t tui) onCollapsedGroup(i int) bool {
+	if !t.groupsActive() || i < 0 || i >= len(t.renderSelection) {
+		return false
+	}
+	return t.groups.collapsed[t.renderSelection[i].File()]
+}
+
+// listed returns the listed items, including those folded into
+// collapsed groups.
+func (t tui) listed() []*tuido.Item {
+	if !t.groupsActive() {
+		return t.renderSelection
+	}
+	items := []*tuido.Item{}
+	for i, item := range t.renderSelection {
+		if t.onCollapsedGroup(i) {
+			items = append(items, t.groups.folded[item.File()]...)
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// groupHeader returns the header to render above the i'th listed item,
+// or "" if the item is not the first of its file.
+func (t tui) groupHeader(i int) string {
+	file := t.renderSelection[i].File()
+	if i > 0 && t.renderSelection[i-1].File() == file {
+		return ""
+	}
+
+	name := file
+	if rel, err := filepath.Rel(t.config.root, file); err == nil {
+		name = rel
+	}
+	arrow := glyph("▾", "-")
+	if t.groups.collapsed[file] {
+		arrow = glyph("▸", "+")
+	}
+	return groupStyle.Render(fmt.Sprintf("%s %s (%d)", arrow, name, t.groups.counts[file]))
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestGroupsCollapse(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.xit"), filepath.Join(dir, "b.xit")
+	os.WriteFile(a, []byte("[ ] a one\n[ ] a two\n"), 0644)
+	os.WriteFile(b, []byte("[ ] b one\n"), 0644)
+
+	m := newTUI(loadItems([]string{b, a}, nil), runConfig)
+	m.toggleGroups()
+	if len(m.renderSelection) != 3 || m.renderSelection[0].File() != a {
+		t.Fatalf("expected items grouped by file, but found %v", m.renderSelection)
+	}
+
+	m.toggleCollapse()
+	if len(m.renderSelection) != 2 || len(m.listed()) != 3 {
+		t.Fatalf("expected a's group folded into one row of 2 listed, but found %d rows of %d", len(m.renderSelection), len(m.listed()))
+	}
+
+	// status keys on a collapsed group are refused, rather than changing
+	// the item standing in for it
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = model.(tui)
+	if m.renderSelection[0].Satus() != tuido.Open {
+		t.Errorf("expected the collapsed group's items to be unchanged")
+	}
+
+	m.toggleCollapse()
+	if len(m.renderSelection) != 3 {
+		t.Errorf("expected the group expanded again, but found %d rows", len(m.renderSelection))
+	}
+}
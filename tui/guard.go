@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isLargeScanRoot reports whether a recursive scan from root is likely to
+// walk an enormous tree: a filesystem root, or the user's home directory.
+func isLargeScanRoot(root string) bool {
+	root = filepath.Clean(root)
+
+	if filepath.Dir(root) == root {
+		return true
+	}
+	if home, err := os.UserHomeDir(); err == nil && filepath.Clean(home) == root {
+		return true
+	}
+	return false
+}
+
+// confirmScanRoot asks the user to confirm a scan of a very large tree,
+// unless -force was passed. It returns false if the scan should not proceed.
+func confirmScanRoot(root string) bool {
+	if *forceFlag || !isLargeScanRoot(root) {
+		return true
+	}
+
+	fmt.Printf("tuido: %s is a very large tree, and scanning it may take a long time.\n", root)
+	fmt.Print("Scan anyway? (pass -force to skip this check) [y/N] ")
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
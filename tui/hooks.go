@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+// hookFailedMsg reports an on-change hook command which exited with an
+// error.
+type hookFailedMsg struct {
+	err error
+}
+
+// hookCall is a pending run of the on-change hook, for one status change.
+type hookCall struct {
+	file   string
+	line   int
+	status tuido.Status
+}
+
+// queueHook schedules the on-change hook for a status change to item.
+// The hook is run after the change is written, when the update completes.
+func (t *tui) queueHook(item *tuido.Item) {
+	if t.config.onChange == "" {
+		return
+	}
+	t.hooks = append(t.hooks, hookCall{item.File(), item.Line(), item.Satus()})
+}
+
+// flushHooks returns a command running each queued hook, asynchronously.
+func (t *tui) flushHooks() tea.Cmd {
+	if len(t.hooks) == 0 {
+		return nil
+	}
+
+	cmds := []tea.Cmd{}
+	for _, h := range t.hooks {
+		cmds = append(cmds, runHook(t.config.onChange, h))
+	}
+	t.hooks = nil
+	return tea.Batch(cmds...)
+}
+
+// runHook fills the {file}, {line}, and {status} placeholders of the
+// command template, and runs it with `sh -c`. Substituted values are
+// single-quoted, so that they are passed as plain words.
+func runHook(template string, h hookCall) tea.Cmd {
+	command := strings.NewReplacer(
+		"{file}", shellQuote(h.file),
+		"{line}", strconv.Itoa(h.line),
+		"{status}", shellQuote(string(h.status)),
+	).Replace(template)
+
+	return func() tea.Msg {
+		out, err := exec.Command("sh", "-c", command).CombinedOutput()
+		if err != nil {
+			msg := strings.TrimSpace(string(out))
+			if msg == "" {
+				msg = err.Error()
+			}
+			return hookFailedMsg{fmt.Errorf("onchange hook failed: %s", msg)}
+		}
+		return nil
+	}
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -9,13 +9,14 @@ import (
 )
 
 func init() {
-	rand.Seed(time.Now().Unix()) // a fresh set of tag colors on each run. Spice of life.
+	rand.Seed(time.Now().Unix()) // a fresh set of tag colors on each -rainbow run. Spice of life.
 
 	home, err := os.UserHomeDir()
 	if err != nil {
 		fmt.Printf("error getting user home dir: %s", err)
 	}
 	tuidoDir := filepath.Join(home, ".tuido")
+	appDir = tuidoDir
 	runConfig.writeto = tuidoDir
 
 	loadFromDefaultConfigLocation()
@@ -29,16 +30,32 @@ func init() {
 				runConfig.writeto, err)
 		}
 	}
+	if appDir != runConfig.writeto {
+		os.MkdirAll(appDir, 0777)
+	}
 }
 
-func loadFromDefaultConfigLocation() {
+// appDir is the per-user tuido directory, home to app data (eg, sidecar
+// notes) regardless of the configured writeto.
+var appDir string
+
+// userConfigPath is the location of the per-user configuration file,
+// `tuido.conf` in the user's config directory.
+func userConfigPath() (string, error) {
 	cfgDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cfgDir, "tuido.conf"), nil
+}
+
+func loadFromDefaultConfigLocation() {
+	cfgPath, err := userConfigPath()
 	if err != nil {
 		fmt.Println("error seeking configdir")
 		return
 	}
 
-	cfgPath := filepath.Join(cfgDir, "tuido.conf")
 	cfg := parseConfigIfExists(cfgPath)
 
 	if cfg != nil {
@@ -48,5 +65,82 @@ func loadFromDefaultConfigLocation() {
 		if cfg.writeto != "" {
 			runConfig.writeto = cfg.writeto
 		}
+		if cfg.inbox != "" {
+			runConfig.inbox = cfg.inbox
+		}
+		if cfg.archive != "" {
+			runConfig.archive = cfg.archive
+		}
+		if cfg.ics != "" {
+			runConfig.ics = cfg.ics
+		}
+		if cfg.quietHours != "" {
+			runConfig.quietHours = cfg.quietHours
+		}
+		if cfg.filterKey != "" {
+			runConfig.filterKey = cfg.filterKey
+		}
+		if cfg.maxTags != 0 {
+			runConfig.maxTags = cfg.maxTags
+		}
+		if len(cfg.dirs) != 0 {
+			runConfig.dirs = cfg.dirs
+		}
+		if cfg.global != "" {
+			runConfig.global = cfg.global
+		}
+		if len(cfg.githubRepos) != 0 {
+			runConfig.githubRepos = cfg.githubRepos
+		}
+		if cfg.githubToken != "" {
+			runConfig.githubToken = cfg.githubToken
+		}
+		if cfg.maxDepth != 0 {
+			runConfig.maxDepth = cfg.maxDepth
+		}
+		if cfg.columns != 0 {
+			runConfig.columns = cfg.columns
+		}
+		if cfg.newStatus != "" {
+			runConfig.newStatus = cfg.newStatus
+		}
+		if len(cfg.newTags) != 0 {
+			runConfig.newTags = cfg.newTags
+		}
+		runConfig.tagColors = mergeTagColors(runConfig.tagColors, cfg.tagColors)
+		if cfg.theme != "" {
+			runConfig.theme = cfg.theme
+		}
+		runConfig.themeColors = mergeTagColors(runConfig.themeColors, cfg.themeColors)
+		if cfg.sort != "" {
+			runConfig.sort = cfg.sort
+		}
+		runConfig.markers = mergeMarkers(runConfig.markers, cfg.markers)
+		if cfg.statuses != nil {
+			runConfig.statuses = cfg.statuses
+		}
+		if cfg.stamps != nil {
+			runConfig.stamps = cfg.stamps
+		}
+		if cfg.todoComments != nil {
+			runConfig.todoComments = cfg.todoComments
+		}
+		runConfig.keys = mergeKeys(runConfig.keys, cfg.keys)
+		runConfig.views = mergeViews(runConfig.views, cfg.views)
+		if cfg.skipDirs != nil {
+			runConfig.skipDirs = cfg.skipDirs
+		}
+		if cfg.onChange != "" {
+			runConfig.onChange = cfg.onChange
+		}
+		if cfg.stalled != 0 {
+			runConfig.stalled = cfg.stalled
+		}
+		if cfg.stale != 0 {
+			runConfig.stale = cfg.stale
+		}
+		if cfg.tagSigil != "" {
+			runConfig.tagSigil = cfg.tagSigil
+		}
 	}
 }
@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyMap holds the user-configurable key bindings for navigation mode.
+type keyMap struct {
+	filter key.Binding
+
+	up       key.Binding
+	down     key.Binding
+	first    key.Binding
+	last     key.Binding
+	halfDown key.Binding
+	halfUp   key.Binding
+	pageDown key.Binding
+	pageUp   key.Binding
+	tab      key.Binding
+	quit     key.Binding
+
+	// commands are the bindings of the other navigation mode actions, by
+	// action name. See commandActions.
+	commands map[string]*key.Binding
+	// rebound are the actions given keys by the `keys` config.
+	rebound map[string]bool
+	// statuses are the custom statuses given keys by the `statuses`
+	// config, in order. See bindStatuses.
+	statuses []string
+}
+
+func defaultKeyMap() keyMap {
+	keys := keyMap{
+		filter: key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter todos by tag")),
+
+		up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("", "previous item")),
+		down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("", "next item")),
+		first:    key.NewBinding(key.WithKeys("g", "home"), key.WithHelp("", "first item")),
+		last:     key.NewBinding(key.WithKeys("G", "end"), key.WithHelp("", "last item")),
+		halfDown: key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("", "half page down")),
+		halfUp:   key.NewBinding(key.WithKeys("ctrl+u"), key.WithHelp("", "half page up")),
+		pageDown: key.NewBinding(key.WithKeys("pgdown", "]"), key.WithHelp("", "next page")),
+		pageUp:   key.NewBinding(key.WithKeys("pgup", "["), key.WithHelp("", "previous page")),
+		tab:      key.NewBinding(key.WithKeys("tab"), key.WithHelp("", "cycle between todo, done, and snoozed tabs")),
+		quit:     key.NewBinding(key.WithKeys("q"), key.WithHelp("", "quit")),
+
+		commands: map[string]*key.Binding{},
+		rebound:  map[string]bool{},
+	}
+	for _, c := range commandActions {
+		if c.name == "" {
+			continue
+		}
+		binding := key.NewBinding(key.WithKeys(c.keys...), key.WithHelp("", c.desc))
+		keys.commands[c.name] = &binding
+	}
+	return keys
+}
+
+// keyActions are the configurable navigation bindings, by their action
+// name in the `keys` config. The other actions are commandActions.
+var keyActions = map[string]func(*keyMap) *key.Binding{
+	"filter":   func(k *keyMap) *key.Binding { return &k.filter },
+	"up":       func(k *keyMap) *key.Binding { return &k.up },
+	"down":     func(k *keyMap) *key.Binding { return &k.down },
+	"first":    func(k *keyMap) *key.Binding { return &k.first },
+	"last":     func(k *keyMap) *key.Binding { return &k.last },
+	"halfdown": func(k *keyMap) *key.Binding { return &k.halfDown },
+	"halfup":   func(k *keyMap) *key.Binding { return &k.halfUp },
+	"pagedown": func(k *keyMap) *key.Binding { return &k.pageDown },
+	"pageup":   func(k *keyMap) *key.Binding { return &k.pageUp },
+	"tab":      func(k *keyMap) *key.Binding { return &k.tab },
+	"quit":     func(k *keyMap) *key.Binding { return &k.quit },
+}
+
+// commandAction is a navigation mode action, other than navigation
+// itself, with its default keys and its help.
+type commandAction struct {
+	name string
+	keys []string
+	desc string
+}
+
+// commandActions are the actions run from navigation mode, in the order
+// of the help screen. Unnamed entries separate groups of the help.
+var commandActions = []commandAction{
+	{"new", []string{"n"}, "new item"},
+	{"edit", []string{"e", "r"}, "edit item"},
+	{"editor", []string{"E"}, "open item in $EDITOR"},
+	{"note", []string{"N"}, "edit item note"},
+	{"tag", []string{"t"}, "add a tag"},
+	{"untag", []string{"T"}, "remove a tag"},
+	{"files", []string{"F"}, "cycle recent files"},
+	{"browse", []string{"b"}, "browse folders"},
+	{"global", []string{"P"}, "toggle project only / project + global items"},
+	{"layout", []string{"w"}, "cycle tag layout"},
+	{"colors", []string{"c"}, "reshuffle tag colors"},
+	{"legend", []string{"L"}, "tag legend - filter by a tag, or set its color"},
+	{"views", []string{"V"}, "saved views - apply or save a filter"},
+	{"palette", []string{"ctrl+p"}, "jump to an item (fuzzy finder)"},
+	{"ages", []string{"H"}, "toggle item ages"},
+	{"agenda", []string{"A"}, "group items by due date (agenda)\n   (in the done tab) archive listed items"},
+	{"groups", []string{"="}, "group items by file"},
+	{"collapse", []string{"C"}, "collapse / expand the selected item's file group"},
+	{"fold", []string{"+"}, "fold / unfold the selected item's subtasks"},
+	{"dates", []string{"d"}, "toggle relative / iso dates"},
+	{"stalled", []string{"O"}, "show only stalled ongoing items"},
+	{"columns", []string{"|"}, "toggle multiple columns"},
+	{"board", []string{"K"}, "toggle the board - a column per status"},
+	{"prevcolumn", []string{"left", "h"}, "previous column"},
+	{"nextcolumn", []string{"right", "l"}, "next column"},
+	{"markdown", []string{"M"}, "copy listed items as markdown"},
+	{"reference", []string{"y"}, "copy item reference"},
+	{"copy", []string{"Y"}, "copy item text"},
+	{"preview", []string{"i"}, "toggle a preview of the lines around the item"},
+	{"peek", []string{"enter"}, "view the item in its file"},
+	{"link", []string{"U"}, "open the item's link in the browser"},
+	{"delete", []string{"X"}, "delete item"},
+	{"moveup", []string{"ctrl+up"}, "move item up in its file"},
+	{"movedown", []string{"ctrl+down"}, "move item down in its file"},
+	{"export", []string{"ctrl+e"}, "export listed items to a file"},
+	{"summary", []string{"S"}, "summary of item counts"},
+	{"focus", []string{"f"}, "focus mode - triage items one at a time"},
+	{"snooze", []string{"z"}, "snooze item"},
+	{"snoozeuntil", []string{"Z"}, "snooze item until a date (empty date: wake it)"},
+	{"escalate", []string{"!"}, "escalate item"},
+	{"relax", []string{"1"}, "relax item"},
+	{"pomo", []string{"p"}, "begin a pomodoro"},
+	{},
+	{"done", []string{"x"}, "mark done"},
+	{"obsolete", []string{"s", "-", "~"}, "mark obsolete (strikethrough)"},
+	{"ongoing", []string{"a", "@"}, "mark ongoing (at)"},
+	{"review", []string{"R"}, "mark in review"},
+	{"open", []string{" "}, "mark open"},
+	{"mark", []string{"v"}, "mark item (status keys then apply to all marked items)"},
+	{"unmark", []string{"esc"}, "clear marks"},
+	{"batch", []string{"B"}, "set status of all listed items"},
+	{"undo", []string{"u"}, "undo change"},
+	{"redo", []string{"ctrl+r"}, "redo change"},
+	{},
+	{"donerange", []string{"D"}, "cycle done tab date range"},
+	{"anyall", []string{"&"}, "match any / all filter tags"},
+	{"sort", []string{"o"}, "cycle sort order"},
+	{"help", []string{"?"}, "enter help"},
+}
+
+// isAction reports whether name is a configurable action.
+func isAction(name string) bool {
+	if _, ok := keyActions[name]; ok {
+		return true
+	}
+	for _, c := range commandActions {
+		if c.name == name && name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// newKeyMap returns the default key bindings, overridden by any
+// bindings set in cfg.
+func newKeyMap(cfg config) keyMap {
+	keys := defaultKeyMap()
+
+	if cfg.filterKey != "" {
+		keys.filter.SetKeys(cfg.filterKey)
+		keys.filter.SetHelp(cfg.filterKey, keys.filter.Help().Desc)
+	}
+
+	for action, bound := range cfg.keys {
+		binding, ok := keys.commands[action]
+		if !ok {
+			binding = keyActions[action](&keys)
+		}
+		binding.SetKeys(bound...)
+		binding.SetHelp(bound[0], binding.Help().Desc)
+		keys.rebound[action] = true
+	}
+	keys.bindStatuses(cfg)
+
+	return keys
+}
+
+// command returns the name of the command action bound to msg, or "".
+// Actions rebound by the config are matched first, in order of name, so
+// that their keys take precedence over the defaults of other actions.
+func (k keyMap) command(msg tea.KeyMsg) string {
+	rebound := []string{}
+	for name := range k.rebound {
+		rebound = append(rebound, name)
+	}
+	sort.Strings(rebound)
+	for _, name := range rebound {
+		if binding, ok := k.commands[name]; ok && key.Matches(msg, *binding) {
+			return name
+		}
+	}
+	for _, c := range commandActions {
+		if binding, ok := k.commands[c.name]; ok && key.Matches(msg, *binding) {
+			return c.name
+		}
+	}
+	return ""
+}
+
+// helpKeys renders the keys of binding for the help screen, eg "e/r",
+// or "[space]".
+func helpKeys(binding key.Binding) string {
+	names := []string{}
+	for _, k := range binding.Keys() {
+		switch {
+		case k == " ":
+			k = "[space]"
+		case len(k) > 1 && !strings.HasPrefix(k, "ctrl+"):
+			k = "[" + k + "]"
+		}
+		names = append(names, k)
+	}
+	return strings.Join(names, "/")
+}
+
+// helpLines renders a help line for each binding, as "keys: desc".
+func helpLines(bindings ...key.Binding) string {
+	lines := []string{}
+	for _, b := range bindings {
+		lines = append(lines, helpKeys(b)+": "+b.Help().Desc)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// controls renders the help screen's list of key bindings, from the
+// bindings in effect.
+func (k keyMap) controls() string {
+	groups := []string{}
+	group := []key.Binding{}
+	for _, c := range commandActions {
+		if c.name == "" {
+			groups = append(groups, helpLines(group...))
+			group = []key.Binding{}
+			continue
+		}
+		group = append(group, *k.commands[c.name])
+	}
+	if len(k.statuses) > 0 {
+		custom := []key.Binding{}
+		for _, name := range k.statuses {
+			custom = append(custom, *k.commands[name])
+		}
+		groups = append(groups, helpLines(custom...))
+	}
+	groups = append(groups, helpLines(append([]key.Binding{
+		k.up, k.down, k.first, k.last, k.halfDown, k.halfUp, k.pageDown, k.pageUp, k.tab,
+	}, group...)...))
+	groups = append(groups, helpLines(k.filter, k.quit))
+	return strings.Join(groups, "\n\n")
+}
+
+// navigate moves the selection or switches tabs per the navigation key
+// bindings, and reports whether msg was one of them.
+func (t *tui) navigate(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, t.keys.up) && t.board:
+		t.moveInColumn(-1)
+	case key.Matches(msg, t.keys.down) && t.board:
+		t.moveInColumn(1)
+	case key.Matches(msg, t.keys.up):
+		t.setSelection(t.selection - 1)
+	case key.Matches(msg, t.keys.down):
+		t.setSelection(t.selection + 1)
+	case key.Matches(msg, t.keys.first):
+		t.setSelection(0)
+	case key.Matches(msg, t.keys.last):
+		t.setSelection(len(t.renderSelection) - 1)
+	case key.Matches(msg, t.keys.halfDown):
+		t.moveHalfPage(1)
+	case key.Matches(msg, t.keys.halfUp):
+		t.moveHalfPage(-1)
+	case key.Matches(msg, t.keys.pageDown):
+		t.movePage(1)
+	case key.Matches(msg, t.keys.pageUp):
+		t.movePage(-1)
+	case key.Matches(msg, t.keys.tab):
+		t.tab()
+	default:
+		return false
+	}
+	return true
+}
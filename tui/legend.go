@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/nilock/tuido/tuido"
+)
+
+// tagLegend is the side panel listing tags in their colors, with their
+// counts of pending and done items. The selected tag can be applied as
+// the filter, or assigned a fixed color.
+type tagLegend struct {
+	tags   []string
+	counts map[string]tagCount
+	cursor int
+
+	// editor is the prompt for a hex color for the selected tag
+	editor textinput.Model
+	// pending is an entered color, awaiting confirmation
+	pending string
+	// renamer is the prompt for a new name for the selected tag
+	renamer textinput.Model
+}
+
+// tagCount is the number of pending and done items with a tag.
+type tagCount struct {
+	pending int
+	done    int
+}
+
+func newTagLegend(tagColors map[string]lg.Style, items []*tuido.Item) tagLegend {
+	counts := map[string]tagCount{}
+	for _, item := range items {
+		finished := item.Satus().Finished()
+		seen := map[string]bool{}
+		for _, tag := range item.Tags() {
+			if seen[tag.Name()] {
+				continue
+			}
+			seen[tag.Name()] = true
+			c := counts[tag.Name()]
+			if finished {
+				c.done++
+			} else {
+				c.pending++
+			}
+			counts[tag.Name()] = c
+		}
+	}
+
+	tags := []string{}
+	for name := range tagColors {
+		tags = append(tags, name)
+	}
+	for name := range counts {
+		if _, ok := tagColors[name]; !ok {
+			tags = append(tags, name)
+		}
+	}
+	sort.Strings(tags)
+
+	editor := textinput.New()
+	editor.Prompt = "color: "
+	editor.Placeholder = "#rrggbb"
+	editor.CharLimit = 7
+
+	renamer := textinput.New()
+	renamer.Prompt = "rename to: " + tuido.TagSigil
+
+	return tagLegend{tags: tags, counts: counts, editor: editor, renamer: renamer}
+}
+
+func (l *tagLegend) move(delta int) {
+	l.cursor = min(max(l.cursor+delta, 0), len(l.tags)-1)
+}
+
+func (l tagLegend) selected() string {
+	if len(l.tags) == 0 {
+		return ""
+	}
+	return l.tags[l.cursor]
+}
+
+func (l tagLegend) View(tagColors map[string]lg.Style, height int) string {
+	faint := lg.NewStyle().Faint(true)
+	rows := []string{faint.Render("  tag  (pending/done)")}
+
+	// scroll to keep the cursor in view, leaving room for the prompt
+	visible := max(height-4, 1)
+	first := max(l.cursor-visible+1, 0)
+	for i, name := range l.tags {
+		if i < first || i >= first+visible {
+			continue
+		}
+		c := l.counts[name]
+		row := tagColors[name].Render(tuido.TagSigil+name) + faint.Render(fmt.Sprintf(" %d/%d", c.pending, c.done))
+		if i == l.cursor {
+			row = lg.NewStyle().Bold(true).Render("> ") + row
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row)
+	}
+
+	if l.editor.Focused() {
+		rows = append(rows, "", l.editor.View())
+	} else if l.renamer.Focused() {
+		rows = append(rows, "", l.renamer.View())
+	} else if l.pending != "" {
+		rows = append(rows, "", "apply "+
+			lg.NewStyle().Foreground(lg.Color(l.pending)).Render(tuido.TagSigil+l.selected())+"?")
+	}
+
+	return lg.NewStyle().
+		Width(browserWidth).
+		Height(height).
+		MaxHeight(height).
+		Border(themeBorder(lg.NormalBorder()), false, true, false, false).
+		Render(strings.Join(rows, "\n"))
+}
+
+// setLegendMode opens the tag legend side panel.
+func (t *tui) setLegendMode() {
+	t.legend = newTagLegend(t.tagColors, t.items)
+	t.mode = legend
+}
+
+// updateLegend processes keystrokes in the tag legend. A color is entered
+// for the selected tag, then confirmed before it is applied and saved. A
+// new name for the tag is applied to every item at once.
+func (t *tui) updateLegend(msg tea.KeyMsg) tea.Cmd {
+	l := &t.legend
+
+	if l.renamer.Focused() {
+		switch msg.String() {
+		case "esc":
+			l.renamer.Blur()
+		case "enter":
+			l.renamer.Blur()
+			t.err = t.renameLegendTag(l.renamer.Value())
+		default:
+			var cmd tea.Cmd
+			l.renamer, cmd = l.renamer.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	if l.editor.Focused() {
+		switch msg.String() {
+		case "esc":
+			l.editor.Blur()
+		case "enter":
+			hex := l.editor.Value()
+			if !strings.HasPrefix(hex, "#") {
+				hex = "#" + hex
+			}
+			if _, err := colorful.Hex(hex); err != nil {
+				t.err = fmt.Errorf("%s is not a hex color", l.editor.Value())
+				return nil
+			}
+			t.err = nil
+			l.pending = hex
+			l.editor.Blur()
+		default:
+			var cmd tea.Cmd
+			l.editor, cmd = l.editor.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	if l.pending != "" {
+		if msg.String() == "y" || msg.String() == "enter" {
+			t.err = t.applyTagColor(l.selected(), l.pending)
+		}
+		l.pending = ""
+		return nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		l.move(-1)
+	case "down", "j":
+		l.move(1)
+	case "enter":
+		if l.selected() != "" {
+			l.editor.SetValue("")
+			l.editor.Focus()
+		}
+	case "r":
+		if l.selected() != "" {
+			l.renamer.SetValue(l.selected())
+			l.renamer.CursorEnd()
+			l.renamer.Focus()
+		}
+	case "f", "/":
+		t.filterByTag(l.selected())
+	case "backspace":
+		t.filter.SetValue("")
+		t.populateRenderSelection()
+	case "esc", "L":
+		t.mode = navigation
+	}
+	return nil
+}
+
+// filterByTag replaces the filter with tag name, leaving the legend open
+// so that tags can be tried in turn.
+func (t *tui) filterByTag(name string) {
+	if name == "" {
+		return
+	}
+	// a trailing space matches the tag exactly
+	t.filter.SetValue(tuido.TagSigil + name + " ")
+	t.populateRenderSelection()
+}
+
+// applyTagColor fixes the color of tag name, and saves the choice to the
+// user configuration file so that it is kept across runs.
+func (t *tui) applyTagColor(name, hex string) error {
+	if t.config.tagColors == nil {
+		t.config.tagColors = map[string]string{}
+	}
+	t.config.tagColors[name] = hex
+	t.tagColors[name] = lg.NewStyle().Foreground(lg.Color(hex))
+
+	path, err := userConfigPath()
+	if err != nil {
+		return err
+	}
+	return saveTagColor(path, name, hex)
+}
+
+// saveTagColor writes the color override for tag name into the
+// `tagcolors` line of the configuration file at path, creating the
+// line (or the file) if necessary.
+func saveTagColor(path, name, hex string) error {
+	colors := map[string]string{}
+	if cfg := parseConfigIfExists(path); cfg != nil {
+		for k, v := range cfg.tagColors {
+			colors[k] = v
+		}
+	}
+	colors[name] = hex
+
+	pairs := []string{}
+	for k, v := range colors {
+		pairs = append(pairs, k+":"+v)
+	}
+	sort.Strings(pairs)
+	return setConfigLine(path, "tagcolors", strings.Join(pairs, ","))
+}
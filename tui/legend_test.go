@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLegendCountsAndFilter(t *testing.T) {
+	items := newItems("[ ] one #home #home", "[x] two #home", "[@] three #work", "[ ] four #house")
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.setLegendMode()
+
+	if c := m.legend.counts["home"]; c.pending != 1 || c.done != 1 {
+		t.Errorf("expected #home on 1 pending and 1 done item, but found %+v", c)
+	}
+	if c := m.legend.counts["work"]; c.pending != 1 || c.done != 0 {
+		t.Errorf("expected #work on 1 pending item, but found %+v", c)
+	}
+
+	// the filter is the exact tag, so that #home does not match #house
+	m.filterByTag("home")
+	if len(m.renderSelection) != 1 || m.renderSelection[0] != items[0] {
+		t.Errorf("expected only the pending #home item listed, but found %d items", len(m.renderSelection))
+	}
+	if m.mode != legend {
+		t.Errorf("expected the legend to stay open")
+	}
+}
+
+func TestLegendRenameTag(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] one #wip\n[x] two #wip=2 #home\n[ ] three #home\n"), 0644)
+	items, _ := getItems(file)
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.setLegendMode()
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			model, _ := m.Update(k)
+			m = model.(tui)
+		}
+	}
+	press(tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if m.legend.selected() != "wip" || !m.legend.renamer.Focused() {
+		t.Fatalf("expected the rename prompt for #wip, but found %q", m.legend.selected())
+	}
+	press(tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("in-progress")}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err != nil {
+		t.Fatal(m.err)
+	}
+	expectContent(t, file, "[ ] one #in-progress\n[x] two #in-progress=2 #home\n[ ] three #home\n")
+	if m.legend.selected() != "in-progress" {
+		t.Errorf("expected the renamed tag selected, but found %q", m.legend.selected())
+	}
+
+	// the rename is undone as a whole
+	m.applyUndo(false)
+	expectContent(t, file, "[ ] one #wip\n[x] two #wip=2 #home\n[ ] three #home\n")
+}
+
+func TestLegendRenameTagConflicts(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.xit"), filepath.Join(dir, "b.xit")
+	os.WriteFile(a, []byte("[ ] one #wip\n[ ] two #wip\n"), 0644)
+	os.WriteFile(b, []byte("[ ] three #wip\n"), 0644)
+	itemsA, _ := getItems(a)
+	itemsB, _ := getItems(b)
+	m := newTUI(append(itemsA, itemsB...), runConfig)
+	m.populateRenderSelection()
+	m.setLegendMode()
+
+	// both files change on disk, under the items
+	os.WriteFile(a, []byte("[ ] one #wip\n[ ] two #wip, edited\n"), 0644)
+	os.WriteFile(b, []byte("[ ] three #wip, edited\n"), 0644)
+
+	err := m.renameLegendTag("doing")
+	if err == nil || !strings.Contains(err.Error(), a+":2") || !strings.Contains(err.Error(), b+":1") {
+		t.Fatalf("expected both conflicts reported, but found %v", err)
+	}
+	expectContent(t, a, "[ ] one #doing\n[ ] two #wip, edited\n")
+
+	// forcing the change renames the conflicting items, over their edits
+	m.resolveConflict("f")
+	if m.err != nil {
+		t.Fatalf("expected the forced rename to succeed, but found %v", m.err)
+	}
+	expectContent(t, a, "[ ] one #doing\n[ ] two #doing\n")
+	expectContent(t, b, "[ ] three #doing\n")
+}
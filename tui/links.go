@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// urlStyle underlines the links in item text.
+var urlStyle = lg.NewStyle().Underline(true)
+
+// openLink opens the selected item's link in the browser, or, if it has
+// several, asks which to open.
+func (t *tui) openLink() {
+	current := t.currentSelection()
+	if current == nil {
+		return
+	}
+	switch urls := current.URLs(); len(urls) {
+	case 0:
+		t.notice = "no links in " + current.Location()
+	case 1:
+		t.browse(urls[0])
+	default:
+		t.mode = choosingLink
+	}
+}
+
+func (t tui) linkPrompt() string {
+	choices := []string{}
+	for i, url := range t.currentSelection().URLs() {
+		if i == 9 {
+			break
+		}
+		choices = append(choices, fmt.Sprintf("[%d] - %s", i+1, url))
+	}
+	return "open " + strings.Join(choices, ",  ") + ",  [any key] - Cancel"
+}
+
+// chooseLink opens the link numbered by key, from 1, if any.
+func (t *tui) chooseLink(key string) {
+	urls := t.currentSelection().URLs()
+	if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= len(urls) && n <= 9 {
+		t.browse(urls[n-1])
+	}
+}
+
+func (t *tui) browse(url string) {
+	if err := openBrowser(url); err != nil {
+		t.err = fmt.Errorf("could not open %s: %s", url, err)
+		return
+	}
+	t.notice = "opened " + url
+}
+
+// openBrowser opens url with the desktop's default handler, without
+// waiting for it.
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
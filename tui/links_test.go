@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpenLink(t *testing.T) {
+	items := newItems("[ ] compare https://a.example/1 with https://b.example/2", "[ ] no links")
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+
+	m.openLink()
+	if m.mode != choosingLink || !strings.Contains(m.linkPrompt(), "[2] - https://b.example/2") {
+		t.Fatalf("expected a choice of the item's links, but found mode %d", m.mode)
+	}
+	m.chooseLink("3")
+	if m.notice != "" || m.err != nil {
+		t.Errorf("expected no link opened, but found %q %v", m.notice, m.err)
+	}
+
+	m.mode = navigation
+	m.setSelection(1)
+	m.openLink()
+	if m.mode != navigation || m.notice == "" {
+		t.Errorf("expected a notice that the item has no links, but found mode %d", m.mode)
+	}
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+// progressEvery is the number of parsed files between progress reports
+// of a background scan.
+const progressEvery = 250
+
+// scanProgressMsg reports the number of files parsed so far by the
+// background scan.
+type scanProgressMsg struct {
+	done  int
+	total int
+}
+
+// itemsLoadedMsg delivers the items of a completed background scan.
+type itemsLoadedMsg struct {
+	items []*tuido.Item
+}
+
+// loadInBackground parses the workspace's files while the app runs,
+// reporting progress to prog and then sending the sorted items.
+func loadInBackground(prog *tea.Program, ws workspace) {
+	total := len(ws.files)
+	go func() {
+		items := ws.load(func(done int) {
+			if done%progressEvery == 0 {
+				prog.Send(scanProgressMsg{done, total})
+			}
+		})
+		sortItems(items)
+		prog.Send(itemsLoadedMsg{items})
+	}()
+}
+
+// startLoading shows the spinner in place of the items, until an
+// itemsLoadedMsg arrives.
+func (t *tui) startLoading(total int) {
+	t.loading = true
+	t.scanTotal = total
+	t.spinner = spinner.New()
+	t.spinner.Spinner = spinner.Dot
+}
+
+// updateLoading handles messages while the initial scan runs. Changes
+// reported by -w are held until the scan completes, and keys other than
+// quit are ignored, so that nothing is written to items that are about
+// to be replaced.
+func (t tui) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case itemsLoadedMsg:
+		t.loading = false
+		t.items = msg.items
+		t.refreshTagColors()
+		t.populateRenderSelection()
+		if t.resumeFrom != nil {
+			t.resume(*t.resumeFrom)
+			t.resumeFrom = nil
+		}
+		for _, file := range t.pendingReloads {
+			t.reloadFile(file)
+		}
+		t.pendingReloads = nil
+		return t, nil
+	case scanProgressMsg:
+		t.scanned = msg.done
+	case fileChangedMsg:
+		t.pendingReloads = append(t.pendingReloads, msg.file)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		t.spinner, cmd = t.spinner.Update(msg)
+		return t, cmd
+	case tea.KeyMsg:
+		if key.Matches(msg, t.keys.quit) || msg.String() == "ctrl+c" {
+			return t, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		t.h = msg.Height
+		t.w = msg.Width
+	}
+	return t, nil
+}
+
+func (t tui) loadingView() string {
+	return fmt.Sprintf("\n  %s scanning %d of %d files...", t.spinner.View(), t.scanned, t.scanTotal)
+}
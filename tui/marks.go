@@ -0,0 +1,48 @@
+package tui
+
+import "github.com/nilock/tuido/tuido"
+
+// toggleMark marks or unmarks the current item for a bulk status change.
+func (t *tui) toggleMark() {
+	current := t.currentSelection()
+	if current == nil {
+		return
+	}
+	if t.marked == nil {
+		t.marked = map[*tuido.Item]bool{}
+	}
+	if t.marked[current] {
+		delete(t.marked, current)
+	} else {
+		t.marked[current] = true
+	}
+}
+
+// markedItems returns the marked items, in the order of t.items. Marked
+// items which have since been filtered out of view are included.
+func (t tui) markedItems() []*tuido.Item {
+	items := []*tuido.Item{}
+	for _, item := range t.items {
+		if t.marked[item] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// remark carries marks over from the items of a file to their fresh
+// reads, matched by text.
+func (t *tui) remark(file string, fresh []*tuido.Item) {
+	texts := map[string]bool{}
+	for item := range t.marked {
+		if item.File() == file {
+			texts[item.Text()] = true
+			delete(t.marked, item)
+		}
+	}
+	for _, item := range fresh {
+		if texts[item.Text()] {
+			t.marked[item] = true
+		}
+	}
+}
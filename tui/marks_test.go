@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestMarkedStatusChange(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	if err := os.WriteFile(file, []byte("[ ] first\n[ ] second\n[ ] third\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	items, err := getItems(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tui := newTUI(items, runConfig)
+	tui.populateRenderSelection()
+	tui.toggleMark()
+	tui.setSelection(2)
+	tui.toggleMark()
+	tui.setStatus(tuido.Ongoing)
+
+	if tui.err != nil {
+		t.Fatal(tui.err)
+	}
+	if len(tui.marked) != 0 {
+		t.Errorf("expected marks to be cleared, but found %d", len(tui.marked))
+	}
+
+	expectStatuses(t, file, tuido.Ongoing, tuido.Open, tuido.Ongoing)
+
+	if _, err := tui.undo.undo(); err != nil {
+		t.Fatal(err)
+	}
+	expectStatuses(t, file, tuido.Open, tuido.Open, tuido.Open)
+
+	if _, err := tui.undo.redo(); err != nil {
+		t.Fatal(err)
+	}
+	expectStatuses(t, file, tuido.Ongoing, tuido.Open, tuido.Ongoing)
+}
+
+func TestMarkedTagEdit(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] first\n[ ] second\n[ ] third\n"), 0644)
+	items, _ := getItems(file)
+
+	tui := newTUI(items, runConfig)
+	tui.populateRenderSelection()
+	tui.toggleMark()
+	tui.setSelection(1)
+	tui.toggleMark()
+	tui.setTaggingMode(false)
+	tui.tagEditor.SetValue("cleanup")
+
+	if err := tui.applyTagEdit(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(file)
+	if string(data) != "[ ] first #cleanup\n[ ] second #cleanup\n[ ] third\n" {
+		t.Errorf("expected the marked items tagged, but found %q", data)
+	}
+}
+
+func expectStatuses(t *testing.T, file string, statuses ...tuido.Status) {
+	t.Helper()
+	items, err := getItems(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, item := range items {
+		if item.Satus() != statuses[i] {
+			t.Errorf("item %d: expected status %s, but found %s", i, statuses[i], item.Satus())
+		}
+	}
+}
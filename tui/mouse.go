@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// escapeSequence matches the terminal styling of rendered text.
+var escapeSequence = regexp.MustCompile("\x1b\\[[0-9;]*[a-zA-Z]")
+
+// nextStatus is the status that clicking an item's status box gives it:
+// open items become ongoing, and then checked, and then open again.
+var nextStatus = map[tuido.Status]tuido.Status{
+	tuido.Open:     tuido.Ongoing,
+	tuido.Ongoing:  tuido.Checked,
+	tuido.Review:   tuido.Checked,
+	tuido.Checked:  tuido.Open,
+	tuido.Obsolete: tuido.Open,
+}
+
+// statusCommands are the navigation commands which set each status.
+var statusCommands = map[tuido.Status]string{
+	tuido.Open:     "open",
+	tuido.Ongoing:  "ongoing",
+	tuido.Review:   "review",
+	tuido.Checked:  "done",
+	tuido.Obsolete: "obsolete",
+}
+
+// updateMouse handles mouse events in navigation mode. The wheel moves
+// the selection, and a click on a tab switches to it. A click on an item
+// selects it, and, on the item's status box, cycles its status, or, on
+// one of its tags, filters by the tag. Clicks on the board, or while a
+// side panel is open, are ignored.
+func (t *tui) updateMouse(msg tea.MouseMsg) {
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		t.setSelection(t.selection - 1)
+		return
+	case tea.MouseWheelDown:
+		t.setSelection(t.selection + 1)
+		return
+	case tea.MouseLeft:
+	default:
+		return
+	}
+	if t.mode != navigation || t.board {
+		return
+	}
+
+	if tab, ok := t.tabAt(msg.X, msg.Y); ok {
+		for t.itemsFilter != tab {
+			t.tab()
+		}
+		return
+	}
+
+	i, row, line, col := t.itemAt(msg.X, msg.Y)
+	if i < 0 {
+		return
+	}
+	t.setSelection(i)
+	if t.onCollapsedGroup(i) {
+		return
+	}
+
+	current := t.currentSelection()
+	if start := statusBoxStart(line, t.showAges); row == t.headerLines(i) && start >= 0 && col >= start && col < start+3 {
+		next, ok := nextStatus[current.Satus()]
+		if !ok {
+			next = tuido.Open // custom statuses
+		}
+		if notice := t.readOnlyNotice(statusCommands[next]); notice != "" {
+			t.notice = notice
+			return
+		}
+		t.setStatus(next)
+		return
+	}
+	if name := tagAt(line, col); name != "" {
+		t.filterByTag(name)
+	}
+}
+
+// tabAt returns the tab drawn at screen cell x, y, if any.
+func (t tui) tabAt(x, y int) (itemType, bool) {
+	if y >= lg.Height(t.header()) {
+		return "", false
+	}
+	left := 0
+	for _, tab := range tabs {
+		style := tabStyle
+		if t.itemsFilter == tab {
+			style = activeTabStyle
+		}
+		width := lg.Width(style.Render(string(tab)))
+		if x >= left && x < left+width {
+			return tab, true
+		}
+		left += width
+	}
+	return "", false
+}
+
+// itemAt returns the index in the render selection of the item drawn at
+// screen cell x, y, with the row of y within the item, the line drawn at
+// y, unstyled, and the column of x in that line. It returns -1 if no item
+// is drawn there. The list is laid out as View lays it out in navigation
+// mode.
+func (t tui) itemAt(x, y int) (int, int, string, int) {
+	header := t.header()
+	height := t.h - (lg.Height(header) + lg.Height(t.statusBar()) + lg.Height(t.footer()))
+	width := t.w
+	if t.preview {
+		if t.previewBeside() {
+			width -= lg.Width(t.previewPanel(height, true))
+		} else {
+			height -= lg.Height(t.previewPanel(height, false))
+		}
+	}
+	row := y - lg.Height(header)
+	if row < 0 || row >= height || x < 0 || x >= width || len(t.renderSelection) == 0 {
+		return -1, 0, "", 0
+	}
+
+	columns := t.columnCount()
+	colWidth := width / columns
+	rendered := t.renderedItemCollection(colWidth - 1)
+	_, starts := stackItems(rendered, height)
+	starts = append(starts, len(rendered))
+
+	// the page holding the selection is the one on screen
+	current := 0
+	for c := 0; c < len(starts)-1; c++ {
+		if t.selection >= starts[c] {
+			current = c
+		}
+	}
+	c := current/columns*columns + x/colWidth
+	if c >= len(starts)-1 {
+		return -1, 0, "", 0
+	}
+
+	for i := starts[c]; i < starts[c+1]; i++ {
+		lines := strings.Split(rendered[i], "\n")
+		if row < len(lines) {
+			return i, row, escapeSequence.ReplaceAllString(lines[row], ""), x % colWidth
+		}
+		row -= len(lines)
+	}
+	return -1, 0, "", 0
+}
+
+// headerLines returns the number of lines of the agenda and file group
+// headers drawn above item i, which are part of its rendered item.
+func (t tui) headerLines(i int) int {
+	n := 0
+	if t.agendaActive() {
+		if header := t.bucketHeader(i, time.Now()); header != "" {
+			n += lg.Height(header)
+		}
+	}
+	if t.groupsActive() {
+		if header := t.groupHeader(i); header != "" {
+			n += lg.Height(header)
+		}
+	}
+	return n
+}
+
+// cellIndex returns the byte index in line of the character drawn at
+// column col, or -1 if line is narrower.
+func cellIndex(line string, col int) int {
+	width := 0
+	for i, r := range line {
+		width += lg.Width(string(r))
+		if width > col {
+			return i
+		}
+	}
+	return -1
+}
+
+// statusBoxStart returns the column of an item's status box, eg "[ ]", in
+// the first line of a rendered item, after the cursor, marks, indent and
+// any age, or -1 if line does not begin an item.
+func statusBoxStart(line string, ages bool) int {
+	rest := strings.TrimLeft(line, " >*")
+	if ages {
+		if space := strings.Index(rest, " "); space >= 0 {
+			rest = rest[space+1:]
+		}
+	}
+	if len(rest) < 3 || rest[0] != '[' || rest[2] != ']' {
+		return -1
+	}
+	return lg.Width(line[:len(line)-len(rest)])
+}
+
+// tagAt returns the name of the tag drawn at column col of line, or "".
+func tagAt(line string, col int) string {
+	i := cellIndex(line, col)
+	if i < 0 || line[i] == ' ' {
+		return ""
+	}
+	start := strings.LastIndex(line[:i], " ") + 1
+	end := len(line)
+	if space := strings.Index(line[i:], " "); space >= 0 {
+		end = i + space
+	}
+	word := line[start:end]
+	if !tuido.IsTagToken(word) {
+		return ""
+	}
+	return strings.SplitN(strings.TrimPrefix(word, tuido.TagSigil), "=", 2)[0]
+}
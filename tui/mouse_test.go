@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestMouse(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] one\n[ ] two #home\n[ ] three #work\n[x] four\n"), 0644)
+	items, _ := getItems(file)
+	m := newTUI(items, runConfig)
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = model.(tui)
+	m.populateRenderSelection()
+	top := lg.Height(m.header())
+
+	click := func(x, y int) {
+		t.Helper()
+		model, _ := m.Update(tea.MouseMsg{X: x, Y: y, Type: tea.MouseLeft})
+		m = model.(tui)
+	}
+
+	model, _ = m.Update(tea.MouseMsg{Type: tea.MouseWheelDown})
+	m = model.(tui)
+	if m.selection != 1 {
+		t.Errorf("expected the wheel to move the selection down, but found %d", m.selection)
+	}
+
+	// the second item listed, in the default sort order
+	click(20, top+1)
+	if m.currentSelection().Text() != "three #work" {
+		t.Errorf("expected the clicked item selected, but found %q", m.currentSelection().Text())
+	}
+
+	// the status box of the first item: after the two column cursor
+	click(3, top)
+	if m.currentSelection() != items[0] || items[0].Satus() != tuido.Ongoing {
+		t.Errorf("expected a click on the status box to make the item ongoing, but found %q", items[0].Raw())
+	}
+
+	_, _, line, _ := m.itemAt(0, top+2)
+	click(strings.Index(line, "#home")+1, top+2)
+	if m.filter.Value() != "#home " || len(m.renderSelection) != 1 {
+		t.Errorf("expected a click on a tag to filter by it, but found %q", m.filter.Value())
+	}
+
+	click(lg.Width(activeTabStyle.Render(string(todo)))+1, 1)
+	if m.itemsFilter != done {
+		t.Errorf("expected a click on the done tab to switch to it, but found %s", m.itemsFilter)
+	}
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// noteEntry is a single sidecar note, attached to an item.
+//
+// The item's text is stored alongside its location so that a note can
+// be re-attached to its item after the item has moved within its file.
+type noteEntry struct {
+	ID   string `json:"id"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Item string `json:"item"`
+	Note string `json:"note"`
+}
+
+// notes is the sidecar store of longer form notes attached to items.
+// Notes are kept out of the source files, in `notes.json` in the
+// tuido app directory.
+type notes struct {
+	path    string
+	entries []noteEntry
+}
+
+func notesPath() string {
+	return filepath.Join(appDir, "notes.json")
+}
+
+// loadNotes reads the sidecar notes file. A missing or malformed file
+// produces an empty store.
+func loadNotes(path string) *notes {
+	n := &notes{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return n
+	}
+	json.Unmarshal(data, &n.entries)
+
+	return n
+}
+
+func (n *notes) save() error {
+	data, err := json.MarshalIndent(n.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return tuido.WriteAtomic(n.path, data)
+}
+
+// find returns the index of the note entry for item i, or -1.
+//
+// Item id matches are preferred, then exact location matches. Failing
+// that, an entry in the same file with the same item text is taken to be
+// the same item after a shift in line numbers, and is re-keyed to the
+// item's new line.
+func (n *notes) find(i *tuido.Item) int {
+	if n == nil || i == nil {
+		return -1
+	}
+
+	for idx, e := range n.entries {
+		if e.ID != "" && e.ID == i.ID() {
+			n.entries[idx].Line = i.Line()
+			return idx
+		}
+	}
+	for idx, e := range n.entries {
+		if e.File == i.File() && e.Line == i.Line() && e.Item == i.Text() {
+			return idx
+		}
+	}
+	for idx, e := range n.entries {
+		if e.File == i.File() && e.Item == i.Text() {
+			n.entries[idx].Line = i.Line()
+			return idx
+		}
+	}
+	return -1
+}
+
+// get returns the note attached to item i, if any.
+func (n *notes) get(i *tuido.Item) string {
+	if idx := n.find(i); idx >= 0 {
+		return n.entries[idx].Note
+	}
+	return ""
+}
+
+// set attaches note to item i and persists the store. An empty note
+// removes the entry.
+func (n *notes) set(i *tuido.Item, note string) error {
+	idx := n.find(i)
+
+	if note == "" {
+		if idx < 0 {
+			return nil
+		}
+		n.entries = append(n.entries[:idx], n.entries[idx+1:]...)
+		return n.save()
+	}
+
+	if idx < 0 {
+		n.entries = append(n.entries, noteEntry{
+			ID:   i.ID(),
+			File: i.File(),
+			Line: i.Line(),
+			Item: i.Text(),
+		})
+		idx = len(n.entries) - 1
+	}
+	n.entries[idx].Note = note
+	return n.save()
+}
+
+// rekey follows an item text edit, so that notes stay attached to
+// items whose text has changed in-app.
+func (n *notes) rekey(i *tuido.Item, oldText string) {
+	if n == nil || i == nil {
+		return
+	}
+	for idx, e := range n.entries {
+		if e.File == i.File() && e.Line == i.Line() && e.Item == oldText {
+			n.entries[idx].Item = i.Text()
+			n.entries[idx].ID = i.ID()
+			n.save()
+			return
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMultilineNote(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	m := newTUI(newItems("[ ] plan the trip"), runConfig)
+	m.notes = loadNotes(path)
+	m.populateRenderSelection()
+	m.setNoteMode()
+
+	// [enter] begins a new line, and ctrl+s saves the note
+	for _, msg := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("book")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("pack")},
+		{Type: tea.KeyCtrlS},
+	} {
+		model, _ := m.Update(msg)
+		m = model.(tui)
+	}
+	if m.mode != navigation || m.err != nil {
+		t.Fatalf("expected the note saved, but found mode %v and error %v", m.mode, m.err)
+	}
+	if note := loadNotes(path).get(m.currentSelection()); note != "book\npack" {
+		t.Errorf("expected a note of two lines, but found %q", note)
+	}
+}
@@ -0,0 +1,256 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// palette is the fuzzy finder, which jumps the selection to any item,
+// whatever the tab or filter.
+type palette struct {
+	input   textinput.Model
+	matches []paletteMatch
+	cursor  int
+}
+
+// paletteMatch is an item matching the palette's pattern, with its score
+// and the positions of the matched characters in its text.
+type paletteMatch struct {
+	item      *tuido.Item
+	score     int
+	positions []int
+}
+
+// setPaletteMode opens the fuzzy finder.
+func (t *tui) setPaletteMode() {
+	input := textinput.New()
+	input.Prompt = "jump to: "
+	input.Focus()
+	t.palette = palette{input: input}
+	t.palette.matches = paletteMatches(t.items, "")
+	t.mode = jumping
+}
+
+// updatePalette processes keystrokes in the fuzzy finder. [enter] jumps
+// to the selected match.
+func (t *tui) updatePalette(msg tea.KeyMsg) tea.Cmd {
+	p := &t.palette
+	switch msg.String() {
+	case "esc":
+		t.mode = navigation
+	case "up", "ctrl+k":
+		p.cursor = max(p.cursor-1, 0)
+	case "down", "ctrl+j":
+		p.cursor = max(min(p.cursor+1, len(p.matches)-1), 0)
+	case "enter":
+		t.mode = navigation
+		if p.cursor < len(p.matches) {
+			t.jumpTo(p.matches[p.cursor].item)
+		}
+	default:
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		p.matches = paletteMatches(t.items, p.input.Value())
+		p.cursor = 0
+		return cmd
+	}
+	return nil
+}
+
+// paletteMatches returns the items whose text (tags included) matches
+// pattern, best first.
+func paletteMatches(items []*tuido.Item, pattern string) []paletteMatch {
+	pattern = strings.TrimSpace(pattern)
+	matches := []paletteMatch{}
+	for _, item := range items {
+		score, positions, ok := fuzzyMatch(pattern, item.Text())
+		if ok {
+			matches = append(matches, paletteMatch{item, score, positions})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return len(matches[i].item.Text()) < len(matches[j].item.Text())
+	})
+	return matches
+}
+
+// fuzzy match scores, after fzf's: every matched character scores, and
+// more so at the start of a word or right after the previous match.
+// Gaps between matched characters cost a little, so that tight matches
+// rank above scattered ones.
+const (
+	fuzzyMatchScore       = 16
+	fuzzyBoundaryBonus    = 8
+	fuzzyConsecutiveBonus = 8
+	fuzzyGapPenalty       = 1
+)
+
+// fuzzyMatch scores text against pattern, ignoring case. Every
+// character of pattern must appear in text, in order. It returns the
+// score of the best match, the positions (in runes) of its matched
+// characters, and whether text matched at all. Every text matches an
+// empty pattern.
+func fuzzyMatch(pattern, text string) (int, []int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	r := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	if len(lower) != len(r) {
+		lower = r // case folding changed the length; match runes as they are
+	}
+
+	best, bestPositions, found := 0, []int(nil), false
+	// try each occurrence of the first character as the start of the
+	// match, and take the remaining characters as early as possible
+	for start := range lower {
+		if lower[start] != p[0] {
+			continue
+		}
+		positions := []int{start}
+		for i, n := start+1, 1; n < len(p) && i < len(lower); i++ {
+			if lower[i] == p[n] {
+				positions = append(positions, i)
+				n++
+			}
+		}
+		if len(positions) < len(p) {
+			break // nor will any later start match
+		}
+
+		score := 0
+		for n, pos := range positions {
+			score += fuzzyMatchScore
+			if pos == 0 || isWordBoundary(r[pos-1], r[pos]) {
+				score += fuzzyBoundaryBonus
+			}
+			if n > 0 {
+				if gap := pos - positions[n-1] - 1; gap == 0 {
+					score += fuzzyConsecutiveBonus
+				} else {
+					score -= gap * fuzzyGapPenalty
+				}
+			}
+		}
+		if !found || score > best {
+			best, bestPositions, found = score, positions, true
+		}
+	}
+	return best, bestPositions, found
+}
+
+// isWordBoundary reports whether cur begins a word, after prev: after a
+// space or punctuation (eg the # of a tag), or at a camelCase hump.
+func isWordBoundary(prev, cur rune) bool {
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// jumpTo selects item. If it is not listed, the view is widened until it
+// is: its parents are unfolded, and its file group expanded, and then the
+// tab switched to the item's, and the filter, folder and project scopes,
+// and done date range cleared.
+func (t *tui) jumpTo(item *tuido.Item) {
+	if t.selectItem(item) {
+		return
+	}
+
+	if t.unfoldAncestors(item) {
+		t.populateRenderSelection()
+		if t.selectItem(item) {
+			return
+		}
+	}
+
+	if t.groups.collapsed[item.File()] {
+		t.groups.collapsed[item.File()] = false
+		t.populateRenderSelection()
+		if t.selectItem(item) {
+			return
+		}
+	}
+
+	t.itemsFilter = todo
+	if item.Satus().Finished() {
+		t.itemsFilter = done
+	} else if !item.Active() {
+		t.itemsFilter = snoozed
+	}
+	t.filter.SetValue("")
+	t.fileFilter = ""
+	t.dirScope = ""
+	t.projectOnly = false
+	t.stalledOnly = false
+	t.doneRange = anyTime
+	t.populateRenderSelection()
+	if !t.selectItem(item) {
+		t.err = fmt.Errorf("cannot jump to %s - it is not listed", item.Location())
+		return
+	}
+	t.notice = "cleared the filters to show " + item.Location()
+}
+
+// selectItem selects item, if it is listed, and reports whether it was.
+func (t *tui) selectItem(item *tuido.Item) bool {
+	for i, listed := range t.renderSelection {
+		if listed == item {
+			t.setSelection(i)
+			return true
+		}
+	}
+	return false
+}
+
+func (p palette) View(width, height int) string {
+	highlight := lg.NewStyle().Bold(true).Underline(true)
+	faint := lg.NewStyle().Faint(true)
+
+	rows := []string{p.input.View(), ""}
+	visible := max(height-len(rows), 0)
+	first := max(p.cursor-visible+1, 0)
+	for i := first; i < len(p.matches) && i < first+visible; i++ {
+		m := p.matches[i]
+		matched := map[int]bool{}
+		for _, pos := range m.positions {
+			matched[pos] = true
+		}
+		text := ""
+		for n, r := range []rune(m.item.Text()) {
+			if matched[n] {
+				text += highlight.Render(string(r))
+			} else {
+				text += string(r)
+			}
+		}
+
+		row := m.item.Satus().String() + " " + text + "  " + faint.Render(m.item.Location())
+		if i == p.cursor {
+			row = lg.NewStyle().Bold(true).Render("> ") + row
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, lg.NewStyle().MaxWidth(width).Render(row))
+	}
+	if len(p.matches) == 0 {
+		rows = append(rows, faint.Render("  no matching items"))
+	}
+
+	return lg.NewStyle().
+		Width(width).
+		Height(height).
+		MaxHeight(height).
+		Render(strings.Join(rows, "\n"))
+}
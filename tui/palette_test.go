@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	if _, _, ok := fuzzyMatch("wplnt", "water the plants"); !ok {
+		t.Errorf("expected a scattered match")
+	}
+	if _, _, ok := fuzzyMatch("tw", "water the plants"); ok {
+		t.Errorf("expected characters out of order not to match")
+	}
+	word, _, _ := fuzzyMatch("pl", "water the plants")
+	inner, _, _ := fuzzyMatch("pl", "an apple")
+	if word <= inner {
+		t.Errorf("expected a match at the start of a word ranked first, but scored %d and %d", word, inner)
+	}
+	_, positions, _ := fuzzyMatch("ab", "a xab")
+	if len(positions) != 2 || positions[0] != 3 || positions[1] != 4 {
+		t.Errorf("expected the tightest match chosen, but found positions %v", positions)
+	}
+}
+
+func TestPaletteJump(t *testing.T) {
+	items := newItems("[ ] fix the parser #bug", "[ ] water the plants #home", "[x] file taxes #home")
+	m := newTUI(items, runConfig)
+	m.filter.SetValue("#bug ")
+	m.populateRenderSelection()
+
+	m.setPaletteMode()
+	for _, r := range "taxes" {
+		m.updatePalette(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if len(m.palette.matches) != 1 || m.palette.matches[0].item != items[2] {
+		t.Fatalf("expected the done item matched, but found %d matches", len(m.palette.matches))
+	}
+	m.updatePalette(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.mode != navigation || m.itemsFilter != done || m.filter.Value() != "" {
+		t.Errorf("expected the done tab, unfiltered, but found mode %d, tab %s, filter %q", m.mode, m.itemsFilter, m.filter.Value())
+	}
+	if m.currentSelection() != items[2] {
+		t.Errorf("expected the chosen item selected, but found %v", m.currentSelection())
+	}
+}
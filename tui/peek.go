@@ -7,6 +7,9 @@ import (
 	"github.com/nilock/tuido/tuido"
 )
 
+// peekStyle is the pointer to the item in its source context
+var peekStyle lg.Style = lg.NewStyle().Foreground(lg.Color("#a0f0a0"))
+
 type peekScreen struct {
 	item tuido.Item
 }
@@ -24,12 +27,12 @@ func (p *peekScreen) View(h, w int, footer func() string) string {
 		Padding(bodyPadding).
 		Render(peekBody)
 
-	st := lg.NewStyle().
-		Foreground(lg.Color("#a0f0a0"))
+	st := peekStyle
 
-	pointer := strings.Repeat("  │\n", n+bodyPadding)
-	pointer += ">>│"
-	pointer += strings.Repeat("\n  │", lg.Height(peekBody)-lg.Height(pointer))
+	bar := glyph("│", "|")
+	pointer := strings.Repeat("  "+bar+"\n", n+bodyPadding)
+	pointer += ">>" + bar
+	pointer += strings.Repeat("\n  "+bar, lg.Height(peekBody)-lg.Height(pointer))
 
 	pointer = st.Render(pointer)
 
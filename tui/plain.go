@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// narrowWidth is the window width below which the header, status bar, and
+// footer are shortened, and cut to the window.
+const narrowWidth = 60
+
+// statusWords spell out item statuses in the plain theme.
+var statusWords = map[tuido.Status]string{
+	tuido.Open:     "OPEN",
+	tuido.Ongoing:  "ONGOING",
+	tuido.Review:   "REVIEW",
+	tuido.Checked:  "DONE",
+	tuido.Obsolete: "OBSOLETE",
+}
+
+// asciiBorder is the border of panels and cards in the plain theme, which
+// draws no box drawing characters.
+var asciiBorder = lg.Border{
+	Top: "-", Bottom: "-", Left: "|", Right: "|",
+	TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+}
+
+// the tab styles of the other themes
+var (
+	boxedTabStyle       = tabStyle
+	boxedActiveTabStyle = activeTabStyle
+	boxedTabGapStyle    = tabGapStyle
+)
+
+// applyPlainStyles sets the tabs of the plain theme: the active tab in
+// brackets, without borders, so that the header is a single line.
+func applyPlainStyles(plain bool) {
+	if !plain {
+		tabStyle, activeTabStyle, tabGapStyle = boxedTabStyle, boxedActiveTabStyle, boxedTabGapStyle
+		return
+	}
+	tabStyle = lg.NewStyle().Padding(0, 1)
+	activeTabStyle = lg.NewStyle().Bold(true).Border(lg.Border{Left: "[", Right: "]"}, false, true, false, true)
+	tabGapStyle = lg.NewStyle()
+}
+
+// themeBorder returns b, or, in the plain theme, asciiBorder.
+func themeBorder(b lg.Border) lg.Border {
+	if activeTheme.plain {
+		return asciiBorder
+	}
+	return b
+}
+
+// glyph returns fancy, or, in the plain theme, its plain text stand-in.
+func glyph(fancy, plain string) string {
+	if activeTheme.plain {
+		return plain
+	}
+	return fancy
+}
+
+// plainBox is the status of item spelled out, in place of its status box,
+// padded so that item texts line up.
+func plainBox(item tuido.Item) string {
+	word, ok := statusWords[item.Satus()]
+	if !ok {
+		word = strings.ToUpper(string(item.Satus()))
+	}
+	return fmt.Sprintf("%-8s ", word)
+}
+
+// plainFlags are the words which stand in for the colors flagging item,
+// eg " (overdue)".
+func (t tui) plainFlags(item tuido.Item) string {
+	flags := ""
+	if item.Overdue() && t.itemsFilter == todo {
+		flags += " (overdue)"
+	}
+	if item.Stalled(t.config.stalled) {
+		flags += " (stalled)"
+	} else if t.stale(item) {
+		flags += " (stale)"
+	}
+	return flags
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+func TestPlainTheme(t *testing.T) {
+	defer applyTheme(themes["dark"])
+	cfg := runConfig
+	cfg.theme = "plain"
+	applyTheme(cfg.resolveTheme())
+
+	items := newItems("[ ] file taxes #due=2022-04-18 #money", "[x] done already")
+	m := newTUI(items, cfg)
+	m.tagLayout = tagsBelow
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 40, Height: 20})
+	m = model.(tui)
+	m.populateRenderSelection()
+
+	line := escapeSequence.ReplaceAllString(m.renderTuido(*items[0], 30), "")
+	if !strings.HasPrefix(line, "OPEN ") || strings.Contains(line, "\n") || lg.Width(line) > 28 {
+		t.Errorf("expected the status in words, on one line cut to width, but found %q", line)
+	}
+	if line := m.renderTuido(*items[0], 80); !strings.Contains(line, "(overdue)") {
+		t.Errorf("expected overdue items flagged in words, but found %q", line)
+	}
+
+	view := m.View()
+	for _, box := range []string{"─", "│", "╭", "●"} {
+		if strings.Contains(view, box) {
+			t.Errorf("expected no box drawing, but found %q in\n%s", box, view)
+		}
+	}
+	for i, row := range strings.Split(view, "\n") {
+		if lg.Width(row) > 40 {
+			t.Errorf("expected row %d cut to the narrow window, but found %q", i, row)
+		}
+	}
+}
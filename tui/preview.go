@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+var previewStyle lg.Style = lg.NewStyle().Faint(true)
+
+// previewContext is the number of lines shown on either side of the item.
+const previewContext = 10
+
+// previewSplitWidth is the window width from which the preview is shown
+// beside the list, rather than below it.
+const previewSplitWidth = 120
+
+// previewBeside reports whether the preview is shown beside the list.
+func (t tui) previewBeside() bool {
+	return t.w >= previewSplitWidth
+}
+
+// previewPanel renders the lines of the selected item's source file
+// around the item, numbered, with the item's own line highlighted. The
+// panel is half the window wide beside the list, as laid out by View,
+// and otherwise is below the list and at most half its height.
+func (t tui) previewPanel(height int, beside bool) string {
+	width := t.w
+	border := lg.NewStyle().Border(themeBorder(lg.NormalBorder()), true, false, false, false)
+	if beside {
+		width = t.w / 2
+		border = lg.NewStyle().Border(themeBorder(lg.NormalBorder()), false, false, false, true)
+		width-- // for the border
+	} else {
+		height = min(2*previewContext+2, max(height/2, 5))
+		height-- // for the border
+	}
+	panel := border.Copy().
+		Width(width).
+		Height(height).
+		MaxHeight(height + 1)
+
+	current := t.currentSelection()
+	if current == nil {
+		return panel.Render("")
+	}
+
+	n := min(previewContext, max((height-1)/2, 0))
+	before, after, err := current.Surrounding(n)
+	if err != nil {
+		return panel.Render(previewStyle.Render(err.Error()))
+	}
+
+	line := func(number int, s string) string {
+		gutter := fmt.Sprintf("%4d %s ", number, glyph("│", "|"))
+		return lg.NewStyle().MaxWidth(width).Render(gutter + strings.ReplaceAll(s, "\t", "    "))
+	}
+	first := current.Line() - len(before)
+	rows := []string{}
+	for i, s := range before {
+		rows = append(rows, previewStyle.Render(line(first+i, s)))
+	}
+	rows = append(rows, lg.NewStyle().Bold(true).Reverse(true).Render(line(current.Line(), current.Raw())))
+	for i, s := range after {
+		rows = append(rows, previewStyle.Render(line(current.Line()+1+i, s)))
+	}
+	return panel.Render(strings.Join(rows, "\n"))
+}
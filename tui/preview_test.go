@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+func TestPreviewPanel(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("notes\n[ ] first\n[ ] second\nmore notes\n"), 0644)
+	items, _ := getItems(file)
+
+	m := newTUI(items, runConfig)
+	m.w, m.h = 80, 40
+	m.populateRenderSelection()
+	m.setSelection(1)
+
+	if m.previewBeside() {
+		t.Errorf("expected the preview below the list in a narrow window")
+	}
+	panel := m.previewPanel(30, false)
+	for _, line := range []string{"   1 │ notes", "   3 │ [ ] second", "   4 │ more notes"} {
+		if !strings.Contains(panel, line) {
+			t.Errorf("expected %q in the preview:\n%s", line, panel)
+		}
+	}
+
+	m.w = previewSplitWidth
+	if !m.previewBeside() {
+		t.Errorf("expected the preview beside the list in a wide window")
+	}
+	if w := len([]rune(strings.Split(m.previewPanel(30, true), "\n")[0])); w != m.w/2 {
+		t.Errorf("expected a half width preview, but found width %d", w)
+	}
+
+	// a side panel puts the preview below the list, in a wide window too
+	m.preview = true
+	m.setViewsMode()
+	if h := lg.Height(m.View()); h > m.h {
+		t.Errorf("expected the frame to fit the window's %d lines, but found %d", m.h, h)
+	}
+}
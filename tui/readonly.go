@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"github.com/nilock/tuido/tuido"
+)
+
+// itemWriteCommands are the navigation mode commands which write the
+// selected item, or the marked items.
+var itemWriteCommands = map[string]bool{
+	"done": true, "obsolete": true, "ongoing": true, "review": true, "open": true,
+	"escalate": true, "relax": true, "edit": true, "editor": true, "tag": true, "untag": true,
+	"snooze": true, "snoozeuntil": true, "delete": true, "moveup": true, "movedown": true,
+}
+
+// markCommands are the itemWriteCommands which apply to the marked items,
+// while there are any.
+var markCommands = map[string]bool{
+	"done": true, "obsolete": true, "ongoing": true, "review": true, "open": true,
+	"tag": true, "untag": true,
+}
+
+// fileWriteCommands are the other commands which write files, and are
+// refused in read-only mode.
+var fileWriteCommands = map[string]bool{
+	"new": true, "batch": true, "undo": true, "redo": true,
+}
+
+// readOnlyNotice returns the notice shown in place of running command,
+// if it would write a read-only item, or any file in read-only mode, or
+// else "".
+func (t *tui) readOnlyNotice(command string) string {
+	// custom status commands write, and apply to the marked items, as
+	// the built-in status commands do
+	_, custom := t.config.statusCommand(command)
+	writes := itemWriteCommands[command] || custom
+	if tuido.ReadOnly && (writes || fileWriteCommands[command]) {
+		return "read-only: tuido was started with -read-only"
+	}
+	if !writes {
+		return ""
+	}
+
+	// status and tag keys apply to the marked items, if there are any
+	items := []*tuido.Item{t.currentSelection()}
+	if marked := t.markedItems(); len(marked) > 0 && (markCommands[command] || custom) {
+		items = marked
+	}
+	for _, item := range items {
+		if item == nil || !item.ReadOnly() {
+			return ""
+		}
+	}
+	return "read-only: " + items[0].Location() + " cannot be changed"
+}
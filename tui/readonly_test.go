@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestReadOnly(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] first\n[ ] second\n"), 0644)
+	items, _ := getItems(file)
+	items[0].SetReadOnly()
+
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = model.(tui)
+	if !strings.HasPrefix(m.notice, "read-only: ") {
+		t.Errorf("expected a read-only notice, but found %q", m.notice)
+	}
+	expectContent(t, file, "[ ] first\n[ ] second\n")
+
+	// in read-only mode, no file is written
+	tuido.ReadOnly = true
+	defer func() { tuido.ReadOnly = false }()
+	m.setSelection(1)
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = model.(tui)
+	if m.mode != navigation || !strings.Contains(m.notice, "-read-only") {
+		t.Errorf("expected new items refused, but found mode %d and notice %q", m.mode, m.notice)
+	}
+	if err := items[1].SetStatus(tuido.Checked); err == nil {
+		t.Errorf("expected writes refused in read-only mode")
+	}
+	if _, err := tuido.Create(file, tuido.Open, "third"); err == nil {
+		t.Errorf("expected new items refused in read-only mode")
+	}
+	expectContent(t, file, "[ ] first\n[ ] second\n")
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// unchecked returns those of items which are not checked.
+func unchecked(items []*tuido.Item) []*tuido.Item {
+	ret := []*tuido.Item{}
+	for _, item := range items {
+		if item != nil && item.Satus() != tuido.Checked {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+// recur appends the next occurrence of each recurring item of items which
+// is now checked, to its file and to the list. The new items join the
+// latest undo batch, so that undoing the check removes them too.
+func (t *tui) recur(items []*tuido.Item) error {
+	var firstErr error
+	changes := []change{}
+	for _, item := range items {
+		if item.Satus() != tuido.Checked {
+			continue
+		}
+		next, err := item.Recur()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if next == nil {
+			continue
+		}
+		t.items = append(t.items, next)
+		changes = append(changes, change{next, created, ""})
+		t.notice = fmt.Sprintf("recurs: %s", next.String())
+	}
+	if len(changes) == 0 {
+		return firstErr
+	}
+	if len(changes) > 1 {
+		t.notice = fmt.Sprintf("%d items recur", len(changes))
+	}
+	t.undo.amend(changes)
+
+	renest := map[string]bool{}
+	for _, c := range changes {
+		renest[c.item.File()] = true
+	}
+	for file := range renest {
+		siblings := []*tuido.Item{}
+		for _, item := range t.items {
+			if item.File() == file {
+				siblings = append(siblings, item)
+			}
+		}
+		sort.SliceStable(siblings, func(a, b int) bool { return siblings[a].Line() < siblings[b].Line() })
+		tuido.Nest(siblings)
+	}
+	t.populateRenderSelection()
+	return firstErr
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestRecurOnCheck(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] water plants every:2d #due=2024-01-31\n"), 0644)
+	items, _ := getItems(file)
+
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.setStatus(tuido.Checked)
+
+	if len(m.items) != 2 {
+		t.Fatalf("expected the next occurrence listed, but found %d items", len(m.items))
+	}
+	next := m.items[1]
+	if next.Raw() != "[ ] water plants every:2d #due=2024-02-02" || next.Line() != 2 {
+		t.Errorf("unexpected next occurrence %q on line %d", next.Raw(), next.Line())
+	}
+
+	// checking an item already checked does not recur again
+	m.setSelection(len(m.renderSelection) - 1)
+	m.setItemsStatus([]*tuido.Item{items[0]}, tuido.Checked)
+	if len(m.items) != 2 {
+		t.Errorf("expected no further occurrence, but found %d items", len(m.items))
+	}
+
+	// undoing the first check removes the next occurrence too
+	m.applyUndo(false)
+	m.applyUndo(false)
+	expectContent(t, file, "[ ] water plants every:2d #due=2024-01-31\n")
+}
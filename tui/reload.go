@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+// fileChangedMsg reports that a file has changed on disk, and that its
+// items should be re-read.
+//
+// Background watchers must not touch t.items directly. Instead, they
+// send a fileChangedMsg into the program, and the reload is applied
+// from Update, in the bubbletea message loop.
+type fileChangedMsg struct {
+	file string
+}
+
+// fileChanged returns a tea.Cmd which reports a change to file.
+func fileChanged(file string) tea.Cmd {
+	return func() tea.Msg {
+		return fileChangedMsg{file}
+	}
+}
+
+// reloadFile replaces the in-memory items of file with a fresh read
+// from disk, keeping the current selection where possible.
+func (t *tui) reloadFile(file string) {
+	current := t.currentSelection()
+	var currentText string
+	if current != nil {
+		currentText = current.Text()
+	}
+
+	kept := []*tuido.Item{}
+	for _, item := range t.items {
+		if item.File() != file {
+			kept = append(kept, item)
+		}
+	}
+
+	if items, err := getItems(file); err == nil {
+		t.remark(file, items)
+		kept = append(kept, items...)
+	} else if !os.IsNotExist(err) {
+		t.err = err
+	}
+	t.items = kept
+
+	t.refreshTagColors()
+	t.populateRenderSelection()
+
+	// re-select the previously selected item, which may have been
+	// replaced by a fresh read of the same line
+	for i, item := range t.renderSelection {
+		if item == current ||
+			(current != nil && item.File() == current.File() && item.Text() == currentText) {
+			t.setSelection(i)
+			return
+		}
+	}
+}
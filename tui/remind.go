@@ -0,0 +1,239 @@
+package tui
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// reminder is a notification due for an item: "due", on its due date, or
+// "overdue", after it.
+type reminder struct {
+	item *tuido.Item
+	kind string
+}
+
+// key identifies the reminder among those already sent. The due date is
+// part of it, so that an item moved to a later date is reminded afresh.
+func (r reminder) key() string {
+	return r.item.ID() + " " + r.kind + " " + r.item.Due().Format("2006-01-02")
+}
+
+func (r reminder) title() string {
+	if r.kind == "overdue" {
+		return "tuido: overdue since " + r.item.Due().Format("2006-01-02")
+	}
+	return "tuido: due today"
+}
+
+// dueReminders returns the reminders of the pending items, active as of
+// now, which are due on the day of now, or overdue.
+func dueReminders(items []*tuido.Item, now time.Time) []reminder {
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	reminders := []reminder{}
+	for _, item := range items {
+		if item.Satus().Finished() || item.Due() == nil {
+			continue
+		}
+		if until := item.SnoozedUntil(); until != nil && !until.Before(now) {
+			continue
+		}
+		switch {
+		case item.Due().Before(today):
+			reminders = append(reminders, reminder{item, "overdue"})
+		case item.Due().Equal(today):
+			reminders = append(reminders, reminder{item, "due"})
+		}
+	}
+	return reminders
+}
+
+// parseQuietHours reads a `22-7` range of hours, from the first hour up
+// to (not including) the second, wrapping past midnight if the first is
+// the later.
+func parseQuietHours(s string) (int, int, error) {
+	split := strings.SplitN(s, "-", 2)
+	if len(split) != 2 {
+		return 0, 0, fmt.Errorf("expected a range of hours, eg 22-7")
+	}
+	from, err := strconv.Atoi(split[0])
+	if err != nil || from < 0 || from > 23 {
+		return 0, 0, fmt.Errorf("expected an hour from 0 to 23, not %s", split[0])
+	}
+	to, err := strconv.Atoi(split[1])
+	if err != nil || to < 0 || to > 24 {
+		return 0, 0, fmt.Errorf("expected an hour from 0 to 24, not %s", split[1])
+	}
+	return from, to, nil
+}
+
+// quiet reports whether now is within the quiet hours, eg `22-7`. An
+// empty range is never quiet.
+func quiet(hours string, now time.Time) bool {
+	from, to, err := parseQuietHours(hours)
+	if hours == "" || err != nil {
+		return false
+	}
+	h := now.Hour()
+	if from <= to {
+		return h >= from && h < to
+	}
+	return h >= from || h < to
+}
+
+// quietHours returns the quiet hours of item: the value of its `#quiet`
+// tag, or else the quiethours config. A `#quiet` tag without a value
+// silences the item's reminders altogether.
+func quietHours(item *tuido.Item, cfg config) (string, bool) {
+	for _, tag := range item.Tags() {
+		if tag.Name() == "quiet" {
+			if tag.Value() == "" {
+				return "", true
+			}
+			return tag.Value(), false
+		}
+	}
+	return cfg.quietHours, false
+}
+
+// sentReminders are the keys of the reminders already sent, with the
+// date each was sent.
+type sentReminders map[string]string
+
+// remindedPath returns the location of the sent reminders, or "" if there
+// is no user cache directory.
+func remindedPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tuido", "reminded")
+}
+
+// loadReminded reads the sent reminders from path. A missing or unreadable
+// file is no reminders.
+func loadReminded(path string) sentReminders {
+	sent := sentReminders{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &sent)
+	}
+	return sent
+}
+
+func (sent sentReminders) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(sent)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// remind sends the reminders of items which haven't been sent, outside of
+// each item's quiet hours, and prints each one sent. Reminders held by
+// quiet hours are sent by a later call. Sent reminders of items which are
+// no longer due are forgotten. It returns the number of notifications
+// which failed.
+func remind(items []*tuido.Item, sent sentReminders, cfg config, now time.Time, notify func(title, body string) error) int {
+	failed := 0
+	due := map[string]bool{}
+	for _, r := range dueReminders(items, now) {
+		due[r.key()] = true
+		if _, ok := sent[r.key()]; ok {
+			continue
+		}
+		hours, silenced := quietHours(r.item, cfg)
+		if silenced || quiet(hours, now) {
+			continue
+		}
+		if err := notify(r.title(), r.item.Text()); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", r.item.Location(), err)
+			failed++
+			continue
+		}
+		sent[r.key()] = now.Format("2006-01-02")
+		fmt.Printf("%s  %s  %s  (%s)\n", r.item.ID(), r.item.String(), r.item.Location(), r.kind)
+	}
+	for key := range sent {
+		if !due[key] {
+			delete(sent, key)
+		}
+	}
+	return failed
+}
+
+// remindCommand notifies the desktop of items as they fall due, and as
+// they become overdue, re-scanning every few minutes until interrupted:
+// `tuido remind [-every 5m] [-once] [path...]`.
+func remindCommand(fs *flag.FlagSet) func(args []string) int {
+	everyFlag := fs.Duration("every", 5*time.Minute, "the time between scans")
+	onceFlag := fs.Bool("once", false, "scan and notify once, and exit, eg to run from cron")
+	return func(args []string) int {
+		ws := openWorkspace(args)
+		defer ws.close()
+		path := remindedPath()
+		sent := loadReminded(path)
+		for {
+			code := 0
+			if remind(ws.items(), sent, runConfig, time.Now(), desktopNotify) > 0 {
+				code = 1
+			}
+			if err := sent.save(path); err != nil {
+				fmt.Fprintf(os.Stderr, "error saving sent reminders: %s\n", err)
+			}
+			if *onceFlag {
+				return code
+			}
+			time.Sleep(*everyFlag)
+			ws.files = ws.listFiles()
+		}
+	}
+}
+
+// desktopNotify shows a desktop notification: with notify-send, or with
+// osascript on macOS, or as a PowerShell balloon tip on Windows.
+func desktopNotify(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", windowsNotifyScript)
+		cmd.Env = append(os.Environ(), "TUIDO_TITLE="+title, "TUIDO_BODY="+body)
+	default:
+		cmd = exec.Command("notify-send", "--app-name=tuido", title, body)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// windowsNotifyScript shows $TUIDO_TITLE and $TUIDO_BODY as a balloon tip,
+// which Windows 10 and later show as a toast.
+const windowsNotifyScript = `Add-Type -AssemblyName System.Windows.Forms, System.Drawing
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, $env:TUIDO_TITLE, $env:TUIDO_BODY, 'Info')
+Start-Sleep -Seconds 10
+$n.Dispose()`
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRemind(t *testing.T) {
+	today := "2024-05-01"
+	items := newItems(
+		"[ ] pay rent #due="+today,
+		"[ ] file taxes #due=2022-04-15",
+		"[ ] later #due=2999-01-01",
+		"[x] done #due="+today,
+		"[ ] water plants #due="+today+" #quiet",
+		"[ ] call back #due="+today+" #quiet=0-24",
+	)
+	cfg := runConfig
+	cfg.quietHours = "22-7"
+
+	notified := []string{}
+	notify := func(title, body string) error {
+		notified = append(notified, title+": "+body)
+		return nil
+	}
+
+	// quiet hours hold every reminder
+	night := time.Date(2024, 5, 1, 23, 0, 0, 0, time.Local)
+	sent := sentReminders{}
+	remind(items, sent, cfg, night, notify)
+	if len(notified) != 0 {
+		t.Errorf("expected no notifications in quiet hours, but found %v", notified)
+	}
+
+	noon := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	remind(items, sent, cfg, noon, notify)
+	expected := []string{"tuido: due today: pay rent #due=" + today, "tuido: overdue since 2022-04-15: file taxes #due=2022-04-15"}
+	if len(notified) != 2 || notified[0] != expected[0] || notified[1] != expected[1] {
+		t.Errorf("expected %v, but found %v", expected, notified)
+	}
+
+	// each reminder is sent once, and forgotten once its item is done
+	remind(items, sent, cfg, noon, notify)
+	if len(notified) != 2 {
+		t.Errorf("expected reminders sent once, but found %v", notified)
+	}
+	remind(items[1:], sent, cfg, noon, notify)
+	if len(sent) != 1 {
+		t.Errorf("expected the reminder of the missing item forgotten, but found %v", sent)
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2022, 1, 1, h, 0, 0, 0, time.Local) }
+	for hours, expected := range map[string][]bool{
+		"22-7": {true, true, false, false, true},
+		"9-17": {false, false, true, false, false},
+		"":     {false, false, false, false, false},
+	} {
+		for n, h := range []int{0, 6, 12, 17, 22} {
+			if quiet(hours, at(h)) != expected[n] {
+				t.Errorf("expected quiet(%q) at %d:00 to be %t", hours, h, expected[n])
+			}
+		}
+	}
+	if _, _, err := parseQuietHours("25-3"); err == nil {
+		t.Errorf("expected an error for an hour past 23")
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nilock/tuido/tuido"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// remoteDir is a directory scanned over SFTP. Items read from a remote
+// directory are read-only.
+type remoteDir struct {
+	host string
+	root string
+
+	client *sftp.Client
+	conn   *ssh.Client
+}
+
+// isRemote reports whether dir is an sftp:// URL rather than a local path.
+func isRemote(dir string) bool {
+	return strings.HasPrefix(dir, "sftp://")
+}
+
+// dialRemote connects to the sftp://[user@]host[:port]/path dir.
+//
+// Authentication is via the running ssh-agent, or else the user's
+// default private keys. The host must be present in ~/.ssh/known_hosts.
+func dialRemote(dir string) (*remoteDir, error) {
+	u, err := url.Parse(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	username := u.User.Username()
+	if username == "" {
+		if current, err := user.Current(); err == nil {
+			username = current.Username
+		}
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+
+	home, _ := os.UserHomeDir()
+	hostKeys, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("reading known_hosts: %w", err)
+	}
+
+	conn, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            username,
+		Auth:            sshAuthMethods(home),
+		HostKeyCallback: hostKeys,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	root := u.Path
+	if root == "" {
+		root = "."
+	}
+
+	return &remoteDir{
+		host:   u.Hostname(),
+		root:   root,
+		client: client,
+		conn:   conn,
+	}, nil
+}
+
+func sshAuthMethods(home string) []ssh.AuthMethod {
+	methods := []ssh.AuthMethod{}
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if a, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(a).Signers))
+		}
+	}
+
+	signers := []ssh.Signer{}
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		key, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+		if err != nil {
+			continue
+		}
+		if signer, err := ssh.ParsePrivateKey(key); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	return methods
+}
+
+func (r *remoteDir) Close() {
+	r.client.Close()
+	r.conn.Close()
+}
+
+// getFiles lists the files under the remote root with a parsed extension.
+func (r *remoteDir) getFiles(extensions []string) []string {
+	files := []string{}
+
+	walker := r.client.Walk(r.root)
+	for walker.Step() {
+		if walker.Err() != nil {
+			skipped.skip(r.label(walker.Path()), "%s", walker.Err())
+			continue
+		}
+		if walker.Stat().IsDir() {
+			continue
+		}
+		if tuido.HasExtension(walker.Path(), extensions) {
+			files = append(files, walker.Path())
+		}
+	}
+	return files
+}
+
+// getItems fetches and parses a remote file. The items are labelled with
+// the file's host:path location, and are read-only.
+func (r *remoteDir) getItems(file string) []*tuido.Item {
+	f, err := r.client.Open(file)
+	if err != nil {
+		skipped.skip(r.label(file), "%s", err)
+		return []*tuido.Item{}
+	}
+	defer f.Close()
+
+	items := readItems(r.label(file), f)
+	for _, item := range items {
+		item.SetReadOnly()
+	}
+	return items
+}
+
+func (r *remoteDir) label(file string) string {
+	return r.host + ":" + path.Clean(file)
+}
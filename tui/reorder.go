@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// siblingNeighbor returns the sibling before (by -1) or after (by 1)
+// item in its file: the nearest item of the same parent, or, of items
+// with no parent, the nearest one with none. It is nil if there is none.
+func (t tui) siblingNeighbor(item *tuido.Item, by int) *tuido.Item {
+	var neighbor *tuido.Item
+	for _, i := range t.items {
+		if i.File() != item.File() || i == item || i.Parent() != item.Parent() {
+			continue
+		}
+		before := i.Line() < item.Line()
+		if by < 0 && before && (neighbor == nil || i.Line() > neighbor.Line()) ||
+			by > 0 && !before && (neighbor == nil || i.Line() < neighbor.Line()) {
+			neighbor = i
+		}
+	}
+	return neighbor
+}
+
+// subtree returns item and its subtasks, nested to any depth, in line
+// order.
+func subtree(item *tuido.Item) []*tuido.Item {
+	items := []*tuido.Item{item}
+	for _, child := range item.Children() {
+		items = append(items, subtree(child)...)
+	}
+	return items
+}
+
+// moveItem moves the selected item up (by -1) or down (by 1) past its
+// neighboring sibling in its file, each with its subtasks. The item
+// lines of the two subtrees are rewritten in place, in their new order,
+// so the other lines among them, eg blank lines and headings, stay where
+// they are, and the move is undone as one change. The selection, and any
+// marks, follow the moved items. Items of code comments are not moved,
+// as their lines are among the code's.
+func (t *tui) moveItem(by int) {
+	current := t.currentSelection()
+	if current == nil {
+		return
+	}
+	if current.InCode() {
+		t.err = fmt.Errorf("cannot reorder the comments of code in %s", current.File())
+		return
+	}
+	neighbor := t.siblingNeighbor(current, by)
+	if neighbor == nil {
+		t.notice = "already the first item in " + current.File()
+		if by > 0 {
+			t.notice = "already the last item in " + current.File()
+		}
+		return
+	}
+
+	// slots are the lines of both subtrees, in file order, which take the
+	// moved subtree's lines and the displaced subtree's, in their new order
+	moved, displaced := subtree(current), subtree(neighbor)
+	slots := append(append([]*tuido.Item{}, displaced...), moved...)
+	reordered := append(append([]*tuido.Item{}, moved...), displaced...)
+	if by > 0 {
+		slots, reordered = reordered, slots
+	}
+	for _, item := range displaced {
+		if item.ReadOnly() {
+			t.err = fmt.Errorf("item is read-only - cannot move past %s", item.Location())
+			return
+		}
+	}
+
+	raws := []string{}
+	marks := []bool{}
+	for _, item := range reordered {
+		raws = append(raws, item.Raw())
+		marks = append(marks, t.marked[item])
+	}
+	err := t.track(slots, func() error {
+		return tuido.Batch(func() error {
+			for n, item := range slots {
+				if err := item.SetRaw(raws[n]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.err = err
+		return
+	}
+	for n, item := range slots {
+		if marks[n] {
+			t.marked[item] = true
+		} else {
+			delete(t.marked, item)
+		}
+	}
+
+	// the moved item's line now belongs to the item of the first slot
+	// its subtree took
+	selected := slots[0]
+	if by > 0 {
+		selected = slots[len(displaced)]
+	}
+	t.renest(current.File())
+	t.populateRenderSelection()
+	t.selectItem(selected)
+	t.touch()
+	if t.sortMode != sortFile && t.sortMode != sortPriority {
+		t.notice = fmt.Sprintf("moved to %s - sort by file (o) to list items in file order", selected.Location())
+	}
+}
+
+// renest re-reads the subtask nesting of file's items from their lines.
+func (t *tui) renest(file string) {
+	items := []*tuido.Item{}
+	for _, item := range t.items {
+		if item.File() == file {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool { return items[a].Line() < items[b].Line() })
+	tuido.Nest(items)
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMoveItem(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] first\n\n[ ] second\n[ ] third\n"), 0644)
+	items, _ := getItems(file)
+	cfg := runConfig
+	cfg.sort = "file"
+	m := newTUI(items, cfg)
+	m.populateRenderSelection()
+	m.setSelection(2)
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlUp})
+	m = model.(tui)
+	expectContent(t, file, "[ ] first\n\n[ ] third\n[ ] second\n")
+	if m.currentSelection().Text() != "third" || m.selection != 1 {
+		t.Errorf("expected the selection to follow the moved item, but found %q", m.currentSelection().Text())
+	}
+
+	m.moveItem(-1)
+	expectContent(t, file, "[ ] third\n\n[ ] first\n[ ] second\n")
+	m.moveItem(-1)
+	if m.notice == "" {
+		t.Errorf("expected a notice that the first item cannot move up")
+	}
+
+	m.applyUndo(false)
+	expectContent(t, file, "[ ] first\n\n[ ] third\n[ ] second\n")
+}
+
+func TestMoveSubtree(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] beach\n  [ ] towels\n[ ] pack\n  [ ] sunscreen\n    [ ] spf 50\n"), 0644)
+	items, _ := getItems(file)
+	cfg := runConfig
+	cfg.sort = "file"
+	m := newTUI(items, cfg)
+	m.populateRenderSelection()
+	m.selectItem(items[2])
+
+	// the item moves with its subtasks, past the whole of its sibling's
+	m.moveItem(-1)
+	expectContent(t, file, "[ ] pack\n  [ ] sunscreen\n    [ ] spf 50\n[ ] beach\n  [ ] towels\n")
+	if m.currentSelection().Text() != "pack" {
+		t.Errorf("expected the selection to follow the moved item, but found %q", m.currentSelection().Text())
+	}
+	towels := m.items[len(m.items)-1]
+	if towels.Text() != "towels" || towels.Parent() == nil || towels.Parent().Text() != "beach" {
+		t.Errorf("expected towels to stay a subtask of beach, but found %q under %v", towels.Text(), towels.Parent())
+	}
+
+	m.moveItem(1)
+	expectContent(t, file, "[ ] beach\n  [ ] towels\n[ ] pack\n  [ ] sunscreen\n    [ ] spf 50\n")
+
+	// a subtask moves only among its siblings
+	m.selectItem(items[1])
+	m.moveItem(-1)
+	if m.notice == "" {
+		t.Errorf("expected a notice that the first subtask cannot move up")
+	}
+	expectContent(t, file, "[ ] beach\n  [ ] towels\n[ ] pack\n  [ ] sunscreen\n    [ ] spf 50\n")
+}
+
+func TestMoveCodeComment(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "main.go")
+	content := "// [ ] first\nfunc main() {}\n// [ ] second\n"
+	os.WriteFile(file, []byte(content), 0644)
+	items, _ := getItems(file)
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.selectItem(items[1])
+
+	m.moveItem(-1)
+	if m.err == nil {
+		t.Errorf("expected an error moving a code comment")
+	}
+	expectContent(t, file, content)
+}
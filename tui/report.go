@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// scanSkip records a file, or part of a file, which was not scanned.
+type scanSkip struct {
+	file   string
+	reason string
+}
+
+// scanReport collects the files skipped while scanning for items.
+type scanReport struct {
+	mu    sync.Mutex
+	skips []scanSkip
+}
+
+// skipped is the report for the current run.
+var skipped scanReport
+
+func (r *scanReport) skip(file string, format string, a ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.skips = append(r.skips, scanSkip{file, fmt.Sprintf(format, a...)})
+}
+
+// has reports whether file, or part of it, was skipped.
+func (r *scanReport) has(file string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.skips {
+		if s.file == file {
+			return true
+		}
+	}
+	return false
+}
+
+// print writes the report, one skipped file per line.
+func (r *scanReport) print(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.skips {
+		fmt.Fprintf(w, "%s: %s\n", s.file, s.reason)
+	}
+	fmt.Fprintf(w, "%d files skipped\n", len(r.skips))
+}
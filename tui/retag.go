@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// tagName reads a tag name as typed, with or without its sigil. It is an
+// error if the name is empty, or has a space or an `=`.
+func tagName(typed string) (string, error) {
+	name := strings.TrimPrefix(strings.TrimSpace(typed), tuido.TagSigil)
+	if name == "" || strings.ContainsAny(name, " \t=") {
+		return "", fmt.Errorf("%q is not a tag name", typed)
+	}
+	return name, nil
+}
+
+// tagged returns the items of items with the named tag.
+func tagged(items []*tuido.Item, name string) []*tuido.Item {
+	matched := []*tuido.Item{}
+	for _, item := range items {
+		for _, tag := range item.Tags() {
+			if tag.Name() == name {
+				matched = append(matched, item)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// renameTag renames tag from to to on each of items, writing each file
+// once. Items which no longer have the tag, as they were renamed by an
+// earlier attempt at the change, are passed over. Items which can't be
+// written are left as they were, and every such error is returned.
+func renameTag(items []*tuido.Item, from, to string) error {
+	errs := []error{}
+	err := tuido.Batch(func() error {
+		for _, item := range items {
+			if len(tagged([]*tuido.Item{item}, from)) == 0 {
+				continue
+			}
+			if err := item.RenameTag(from, to); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return nil
+	})
+	switch {
+	case len(errs) == 1:
+		return errs[0]
+	case len(errs) > 1:
+		others := []string{}
+		for _, e := range errs[1:] {
+			others = append(others, e.Error())
+		}
+		return fmt.Errorf("%d items were not renamed: %w; %s", len(errs), errs[0], strings.Join(others, "; "))
+	}
+	return err
+}
+
+// renameLegendTag renames the tag selected in the legend, on every item,
+// as one undoable change, and re-selects it by its new name.
+func (t *tui) renameLegendTag(typed string) error {
+	from := t.legend.selected()
+	to, err := tagName(typed)
+	if err != nil || from == "" || to == from {
+		return err
+	}
+
+	items := tagged(t.items, from)
+	err = t.track(items, func() error { return renameTag(items, from, to) })
+	t.refreshTagColors()
+	t.populateRenderSelection()
+
+	t.legend = newTagLegend(t.tagColors, t.items)
+	for i, name := range t.legend.tags {
+		if name == to {
+			t.legend.cursor = i
+		}
+	}
+	if err == nil {
+		t.notice = fmt.Sprintf("renamed %s%s to %s%s on %d items", tuido.TagSigil, from, tuido.TagSigil, to, len(items))
+	}
+	return err
+}
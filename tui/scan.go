@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// loadItems parses files with a pool of workers, one per CPU. Items are
+// returned ordered by file path, then by line, regardless of the order
+// in which the files are parsed. Unreadable files are recorded as
+// skipped, and files unchanged since they were indexed by the itemCache
+// are not parsed again. If progress is non-nil, it is called with the count of files
+// parsed so far after each file, from the workers.
+func loadItems(files []string, progress func(done int)) []*tuido.Item {
+	sorted := append([]string{}, files...)
+	sort.Strings(sorted)
+
+	results := make([][]*tuido.Item, len(sorted))
+	jobs := make(chan int)
+	var done int64
+
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				items, err := itemCache.getItems(sorted[i])
+				if err != nil {
+					skipped.skip(sorted[i], "unreadable: %s", err)
+				} else {
+					results[i] = items
+				}
+				if progress != nil {
+					progress(int(atomic.AddInt64(&done, 1)))
+				}
+			}
+		}()
+	}
+
+	for i := range sorted {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	items := []*tuido.Item{}
+	for _, r := range results {
+		items = append(items, r...)
+	}
+	return items
+}
+
+// workspace is a configured scan: its root, the local files to parse,
+// and any remote directory.
+type workspace struct {
+	root   string
+	files  []string
+	remote *remoteDir
+
+	// dirs and named are the scanned directories and the files named by
+	// arguments, from which files is listed.
+	dirs  []string
+	named []string
+}
+
+// openWorkspace reads the configuration and flags, and lists the files to
+// scan: those under the -dir directories and the directories and files
+// named by args, or else under the configured dirs or the working
+// directory. It exits on error.
+func openWorkspace(args []string) workspace {
+	wdStr, err := os.Getwd() // [ ] only from cli flag? YES! or... follow .gitignore
+
+	if err != nil {
+		fmt.Printf("error reading the working directory: %s\n", err)
+		os.Exit(1)
+	}
+
+	// local -dir directories are scanned as directory arguments are, and
+	// the configured dirs stand in for both when neither is given
+	dirs := dirFlag
+	if len(dirs) == 0 && len(args) == 0 {
+		for _, dir := range runConfig.dirs {
+			if !isRemote(dir) {
+				dir = resolvePath(wdStr, dir)
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+	remoteDirs, localDirs := dirs.split()
+	if len(remoteDirs) > 1 {
+		fmt.Println("only one remote -dir can be scanned")
+		os.Exit(1)
+	}
+	scanArgs := append(localDirs, args...)
+
+	paths, err := parsePathArgs(scanArgs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(paths.dirs) > 0 {
+		wdStr = paths.dirs[0]
+	}
+
+	var remote *remoteDir
+	if len(remoteDirs) == 1 {
+		remote, err = dialRemote(remoteDirs[0])
+		if err != nil {
+			fmt.Printf("connecting to %s: %s\n", remoteDirs[0], err)
+			os.Exit(1)
+		}
+	}
+
+	configure(wdStr)
+	if !*noCacheFlag {
+		itemCache = loadIndex(indexPath())
+	}
+
+	if _, err := os.Stat(runConfig.writeto); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	scanDirs := paths.dirs
+	if len(scanArgs) == 0 && remote == nil {
+		scanDirs = []string{wdStr}
+	}
+	for _, dir := range scanDirs {
+		if !confirmScanRoot(dir) {
+			os.Exit(1)
+		}
+	}
+
+	ws := workspace{root: wdStr, remote: remote, dirs: scanDirs, named: paths.files}
+	ws.files = ws.listFiles()
+	return ws
+}
+
+// listFiles lists the local files to parse: those of the global
+// directory, of the scanned directories, and the named files.
+func (ws workspace) listFiles() []string {
+	files := []string{}
+	// named files are scanned alone, without the global directory
+	if runConfig.global != "" && len(ws.named) == 0 {
+		files = append(files, getFiles(runConfig.global, runConfig.extensions)...)
+	}
+	for _, dir := range ws.dirs {
+		// [ ] replace with subdir check #active=2022-05-26 #zzz=2
+		if dir != runConfig.global {
+			files = append(files, getFiles(dir, runConfig.extensions)...)
+		}
+	}
+	return append(files, ws.named...)
+}
+
+// configure applies the nearest project config to root, and then the
+// flags, over the user config.
+func configure(root string) {
+	adoptConfigSettings(findProjectConfig(root))
+	adoptFlagSettings()
+	tuido.TagSigil = runConfig.tagSigil
+	tuido.Markers = runConfig.markers
+	tuido.CustomStatuses = customStatuses(runConfig.statuses)
+	tuido.TodoComments = runConfig.todoComments
+	tuido.StampCompleted, tuido.StampCreated = false, false
+	for _, stamp := range runConfig.stamps {
+		tuido.StampCompleted = tuido.StampCompleted || stamp == "completed"
+		tuido.StampCreated = tuido.StampCreated || stamp == "created"
+	}
+	tuido.ReadOnly = *readOnlyFlag
+	runConfig.root = root
+	runConfig.resolveTargets(root)
+	applyTheme(runConfig.resolveTheme())
+}
+
+// items parses the items of the workspace's files, and of any remote
+// directory.
+func (ws workspace) items() []*tuido.Item {
+	return ws.load(nil)
+}
+
+// load is items, reporting the progress of local files to progress, if
+// non-nil.
+func (ws workspace) load(progress func(done int)) []*tuido.Item {
+	items := loadItems(ws.files, progress)
+	if err := itemCache.save(); err != nil {
+		skipped.skip(itemCache.path, "index cache not saved: %s", err)
+	}
+	if ws.remote != nil {
+		for _, f := range ws.remote.getFiles(runConfig.extensions) {
+			items = append(items, ws.remote.getItems(f)...)
+		}
+	}
+	return items
+}
+
+func (ws workspace) close() {
+	if ws.remote != nil {
+		ws.remote.Close()
+	}
+}
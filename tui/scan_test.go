@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+// writeTree writes a synthetic tree of dirs*files files, each holding
+// items items.
+func writeTree(tb testing.TB, dirs, files, items int) string {
+	root := tb.TempDir()
+	lines := []string{}
+	for i := 0; i < items; i++ {
+		lines = append(lines, fmt.Sprintf("[ ] item %d #tag%d", i, i%7), "some prose between items")
+	}
+	content := []byte(strings.Join(lines, "\n") + "\n")
+
+	for d := 0; d < dirs; d++ {
+		dir := filepath.Join(root, fmt.Sprintf("dir%03d", d))
+		os.MkdirAll(dir, 0777)
+		for f := 0; f < files; f++ {
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("notes%03d.md", f)), content, 0644); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	return root
+}
+
+func TestLoadItemsIsOrdered(t *testing.T) {
+	root := writeTree(t, 5, 5, 3)
+	files := getFiles(root, []string{"md"})
+
+	// reverse the files, to check that order comes from the paths
+	for i, j := 0, len(files)-1; i < j; i, j = i+1, j-1 {
+		files[i], files[j] = files[j], files[i]
+	}
+
+	items := loadItems(files, nil)
+	if len(items) != 5*5*3 {
+		t.Fatalf("expected %d items, but found %d", 5*5*3, len(items))
+	}
+	for i := 1; i < len(items); i++ {
+		prev, cur := items[i-1], items[i]
+		if prev.File() > cur.File() || (prev.File() == cur.File() && prev.Line() >= cur.Line()) {
+			t.Fatalf("expected items ordered by file and line, but %s precedes %s", prev.Location(), cur.Location())
+		}
+	}
+}
+
+func BenchmarkLoadItems(b *testing.B) {
+	root := writeTree(b, 20, 50, 20)
+	files := getFiles(root, []string{"md"})
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		loadItems(files, nil)
+	}
+}
+
+func TestBackgroundLoad(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "todo.xit")
+	os.WriteFile(file, []byte("[ ] first\n"), 0644)
+	items := loadItems([]string{file}, nil)
+
+	m := newTUI(nil, runConfig)
+	m.startLoading(1)
+
+	// a change to the file during the scan is applied once it completes
+	os.WriteFile(file, []byte("[ ] first\n[ ] second\n"), 0644)
+	model, _ := m.Update(fileChangedMsg{file})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	model, _ = model.Update(itemsLoadedMsg{items})
+	m = model.(tui)
+
+	if m.loading {
+		t.Fatal("expected loading to end with the loaded items")
+	}
+	if len(m.renderSelection) != 2 {
+		t.Fatalf("expected both items listed, but found %d", len(m.renderSelection))
+	}
+	if m.renderSelection[0].Satus() != tuido.Open {
+		t.Errorf("expected keys to be ignored while loading")
+	}
+}
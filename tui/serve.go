@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// server serves the scanned items as JSON, and as an HTML list. Items are
+// re-scanned for each request, so that the responses follow the files as
+// they are edited by others.
+type server struct {
+	// load scans the items.
+	load func() []*tuido.Item
+	// write allows requests which change items.
+	write bool
+
+	// mu serializes scans with writes, so that a write is never made to
+	// an item which a concurrent write has moved.
+	mu sync.Mutex
+}
+
+// serveCommand serves the scanned items over HTTP until interrupted:
+// `tuido serve [-port 8080] [-write] [path...]`.
+func serveCommand(fs *flag.FlagSet) func(args []string) int {
+	portFlag := fs.Int("port", 8080, "the port to listen on")
+	addrFlag := fs.String("addr", "localhost", "the address to listen on. Use 0.0.0.0 to serve other machines")
+	writeFlag := fs.Bool("write", false, "allow requests which change items: status changes, and new items")
+	return func(args []string) int {
+		ws := openWorkspace(args)
+		defer ws.close()
+		s := &server{load: ws.items, write: *writeFlag}
+
+		addr := fmt.Sprintf("%s:%d", *addrFlag, *portFlag)
+		fmt.Printf("serving %s on http://%s\n", runConfig.root, addr)
+		if err := http.ListenAndServe(addr, s); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+}
+
+// ServeHTTP routes the API:
+//
+//	GET  /                     the HTML list, of ?filter= and ?done=1
+//	GET  /api/items            the listed items, as a JSON array
+//	POST /api/items            creates an item of {"text": ...}
+//	POST /api/items/<id>       sets the status of {"status": ...}
+//
+// Items are listed as by `tuido list`: the pending items, or with done=1
+// the done ones, matching the filter, which is written as at the filter
+// prompt.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/" && r.Method == http.MethodGet:
+		s.page(w, r)
+	case r.URL.Path == "/api/items" && r.Method == http.MethodGet:
+		data, err := tuido.JSON(s.listed(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(append(data, '\n'))
+	case strings.HasPrefix(r.URL.Path, "/api/items") && r.Method == http.MethodPost:
+		if !s.write {
+			http.Error(w, "read-only: run tuido serve -write to allow changes", http.StatusForbidden)
+			return
+		}
+		if err := checkWriteRequest(r); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/items"), "/")
+		s.change(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// checkWriteRequest refuses requests to change items which a web page of
+// another site could have made on the user's behalf: those not of JSON,
+// which browsers send cross-origin only with the server's consent, and
+// those of a browser at another origin.
+func checkWriteRequest(r *http.Request) error {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		return fmt.Errorf("expected a Content-Type of application/json")
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+			return fmt.Errorf("cross-origin requests cannot change items")
+		}
+	}
+	return nil
+}
+
+// listed returns the items requested by r's filter and done parameters.
+func (s *server) listed(r *http.Request) []*tuido.Item {
+	t := newTUI(s.load(), runConfig)
+	if r.URL.Query().Get("done") == "1" {
+		t.itemsFilter = done
+	}
+	t.filter.SetValue(r.URL.Query().Get("filter") + " ") // trailing space: exact tags
+	t.populateRenderSelection()
+	return t.renderSelection
+}
+
+// serveChange is the body of a request which changes an item.
+type serveChange struct {
+	Text   string `json:"text"`
+	Status string `json:"status"`
+}
+
+// change creates an item, if id is empty, or else sets the status of
+// the item of that id (or unique id prefix), and responds with the item.
+// Items checked off recur, and run the onchange hook, as with `tuido
+// done`.
+func (s *server) change(w http.ResponseWriter, r *http.Request, id string) {
+	body := serveChange{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "expected a JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var item *tuido.Item
+	if id == "" {
+		text := strings.TrimSpace(body.Text)
+		if text == "" {
+			http.Error(w, "expected the item's text", http.StatusBadRequest)
+			return
+		}
+		created, err := createItem(runConfig.writeto, text, runConfig)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		item = created
+	} else {
+		status, err := tuido.ParseStatus(body.Status)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		found, err := findItem(s.load(), id)
+		if err != nil {
+			http.Error(w, id+": "+err.Error(), http.StatusNotFound)
+			return
+		}
+		wasChecked := found.Satus() == tuido.Checked
+		if err := found.SetStatus(status); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if !wasChecked && found.Satus() == tuido.Checked {
+			if _, err := found.Recur(); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		if runConfig.onChange != "" {
+			msg := runHook(runConfig.onChange, hookCall{found.File(), found.Line(), found.Satus()})()
+			if failed, ok := msg.(hookFailedMsg); ok {
+				fmt.Fprintln(os.Stderr, failed.err)
+			}
+		}
+		item = found
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// servePage is the HTML list view.
+var servePage = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>tuido - {{.Root}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; }
+li { list-style: none; margin: 0.3em 0; }
+.status { font-family: monospace; }
+.location { color: #888; font-size: 0.8em; }
+</style>
+</head>
+<body>
+<h1>{{.Root}}</h1>
+<form>
+<input name="filter" value="{{.Filter}}" placeholder="#tag !#tag">
+<label><input type="checkbox" name="done" value="1"{{if .Done}} checked{{end}}> done</label>
+<button>filter</button>
+</form>
+<p>{{len .Items}} items</p>
+<ul>
+{{range .Items}}<li><span class="status">{{.Satus}}</span> {{.Text}} <span class="location">{{.Location}}</span></li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+// page renders the HTML list of the items requested by r.
+func (s *server) page(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := servePage.Execute(w, struct {
+		Root   string
+		Filter string
+		Done   bool
+		Items  []*tuido.Item
+	}{runConfig.root, r.URL.Query().Get("filter"), r.URL.Query().Get("done") == "1", s.listed(r)})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestServe(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "todo.xit")
+	os.WriteFile(file, []byte("[ ] fix the parser #bug\n[ ] write <docs>\n[x] ship it #bug\n"), 0644)
+	load := func() []*tuido.Item {
+		items, _ := getItems(file)
+		return items
+	}
+	s := &server{load: load}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/items?filter=%23bug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := []map[string]interface{}{}
+	json.NewDecoder(resp.Body).Decode(&listed)
+	resp.Body.Close()
+	if len(listed) != 1 || listed[0]["text"] != "fix the parser #bug" {
+		t.Errorf("expected the pending #bug item, but found %v", listed)
+	}
+
+	resp, _ = http.Get(ts.URL + "/")
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(page), "write &lt;docs&gt;") || strings.Contains(string(page), "ship it") {
+		t.Errorf("expected the pending items, escaped, in the page, but found %s", page)
+	}
+
+	id := load()[0].ID()
+	resp, _ = http.Post(ts.URL+"/api/items/"+id, "application/json", strings.NewReader(`{"status": "checked"}`))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected a read-only server to refuse changes, but found %s", resp.Status)
+	}
+
+	s.write = true
+	resp, _ = http.Post(ts.URL+"/api/items/"+id, "application/json", strings.NewReader(`{"status": "checked"}`))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the status change, but found %s", resp.Status)
+	}
+	if items := load(); items[0].Satus() != tuido.Checked {
+		t.Errorf("expected the item checked, but found %q", items[0].Raw())
+	}
+
+	// a page of another site cannot forge changes
+	for _, contentType := range []string{"application/x-www-form-urlencoded", "application/json"} {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/items/"+id, strings.NewReader(`{"status": "open"}`))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Origin", "https://example.com")
+		resp, _ = http.DefaultClient.Do(req)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("expected a cross-origin %s change refused, but found %s", contentType, resp.Status)
+		}
+	}
+	resp, _ = http.Post(ts.URL+"/api/items/"+id, "text/plain", strings.NewReader(`{"status": "open"}`))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected a change other than of JSON refused, but found %s", resp.Status)
+	}
+	if items := load(); items[0].Satus() != tuido.Checked {
+		t.Errorf("expected the item still checked, but found %q", items[0].Raw())
+	}
+
+	resp, _ = http.Post(ts.URL+"/api/items/"+id, "application/json", strings.NewReader(`{"status": "finished"}`))
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected an unknown status refused, but found %s", resp.Status)
+	}
+}
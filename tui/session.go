@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// session is where the app was left: the filter, sort order, and tab,
+// and the ID of the selected item. One is kept for each directory, and
+// restored on the next run there, unless -no-session is given.
+type session struct {
+	Filter   string `json:"filter,omitempty"`
+	Sort     string `json:"sort,omitempty"`
+	Tab      string `json:"tab,omitempty"`
+	Selected string `json:"selected,omitempty"`
+}
+
+// sessionsPath returns the location of the saved sessions, or "" if there
+// is no user cache directory.
+func sessionsPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tuido", "sessions")
+}
+
+// sessionKey identifies the session of a run: its working directory, and
+// the paths it was given, if any.
+func sessionKey(args []string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return strings.Join(append([]string{wd}, args...), " ")
+}
+
+// loadSessions reads the saved sessions, by key, from path. A missing or
+// unreadable file is no sessions.
+func loadSessions(path string) map[string]session {
+	sessions := map[string]session{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &sessions)
+	}
+	return sessions
+}
+
+// saveSession records s as the session of key, among those saved at path.
+func saveSession(path, key string, s session) error {
+	if path == "" || key == "" {
+		return nil
+	}
+	sessions := loadSessions(path)
+	sessions[key] = s
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// session returns where the app is now.
+func (t tui) session() session {
+	s := session{
+		Filter: t.filter.Value(),
+		Sort:   t.sortMode.String(),
+		Tab:    string(t.itemsFilter),
+	}
+	if current := t.currentSelection(); current != nil {
+		s.Selected = current.ID()
+	}
+	return s
+}
+
+// resume restores the filter, tab, and selection of s, and its sort order
+// unless one was given by -sort. The selection is kept if the item is no
+// longer listed.
+func (t *tui) resume(s session) {
+	for _, tab := range tabs {
+		if string(tab) == s.Tab {
+			t.itemsFilter = tab
+		}
+	}
+	if m, ok := parseSortMode(s.Sort); ok && *sortFlag == "" {
+		t.sortMode = m
+	}
+	t.filter.SetValue(s.Filter)
+	t.populateRenderSelection()
+	for i, item := range t.renderSelection {
+		if s.Selected != "" && item.ID() == s.Selected {
+			t.setSelection(i)
+			return
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSession(t *testing.T) {
+	items := newItems("[ ] one #work", "[ ] two #work", "[x] three #work", "[ ] four")
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.filter.SetValue("#work")
+	m.sortMode = sortText
+	m.populateRenderSelection()
+	m.setSelection(1)
+	s := m.session()
+
+	path := filepath.Join(t.TempDir(), "sessions")
+	if err := saveSession(path, "/notes", s); err != nil {
+		t.Fatal(err)
+	}
+	saved, ok := loadSessions(path)["/notes"]
+	if !ok || saved != s {
+		t.Fatalf("expected the session saved, but found %+v", saved)
+	}
+
+	m = newTUI(items, runConfig)
+	m.resume(saved)
+	if m.filter.Value() != "#work" || m.sortMode != sortText || m.itemsFilter != todo {
+		t.Errorf("expected the filter and sort order restored, but found %q and %s", m.filter.Value(), m.sortMode)
+	}
+	if m.currentSelection() != items[1] {
+		t.Errorf("expected the selection restored, but found %q", m.currentSelection().Text())
+	}
+}
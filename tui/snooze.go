@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"github.com/nilock/tuido/tuido"
+)
+
+// setSnoozingMode prompts for the date to snooze the selected item until.
+func (t *tui) setSnoozingMode() {
+	if t.currentSelection() == nil {
+		return
+	}
+	t.touch()
+	t.mode = snoozing
+	t.snoozeEditor.SetValue("")
+	t.snoozeEditor.Focus()
+}
+
+// applySnooze snoozes the selected item until the date in the
+// snoozeEditor prompt, or wakes it if the prompt is empty.
+func (t *tui) applySnooze() error {
+	current := t.currentSelection()
+	if current == nil {
+		return nil
+	}
+	date := t.snoozeEditor.Value()
+	err := t.track([]*tuido.Item{current}, func() error {
+		return current.SnoozeUntil(date)
+	})
+	t.stats.record(current, err)
+	t.populateRenderSelection()
+	if err == nil && date == "" {
+		t.notice = "woke " + current.Location()
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSnoozedTab(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] a open\n[ ] b later snooze:2999-01-01\n[x] c done\n"), 0644)
+	items, _ := getItems(file)
+
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			model, _ := m.Update(k)
+			m = model.(tui)
+		}
+	}
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+
+	press(tab, tab)
+	if m.itemsFilter != snoozed || len(m.renderSelection) != 1 || m.renderSelection[0] != items[1] {
+		t.Fatalf("expected the snoozed item listed in the snoozed tab, but found %v", m.renderSelection)
+	}
+
+	// an empty date wakes the item, which leaves the tab
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Z'}}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err != nil {
+		t.Fatal(m.err)
+	}
+	if items[1].Raw() != "[ ] b later" || len(m.renderSelection) != 0 {
+		t.Errorf("expected the item woken, but found %q", items[1].Raw())
+	}
+
+	press(tab)
+	if m.itemsFilter != todo || len(m.renderSelection) != 2 {
+		t.Errorf("expected [tab] back to the todo tab, with the woken item, but found %v", m.renderSelection)
+	}
+
+	// a date snoozes the selected item out of the todo tab
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Z'}})
+	for _, r := range "2w" {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.renderSelection) != 1 {
+		t.Errorf("expected the snoozed item hidden, but found %v", m.renderSelection)
+	}
+}
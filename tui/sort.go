@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// sortMode is the ordering of the listed items.
+type sortMode int
+
+const (
+	// sortDefault orders by importance, then due date, then text
+	sortDefault sortMode = iota
+	// sortDue orders by due date alone, with undated items last
+	sortDue
+	// sortPriority orders by importance alone, keeping file order
+	// among items of equal importance
+	sortPriority
+	// sortFile groups items by file, in line order
+	sortFile
+	// sortText orders alphabetically by item text
+	sortText
+	// sortStatus orders by status, work in progress first
+	sortStatus
+	// sortModified orders by the last modification of the items' files,
+	// most recent first, and then by line
+	sortModified
+	// sortTag orders by the first plain tag of each item, eg #work,
+	// with untagged items last
+	sortTag
+	// sortAge orders by item age, oldest first, with items of unknown age
+	// last
+	sortAge
+)
+
+func (m sortMode) String() string {
+	switch m {
+	case sortDue:
+		return "due"
+	case sortPriority:
+		return "priority"
+	case sortFile:
+		return "file"
+	case sortText:
+		return "text"
+	case sortStatus:
+		return "status"
+	case sortModified:
+		return "modified"
+	case sortTag:
+		return "tag"
+	case sortAge:
+		return "age"
+	}
+	return "importance"
+}
+
+func (m sortMode) next() sortMode {
+	return (m + 1) % (sortAge + 1)
+}
+
+// sortModeNames lists the sort modes, for messages.
+const sortModeNames = "importance, due, priority, file, text, status, modified, tag, or age"
+
+// parseSortMode reads a sort mode from its name, eg "due".
+func parseSortMode(name string) (sortMode, bool) {
+	for m := sortDefault; m <= sortAge; m++ {
+		if name == m.String() {
+			return m, true
+		}
+	}
+	return sortDefault, name == "default"
+}
+
+func (m sortMode) sort(items []*tuido.Item) {
+	switch m {
+	case sortDue:
+		sortItems(items) // due ties are broken by the default order
+		sort.SliceStable(items, func(i, j int) bool {
+			return dueBefore(items[i], items[j])
+		})
+	case sortPriority:
+		sortByLocation(items)
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Priority() > items[j].Priority()
+		})
+	case sortFile:
+		sortByLocation(items)
+	case sortText:
+		sort.SliceStable(items, func(i, j int) bool {
+			return strings.ToLower(items[i].Text()) < strings.ToLower(items[j].Text())
+		})
+	case sortStatus:
+		sort.SliceStable(items, func(i, j int) bool {
+			return rankOf(items[i].Satus()) < rankOf(items[j].Satus())
+		})
+	case sortModified:
+		modified := modTimes(items)
+		sortByLocation(items)
+		sort.SliceStable(items, func(i, j int) bool {
+			return modified[items[i].File()].After(modified[items[j].File()])
+		})
+	case sortTag:
+		sortItems(items) // tag ties are broken by the default order
+		sort.SliceStable(items, func(i, j int) bool {
+			x, y := firstTag(items[i]), firstTag(items[j])
+			if x == "" || y == "" {
+				return x != "" && y == ""
+			}
+			return x < y
+		})
+	case sortAge:
+		sortItems(items) // age ties are broken by the default order
+		sort.SliceStable(items, func(i, j int) bool {
+			x, xok := items[i].Age()
+			y, yok := items[j].Age()
+			if !xok || !yok {
+				return xok && !yok
+			}
+			return x > y
+		})
+	default:
+		sortItems(items)
+	}
+}
+
+// statusRank is the order of statuses under sortStatus.
+var statusRank = map[tuido.Status]int{
+	tuido.Ongoing:  0,
+	tuido.Review:   1,
+	tuido.Open:     2,
+	tuido.Checked:  3,
+	tuido.Obsolete: 4,
+}
+
+// rankOf returns the rank of s under sortStatus. Custom statuses rank
+// with open items, if pending, or else with obsolete ones.
+func rankOf(s tuido.Status) int {
+	if rank, ok := statusRank[s]; ok {
+		return rank
+	}
+	if s.Pending() {
+		return statusRank[tuido.Open]
+	}
+	return statusRank[tuido.Obsolete]
+}
+
+// sortByLocation orders items by file, then by line.
+func sortByLocation(items []*tuido.Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].File() != items[j].File() {
+			return items[i].File() < items[j].File()
+		}
+		return items[i].Line() < items[j].Line()
+	})
+}
+
+// dueBefore reports whether a is due before b. Undated items are due
+// after dated ones.
+func dueBefore(a, b *tuido.Item) bool {
+	x, y := a.Due(), b.Due()
+	if x == nil || y == nil {
+		return x != nil && y == nil
+	}
+	return x.Before(*y)
+}
+
+// modTimes returns the modification time of each of the items' files.
+// Files which can't be read, eg remote ones, have the zero time, and so
+// sort last.
+func modTimes(items []*tuido.Item) map[string]time.Time {
+	times := map[string]time.Time{}
+	for _, item := range items {
+		if _, ok := times[item.File()]; ok {
+			continue
+		}
+		if info, err := os.Stat(item.File()); err == nil {
+			times[item.File()] = info.ModTime()
+		} else {
+			times[item.File()] = time.Time{}
+		}
+	}
+	return times
+}
+
+// firstTag returns the name of the item's first tag without a value, in
+// lower case, or "". Tags with values, eg #due=2022-06-01, are skipped.
+func firstTag(item *tuido.Item) string {
+	for _, tag := range item.Tags() {
+		if tag.Value() == "" {
+			return strings.ToLower(tag.Name())
+		}
+	}
+	return ""
+}
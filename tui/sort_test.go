@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestSortModes(t *testing.T) {
+	items := newItems(
+		"[ ] plain first",
+		"[ ] ! a bit important #due=2022-06-03",
+		"[ ] plain second -> 2022-06-01",
+		"[ ] !! more important",
+		"[ ] ! also a bit important",
+		"[@] Ongoing",
+		"[x] done",
+	)
+
+	tests := map[sortMode][]int{
+		sortDue:      {2, 1, 3, 4, 5, 6, 0},
+		sortPriority: {3, 1, 4, 0, 2, 5, 6},
+		sortFile:     {0, 1, 2, 3, 4, 5, 6},
+		sortText:     {1, 4, 3, 6, 5, 0, 2},
+		sortStatus:   {5, 0, 1, 2, 3, 4, 6},
+	}
+
+	for mode, expected := range tests {
+		sorted := append(items[:0:0], items...)
+		mode.sort(sorted)
+
+		for i, item := range sorted {
+			if item != items[expected[i]] {
+				found := []string{}
+				for _, item := range sorted {
+					found = append(found, item.Text())
+				}
+				t.Errorf("sort %s: unexpected order %q", mode, strings.Join(found, " | "))
+				break
+			}
+		}
+	}
+}
+
+func TestSortByTagAndModified(t *testing.T) {
+	items := newItems("[ ] untagged", "[ ] #work a #due=2022-06-01", "[ ] #due=2022-06-02 #Home b", "[ ] #work c")
+	sorted := append(items[:0:0], items...)
+	sortTag.sort(sorted)
+	if sorted[0] != items[2] || sorted[3] != items[0] {
+		t.Errorf("expected #home first and untagged items last, but found %v", sorted)
+	}
+
+	dir := t.TempDir()
+	older, newer := filepath.Join(dir, "older.xit"), filepath.Join(dir, "newer.xit")
+	os.WriteFile(older, []byte("[ ] old\n"), 0644)
+	os.WriteFile(newer, []byte("[ ] new\n"), 0644)
+	os.Chtimes(older, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour))
+	a, b := tuido.New(older, 1, "[ ] old"), tuido.New(newer, 1, "[ ] new")
+	sorted = []*tuido.Item{&a, &b}
+	sortModified.sort(sorted)
+	if sorted[0] != &b {
+		t.Errorf("expected the recently modified file first")
+	}
+
+	items = newItems("[ ] unknown", "[ ] newer #created=2022-06-02", "[ ] older #created=2022-01-01")
+	sorted = append(items[:0:0], items...)
+	sortAge.sort(sorted)
+	if sorted[0] != items[2] || sorted[2] != items[0] {
+		t.Errorf("expected the oldest first and unknown ages last, but found %v", sorted)
+	}
+
+	for m := sortDefault; m <= sortAge; m++ {
+		if parsed, ok := parseSortMode(m.String()); !ok || parsed != m {
+			t.Errorf("expected sort %s to parse", m)
+		}
+	}
+	if _, ok := parseSortMode("random"); ok {
+		t.Errorf("expected an unknown sort rejected")
+	}
+}
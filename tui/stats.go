@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// sessionStats tallies the changes made during a run of the app,
+// for the summary printed on quit.
+type sessionStats struct {
+	checked int
+	changed int
+	failed  []string
+}
+
+// record tallies a change to item, which failed if err is non-nil.
+func (s *sessionStats) record(item *tuido.Item, err error) {
+	if item == nil {
+		return
+	}
+	if err != nil {
+		s.failed = append(s.failed, fmt.Sprintf("%s: %s", item.Location(), err))
+		return
+	}
+	s.changed++
+}
+
+func (s sessionStats) String() string {
+	ret := fmt.Sprintf("tuido: %d checked, %d changed this session", s.checked, s.changed)
+
+	if len(s.failed) != 0 {
+		ret += fmt.Sprintf(". %d failed to save:\n  %s", len(s.failed), strings.Join(s.failed, "\n  "))
+	}
+	return ret
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// statusCounts tallies items by status.
+type statusCounts struct {
+	total   int
+	open    int
+	ongoing int
+	review  int
+	done    int
+}
+
+// viewCounts tallies the items matching the filter, of both tabs.
+// Snoozed items are not counted as open.
+func (t tui) viewCounts() statusCounts {
+	q := parseFilter(t.filter.Value(), t.filterAll)
+	c := statusCounts{}
+	for _, item := range t.items {
+		if !q.empty() && !q.matches(item) {
+			continue
+		}
+		switch item.Satus() {
+		case tuido.Open:
+			if !item.Active() {
+				continue
+			}
+			c.open++
+		case tuido.Ongoing:
+			c.ongoing++
+		case tuido.Review:
+			c.review++
+		case tuido.Checked, tuido.Obsolete:
+			c.done++
+		default:
+			if item.Satus().Finished() {
+				c.done++
+			}
+		}
+		c.total++
+	}
+	return c
+}
+
+// statusBar summarizes the view above the footer: the counts of items
+// matching the filter, the sort order, the filter, and whether every
+// change was saved.
+func (t tui) statusBar() string {
+	faint := lg.NewStyle().Faint(true)
+
+	c := t.viewCounts()
+	left := fmt.Sprintf(" %d items: %d open, %d ongoing, %d review, %d done", c.total, c.open, c.ongoing, c.review, c.done)
+	if t.w < narrowWidth {
+		left = fmt.Sprintf(" %d items, %d done", c.total, c.done)
+	}
+	left += "  sort: " + t.sortMode.String()
+	if current := t.currentSelection(); current != nil {
+		if d, ok := t.timers.elapsed(current, time.Now()); ok {
+			left += "  timing " + formatClock(d)
+		}
+	}
+	if filter := strings.TrimSpace(t.filter.Value()); filter != "" {
+		left += "  filter: " + filter
+	}
+	left = faint.Render(left)
+
+	right := faint.Render("all changes saved ")
+	if tuido.ReadOnly {
+		right = faint.Render("read-only ")
+	}
+	if t.w < narrowWidth {
+		right = ""
+	}
+	if n := len(t.stats.failed); n > 0 {
+		right = errorStyle.Render(fmt.Sprintf("%d failed to save ", n))
+	}
+
+	gap := strings.Repeat(" ", max(0, t.w-lg.Width(left)-lg.Width(right)))
+	if t.w < narrowWidth {
+		return lg.NewStyle().MaxWidth(t.w).Render(left + gap + right)
+	}
+	return left + gap + right
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStatusBar(t *testing.T) {
+	items := newItems("[ ] one #work", "[@] two #work", "[x] three #work", "[ ] four #home", "[ ] snoozed #work #active=2999-01-01")
+	m := newTUI(items, runConfig)
+	m.w = 120
+	m.filter.SetValue("#work ")
+	m.populateRenderSelection()
+
+	if c := m.viewCounts(); c != (statusCounts{total: 3, open: 1, ongoing: 1, done: 1}) {
+		t.Errorf("expected the #work items counted from both tabs, but found %+v", c)
+	}
+
+	bar := m.statusBar()
+	if !strings.Contains(bar, "sort: importance") || !strings.Contains(bar, "filter: #work") || !strings.Contains(bar, "all changes saved") {
+		t.Errorf("expected the sort, filter, and save state in %q", bar)
+	}
+
+	m.stats.record(items[0], fmt.Errorf("disk full"))
+	if bar := m.statusBar(); !strings.Contains(bar, "1 failed to save") {
+		t.Errorf("expected the failed save reported in %q", bar)
+	}
+}
+
+func TestRelaxWithoutPriority(t *testing.T) {
+	m := newTUI(newItems("[ ] no priority"), runConfig)
+	m.populateRenderSelection()
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	m = model.(tui)
+	if len(m.stats.failed) != 0 || m.notice != "already priority 0" {
+		t.Errorf("expected a notice rather than a failed save, but found %v and %q", m.stats.failed, m.notice)
+	}
+}
@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/nilock/tuido/tuido"
+)
+
+// statusDef is a custom status of the `statuses` config, with the color
+// of its status box, and the key which sets it, if any.
+type statusDef struct {
+	tuido.CustomStatus
+	color string
+	key   string
+}
+
+// reservedStatusNames are the names a custom status cannot take, as
+// those of the built-in statuses, or of the other `is:` predicates.
+var reservedStatusNames = map[string]bool{
+	"open": true, "ongoing": true, "review": true, "checked": true, "obsolete": true,
+	"unknown": true, "pending": true, "done": true, "overdue": true, "snoozed": true,
+}
+
+// builtinMarkers are the marker characters of the built-in statuses.
+const builtinMarkers = " @rxX~"
+
+// parseStatuses reads a `name:marker:color:view:key` list of custom
+// statuses, eg `blocked:?:#e0a000:todo:b`, where view is the tab the
+// status's items are listed in, todo (the default) or done. The color,
+// view, and key may be left empty, or off. Malformed entries are skipped.
+func parseStatuses(s string) []statusDef {
+	defs := []statusDef{}
+	for _, entry := range strings.Split(s, ",") {
+		split := strings.Split(entry, ":")
+		for len(split) < 5 {
+			split = append(split, "")
+		}
+		name, marker, color, view, bound := split[0], split[1], split[2], split[3], strings.Join(split[4:], ":")
+		switch {
+		case name == "" || strings.ContainsAny(name, " #") || reservedStatusNames[name] || isAction(name):
+			fmt.Fprintf(os.Stderr, "ignoring status %s: %q is not an available status name\n", entry, name)
+			continue
+		case len(marker) != 1 || marker == "]" || strings.Contains(builtinMarkers, marker):
+			fmt.Fprintf(os.Stderr, "ignoring status %s: expected a single marker character, other than those of the built-in statuses\n", entry)
+			continue
+		case view != "" && view != string(todo) && view != string(done):
+			fmt.Fprintf(os.Stderr, "ignoring status %s: expected the view todo or done, not %s\n", entry, view)
+			continue
+		}
+		if _, err := colorful.Hex(color); color != "" && err != nil {
+			fmt.Fprintf(os.Stderr, "ignoring status %s: not a hex color\n", entry)
+			continue
+		}
+		defs = append(defs, statusDef{
+			CustomStatus: tuido.CustomStatus{
+				Name:   tuido.Status(name),
+				Marker: "[" + marker + "]",
+				Done:   view == string(done),
+			},
+			color: color,
+			key:   bound,
+		})
+	}
+	return defs
+}
+
+// customStatuses returns the custom statuses of defs, as set for the
+// tuido package by configure.
+func customStatuses(defs []statusDef) []tuido.CustomStatus {
+	statuses := []tuido.CustomStatus{}
+	for _, def := range defs {
+		statuses = append(statuses, def.CustomStatus)
+	}
+	return statuses
+}
+
+// status returns the custom status definition of s, if s is one.
+func (c config) status(s tuido.Status) (statusDef, bool) {
+	for _, def := range c.statuses {
+		if def.Name == s {
+			return def, true
+		}
+	}
+	return statusDef{}, false
+}
+
+// statusCommand returns the custom status set by the navigation command,
+// if it is one. Each is named for its status.
+func (c config) statusCommand(command string) (tuido.Status, bool) {
+	def, ok := c.status(tuido.Status(command))
+	return def.Name, ok
+}
+
+// statusKey returns the custom status bound to key k, in batch and
+// focus modes, if any.
+func (c config) statusKey(k string) (tuido.Status, bool) {
+	for _, def := range c.statuses {
+		if def.key != "" && def.key == k {
+			return def.Name, true
+		}
+	}
+	return "", false
+}
+
+// bindStatuses adds the keys of the custom statuses of cfg to keys.
+// Their bindings take precedence over the defaults, as rebound actions'
+// do.
+func (k *keyMap) bindStatuses(cfg config) {
+	for _, def := range cfg.statuses {
+		if def.key == "" {
+			continue
+		}
+		name := string(def.Name)
+		binding := key.NewBinding(key.WithKeys(def.key), key.WithHelp(def.key, "mark "+name))
+		k.commands[name] = &binding
+		k.rebound[name] = true
+		k.statuses = append(k.statuses, name)
+	}
+}
+
+// customBox renders the status box of an item of a custom status in its
+// color, if it has one.
+func (t tui) customBox(item tuido.Item, box string) (string, bool) {
+	def, ok := t.config.status(item.Satus())
+	if !ok || def.color == "" || activeTheme.mono {
+		return "", false
+	}
+	return lg.NewStyle().Bold(true).Foreground(lg.Color(def.color)).Render(box), true
+}
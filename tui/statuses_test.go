@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestParseStatuses(t *testing.T) {
+	defs := parseStatuses("blocked:?:#e0a000:todo:W,delegated:>,dropped:/::done,done:!,twice:x,odd:%:red")
+	if len(defs) != 3 {
+		t.Fatalf("expected the three well-formed statuses, but found %v", defs)
+	}
+	if d := defs[0]; d.Name != "blocked" || d.Marker != "[?]" || d.color != "#e0a000" || d.Done || d.key != "W" {
+		t.Errorf("expected blocked read in full, but found %+v", d)
+	}
+	if d := defs[1]; d.Name != "delegated" || d.Marker != "[>]" || d.key != "" {
+		t.Errorf("expected delegated without a key, but found %+v", d)
+	}
+	if !defs[2].Done {
+		t.Errorf("expected dropped in the done view")
+	}
+}
+
+func TestCustomStatuses(t *testing.T) {
+	cfg := runConfig
+	cfg.statuses = parseStatuses("blocked:?:#e0a000:todo:W,dropped:/::done:ctrl+g")
+	tuido.CustomStatuses = customStatuses(cfg.statuses)
+	defer func() { tuido.CustomStatuses = []tuido.CustomStatus{} }()
+
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[?] waiting on legal\n[ ] draft the contract\n[/] old plan\n"), 0644)
+	items, _ := getItems(file)
+	if items[0].Satus() != "blocked" || items[2].Satus() != "dropped" {
+		t.Fatalf("expected the custom markers read, but found %s and %s", items[0].Satus(), items[2].Satus())
+	}
+
+	m := newTUI(items, cfg)
+	m.populateRenderSelection()
+	if len(m.renderSelection) != 2 {
+		t.Errorf("expected the blocked item in the todo tab, and the dropped one not, but found %d", len(m.renderSelection))
+	}
+
+	m.selectItem(items[1])
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	m = model.(tui)
+	expectContent(t, file, "[?] waiting on legal\n[?] draft the contract\n[/] old plan\n")
+
+	m.filter.SetValue("is:blocked")
+	m.populateRenderSelection()
+	if len(m.renderSelection) != 2 {
+		t.Errorf("expected is:blocked to match both blocked items, but found %d", len(m.renderSelection))
+	}
+	if !strings.Contains(m.keys.controls(), "W: mark blocked") {
+		t.Errorf("expected the custom status key in the help screen")
+	}
+}
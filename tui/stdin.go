@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+// stdinFile is the file name of items read from plain lines on stdin.
+const stdinFile = "<stdin>"
+
+// grepLine matches a `grep -n` match, eg `notes/todo.md:12:- [ ] fix it`.
+var grepLine = regexp.MustCompile(`^([^:]+):([0-9]+):(.*)$`)
+
+// readStdinItems parses items from r, a pipe into `tuido -`. Lines in
+// the `file:line:text` form of `grep -n` are read as the items of that
+// file and line, and other lines as items of stdin itself. Every item is
+// read-only.
+func readStdinItems(r io.Reader) ([]*tuido.Item, error) {
+	items := []*tuido.Item{}
+	plain := []*tuido.Item{}
+
+	scanner := tuido.NewLineScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+
+		var item *tuido.Item
+		if m := grepLine.FindStringSubmatch(text); m != nil {
+			if info, err := os.Stat(m[1]); err == nil && !info.IsDir() {
+				n, _ := strconv.Atoi(m[2])
+				item = tuido.Parse(m[1], n, m[3])
+				if item == nil {
+					continue
+				}
+			}
+		}
+		if item == nil {
+			if item = tuido.Parse(stdinFile, line, text); item == nil {
+				continue
+			}
+			plain = append(plain, item)
+		}
+		item.SetReadOnly()
+		items = append(items, item)
+	}
+	tuido.Nest(plain)
+	return items, scanner.Err()
+}
+
+// runStdin reads items from stdin, for `tuido -`, rather than scanning
+// the filesystem. The items are printed if stdout is not a terminal,
+// eg `grep -rn "\[ \]" notes/ | tuido - | sort`, and otherwise shown
+// in the app, read-only. -jsonl prints every item, as JSON lines.
+func runStdin() {
+	root, err := os.Getwd()
+	if err != nil {
+		fmt.Printf("error reading the working directory: %s\n", err)
+		os.Exit(1)
+	}
+	configure(root)
+
+	items, err := readStdinItems(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error reading stdin: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonlFlag {
+		enc := json.NewEncoder(os.Stdout)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+		return
+	}
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
+		t := newTUI(items, runConfig)
+		t.populateRenderSelection()
+		for _, item := range t.renderSelection {
+			fmt.Printf("%s  %s  %s\n", item.ID(), item.String(), item.Location())
+		}
+		return
+	}
+
+	// the app reads keys from the terminal, as stdin is the pipe
+	t := newTUI(items, runConfig)
+	t.populateRenderSelection()
+	final, err := tea.NewProgram(t, tea.WithAltScreen(), tea.WithInputTTY()).StartReturningModel()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if t, ok := final.(tui); ok {
+		fmt.Println(t.stats)
+	}
+}
@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadStdinItems(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(file, []byte("# notes\n- [ ] fix it\n"), 0644)
+
+	input := file + ":2:- [ ] fix it\n" +
+		"[ ] plain\n" +
+		"  [x] plain subtask\n" +
+		"not an item\n" +
+		"missing.md:3:[ ] from a missing file\n" // a plain line, and not an item
+	items, err := readStdinItems(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, but found %d", len(items))
+	}
+	if items[0].Location() != file+":2" || items[0].Text() != "fix it" {
+		t.Errorf("expected a grep match read at its file and line, but found %s %q", items[0].Location(), items[0].Text())
+	}
+	if items[1].Location() != "<stdin>:2" || items[2].Parent() != items[1] {
+		t.Errorf("expected plain lines read as nested items of stdin, but found %s", items[1].Location())
+	}
+	for _, item := range items {
+		if !item.ReadOnly() {
+			t.Errorf("expected %s read-only", item.Location())
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// openSubtasks returns the pending subtasks of item, at every depth.
+func openSubtasks(item *tuido.Item) []*tuido.Item {
+	open := []*tuido.Item{}
+	for _, child := range item.Children() {
+		if !child.Satus().Finished() {
+			open = append(open, child)
+		}
+		open = append(open, openSubtasks(child)...)
+	}
+	return open
+}
+
+// checkSelection checks the selected item, as done does, but first asks
+// whether to check its open subtasks too, if it has any.
+func (t *tui) checkSelection() {
+	current := t.currentSelection()
+	if len(t.marked) == 0 && current != nil && current.Satus() != tuido.Checked && len(openSubtasks(current)) > 0 {
+		t.mode = checkingSubtasks
+		return
+	}
+	t.setStatus(tuido.Checked)
+}
+
+func (t tui) checkSubtasksPrompt() string {
+	current := t.currentSelection()
+	return fmt.Sprintf("check the %d open subtasks of %q too? [y] - Check all,  [n] - Only this item,  [any key] - Cancel",
+		len(openSubtasks(current)), current.Text())
+}
+
+// checkWithSubtasks checks the selected item and its open subtasks, as
+// one change, writing the file once.
+func (t *tui) checkWithSubtasks() error {
+	current := t.currentSelection()
+	if current == nil {
+		return nil
+	}
+	err := t.setItemsStatus(append([]*tuido.Item{current}, openSubtasks(current)...), tuido.Checked)
+	t.touch()
+	if t.board {
+		t.populateRenderSelection()
+		t.selectItem(current)
+	}
+	return err
+}
+
+// toggleFold hides or shows the subtasks of the selected item, or of the
+// parent of a selected subtask.
+func (t *tui) toggleFold() {
+	current := t.currentSelection()
+	if current == nil {
+		return
+	}
+	parent := current
+	if len(parent.Children()) == 0 {
+		parent = current.Parent()
+	}
+	if parent == nil {
+		return
+	}
+	if t.folded == nil {
+		t.folded = map[*tuido.Item]bool{}
+	}
+	t.folded[parent] = !t.folded[parent]
+	t.populateRenderSelection()
+	if !t.selectItem(parent) {
+		t.selectItem(current)
+	}
+}
+
+// foldedItem reports whether the subtasks of item, a copy of a listed
+// item, are hidden.
+func (t tui) foldedItem(item tuido.Item) bool {
+	children := item.Children()
+	return len(children) > 0 && t.folded[children[0].Parent()]
+}
+
+// unfoldAncestors shows item, if the subtasks of any of its parents are
+// hidden, and reports whether it was hidden.
+func (t *tui) unfoldAncestors(item *tuido.Item) bool {
+	unfolded := false
+	for p := item.Parent(); p != nil; p = p.Parent() {
+		if t.folded[p] {
+			t.folded[p] = false
+			unfolded = true
+		}
+	}
+	return unfolded
+}
@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestSubtasks(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] trip\n  [ ] towels\n  [x] hat\n  [ ] sunscreen\n    [ ] spf 50\n"), 0644)
+	items, _ := getItems(file)
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+
+	m.toggleFold()
+	if len(m.renderSelection) != 1 || m.currentSelection() != items[0] {
+		t.Fatalf("expected the subtasks folded under their parent, but found %d listed", len(m.renderSelection))
+	}
+	m.jumpTo(items[4])
+	if m.currentSelection() != items[4] || len(m.renderSelection) != 4 {
+		t.Errorf("expected a jump to a folded subtask to unfold it, but found %d listed", len(m.renderSelection))
+	}
+
+	m.selectItem(items[0])
+	m.checkSelection()
+	if m.mode != checkingSubtasks {
+		t.Fatalf("expected a prompt to check the open subtasks, but found mode %d", m.mode)
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = model.(tui)
+	for _, item := range items {
+		if item.Satus() != tuido.Checked {
+			t.Errorf("expected every subtask checked, but found %q", item.Raw())
+		}
+	}
+
+	m.applyUndo(false)
+	items, _ = getItems(file)
+	for _, item := range items[:2] {
+		if item.Satus() != tuido.Open {
+			t.Errorf("expected the check undone as one change, but found %q", item.Raw())
+		}
+	}
+
+	m = newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.checkSelection()
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = model.(tui)
+	if items[0].Satus() != tuido.Checked || items[1].Satus() != tuido.Open {
+		t.Errorf("expected only the parent checked, but found %q and %q", items[0].Raw(), items[1].Raw())
+	}
+}
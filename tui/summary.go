@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// summary counts items by status, pending items by tag and by file, and
+// completions by week.
+type summary struct {
+	total    int
+	statuses map[tuido.Status]int
+	// pending counts the open, ongoing, and in review items of each tag
+	pending map[string]int
+	// files counts the pending items of each file
+	files map[string]int
+	// weekly counts the items completed in each of the last trendWeeks
+	// weeks, oldest first, by their #completed dates
+	weekly []int
+	// spent is the time spent on the items, per their `spent:` annotations
+	spent time.Duration
+	// custom are the custom statuses counted among statuses, in order
+	custom []tuido.Status
+}
+
+// trendWeeks is the number of weeks of the completion trend.
+const trendWeeks = 8
+
+// summarize counts the items matching the current filter, in either tab.
+func (t tui) summarize() summary {
+	s := summary{
+		statuses: map[tuido.Status]int{},
+		pending:  map[string]int{},
+		files:    map[string]int{},
+		weekly:   make([]int, trendWeeks),
+	}
+	for _, def := range t.config.statuses {
+		s.custom = append(s.custom, def.Name)
+	}
+	q := parseFilter(t.filter.Value(), t.filterAll)
+	firstWeek := thisWeek.since(time.Now()).AddDate(0, 0, -7*(trendWeeks-1))
+
+	for _, item := range t.items {
+		if !q.empty() && !q.matches(item) {
+			continue
+		}
+		s.total++
+		s.statuses[item.Satus()]++
+		s.spent += item.Spent()
+
+		if item.Satus().Finished() {
+			if done := item.Completed(); done != nil {
+				if week := weeksSince(firstWeek, *done); week >= 0 && week < trendWeeks {
+					s.weekly[week]++
+				}
+			}
+			continue
+		}
+		s.files[item.File()]++
+		for _, tag := range item.Tags() {
+			s.pending[tag.Name()]++
+		}
+	}
+	return s
+}
+
+// weeksSince returns the number of weeks from the week of from to the
+// week of t, by their calendar dates, each in its own location, so that
+// neither the time of day nor a change of daylight saving time moves t
+// into another week.
+func weeksSince(from, t time.Time) int {
+	days := int(thisWeek.since(t).Sub(thisWeek.since(from)).Hours()) / 24
+	if days < 0 {
+		return -1
+	}
+	return days / 7
+}
+
+// summaryBarWidth is the width of the longest bar of a summary chart.
+const summaryBarWidth = 20
+
+// bar renders n as a bar, scaled so that of most is summaryBarWidth wide.
+// Counts above zero show at least a sliver.
+func bar(n, most int) string {
+	if n <= 0 || most <= 0 {
+		return ""
+	}
+	return strings.Repeat(glyph("█", "#"), max(n*summaryBarWidth/most, 1))
+}
+
+// byCount returns the keys of counts, most counted first, and then in
+// alphabetical order.
+func byCount(counts map[string]int) []string {
+	keys := []string{}
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// summaryView renders the summary as bar charts: of statuses and the
+// completion trend, of pending items by tag, and of pending items by
+// file, side by side in windows wide enough for them.
+func (t tui) summaryView() string {
+	s := t.summarize()
+	bold := lg.NewStyle().Bold(true)
+	faint := lg.NewStyle().Faint(true)
+
+	// leave room for the title, and the exit prompt
+	height := max(t.h-6, 3)
+
+	row := func(label string, labelWidth int, n, most int) string {
+		return label + strings.Repeat(" ", max(labelWidth-lg.Width(label), 1)) +
+			fmt.Sprintf("%5d ", n) + bar(n, most)
+	}
+	// chart renders the rows of keys, ordered by count, within height
+	chart := func(title string, keys []string, counts map[string]int, label func(string) string) []string {
+		rows := []string{bold.Render(title), ""}
+		shown := min(len(keys), max(height-len(rows)-1, 1))
+		most := 0
+		if len(keys) > 0 {
+			most = counts[keys[0]]
+		}
+		for _, k := range keys[:shown] {
+			rows = append(rows, row(label(k), 16, counts[k], most))
+		}
+		if hidden := len(keys) - shown; hidden > 0 {
+			rows = append(rows, faint.Render(fmt.Sprintf("+%d more", hidden)))
+		}
+		return rows
+	}
+
+	statuses := []string{bold.Render("by status"), "", fmt.Sprintf("%-12s %5d", "total", s.total)}
+	if s.spent > 0 {
+		statuses = append(statuses, fmt.Sprintf("%-12s %5s", "time spent", tuido.FormatSpent(s.spent)))
+	}
+	most := 0
+	for _, n := range s.statuses {
+		most = max(most, n)
+	}
+	for _, status := range append([]tuido.Status{tuido.Open, tuido.Ongoing, tuido.Review, tuido.Checked, tuido.Obsolete}, s.custom...) {
+		statuses = append(statuses, row(string(status), 12, s.statuses[status], most))
+	}
+
+	most = 0
+	for _, n := range s.weekly {
+		most = max(most, n)
+	}
+	statuses = append(statuses, "", bold.Render("completed by week"), "")
+	monday := thisWeek.since(time.Now())
+	for w, n := range s.weekly {
+		week := monday.AddDate(0, 0, -7*(trendWeeks-1-w)).Format("Jan 02")
+		statuses = append(statuses, row(week, 12, n, most))
+	}
+
+	panels := []string{strings.Join(statuses, "\n")}
+	if len(s.pending) > 0 {
+		panels = append(panels, strings.Join(chart("pending by tag", byCount(s.pending), s.pending, func(name string) string {
+			return t.tagColors[name].Render(tuido.TagSigil + name)
+		}), "\n"))
+	}
+	if len(s.files) > 0 {
+		panels = append(panels, strings.Join(chart("pending by file", byCount(s.files), s.files, func(file string) string {
+			if rel, err := filepath.Rel(t.config.root, file); err == nil && t.config.root != "" {
+				file = rel
+			}
+			if runes := []rune(file); len(runes) > 15 {
+				file = "…" + string(runes[len(runes)-14:])
+			}
+			return file
+		}), "\n"))
+	}
+
+	// panels are side by side if they fit, and otherwise stacked
+	width := 0
+	spaced := []string{}
+	for _, p := range panels {
+		width += lg.Width(p) + 4
+		spaced = append(spaced, lg.NewStyle().MarginRight(4).Render(p))
+	}
+	body := strings.Join(panels, "\n\n")
+	if width <= t.w {
+		body = lg.JoinHorizontal(lg.Top, spaced...)
+	}
+
+	title := "summary"
+	if f := t.filter.Value(); f != "" {
+		title += " of " + f
+	}
+	rows := []string{"", bold.Render(title), "", body, "", "[press any key to exit summary]"}
+	return lg.JoinHorizontal(lg.Top, "  ", strings.Join(rows, "\n"))
+}
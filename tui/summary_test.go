@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestSummarize(t *testing.T) {
+	items := newItems(
+		"[ ] fix the parser #bug",
+		"[@] fix the renderer #bug #ui",
+		"[x] fix the tests #bug",
+		"[ ] write docs",
+	)
+
+	tui := newTUI(items, runConfig)
+	s := tui.summarize()
+	if s.total != 4 || s.statuses[tuido.Open] != 2 || s.statuses[tuido.Ongoing] != 1 || s.statuses[tuido.Checked] != 1 {
+		t.Errorf("unexpected status counts %v of %d items", s.statuses, s.total)
+	}
+	if s.pending["bug"] != 2 || s.pending["ui"] != 1 {
+		t.Errorf("unexpected pending tag counts %v", s.pending)
+	}
+
+	tui.filter.SetValue("#ui")
+	if s := tui.summarize(); s.total != 1 || s.pending["bug"] != 1 {
+		t.Errorf("expected the filter to apply, but found %d items, %v", s.total, s.pending)
+	}
+}
+
+func TestSummaryTrend(t *testing.T) {
+	now := time.Now()
+	items := newItems(
+		"[x] this week #completed="+now.Format("2006-01-02"),
+		"[x] last week #completed="+now.AddDate(0, 0, -7).Format("2006-01-02"),
+		"[x] long ago #completed=2001-01-01",
+		"[ ] pending",
+	)
+	tui := newTUI(items, runConfig)
+	tui.w, tui.h = 160, 40
+	s := tui.summarize()
+	if s.weekly[trendWeeks-1] != 1 || s.weekly[trendWeeks-2] != 1 {
+		t.Errorf("expected a completion in each of the last two weeks, but found %v", s.weekly)
+	}
+	if s.files["todo.xit"] != 1 {
+		t.Errorf("expected pending items counted by file, but found %v", s.files)
+	}
+
+	view := tui.summaryView()
+	for _, heading := range []string{"by status", "completed by week", "pending by file"} {
+		if !strings.Contains(view, heading) {
+			t.Errorf("expected a %q chart in the summary", heading)
+		}
+	}
+	if bar(5, 10) != strings.Repeat("█", summaryBarWidth/2) || bar(1, 1000) != "█" || bar(0, 10) != "" {
+		t.Errorf("unexpected bar scaling")
+	}
+}
+
+func TestWeeksSince(t *testing.T) {
+	monday := time.Date(2022, 3, 21, 0, 0, 0, 0, time.UTC)
+	cest := time.FixedZone("CEST", 2*60*60)
+	for expected, done := range map[int]time.Time{
+		0:  time.Date(2022, 3, 27, 23, 59, 0, 0, time.UTC),
+		1:  time.Date(2022, 3, 28, 0, 30, 0, 0, cest), // Sunday, in UTC
+		2:  time.Date(2022, 4, 4, 0, 0, 0, 0, time.UTC),
+		-1: time.Date(2022, 3, 20, 12, 0, 0, 0, time.UTC),
+	} {
+		if week := weeksSince(monday, done); week != expected {
+			t.Errorf("expected %s in week %d, but found %d", done, expected, week)
+		}
+	}
+}
+
+func TestSummaryFileLabels(t *testing.T) {
+	items := []*tuido.Item{tuido.Parse("ノート/とても長い名前のファイルです.xit", 1, "[ ] 項目")}
+	tui := newTUI(items, runConfig)
+	tui.w, tui.h = 160, 40
+	if view := tui.summaryView(); !utf8.ValidString(view) || !strings.Contains(view, "…") {
+		t.Errorf("expected long paths cut to whole characters, but found %q", view)
+	}
+}
@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// theme is the palette of the app's styles. Colors are hex colors, or ""
+// for the terminal's own foreground.
+type theme struct {
+	review   string // the marker of items awaiting review
+	overdue  string // overdue items
+	due      string // [x]it! `-> DATE` due dates
+	priority string // priority badges
+	mark     string // the gutter mark of items marked for a bulk change
+	alert    string // stalled items, and the agenda's overdue header
+	group    string // file group headers
+	err      string // errors in the footer
+	peek     string // the pointer of the source context view
+
+	// lightness is that of generated tag colors and item ages, unless
+	// set by -lightness.
+	lightness float64
+	// mono themes have no colors at all, not even for tags. Styles keep
+	// their bold, underline, and reverse.
+	mono bool
+	// plain themes also spell out, in words, what the others draw with
+	// symbols and borders, eg statuses, and list one item per line, for
+	// screen readers and simple terminals.
+	plain bool
+}
+
+// themes are the built-in themes, by name.
+var themes = map[string]theme{
+	"dark": {
+		review: "#d7af5f", overdue: "#ff875f", due: "#5fafd7", priority: "#ff5f87", mark: "#5fafff",
+		alert: "#ff5f5f", group: "#87afd7", err: "#ff2222", peek: "#a0f0a0",
+		lightness: 0.85,
+	},
+	"light": {
+		review: "#875f00", overdue: "#af3f00", due: "#005f87", priority: "#af005f", mark: "#005fd7",
+		alert: "#d70000", group: "#005f87", err: "#d70000", peek: "#008700",
+		lightness: 0.45,
+	},
+	"mono":  {mono: true},
+	"plain": {mono: true, plain: true},
+}
+
+// themeColors are the configurable colors of a theme, by name in the
+// `themecolors` config.
+var themeColors = map[string]func(*theme) *string{
+	"review":   func(th *theme) *string { return &th.review },
+	"overdue":  func(th *theme) *string { return &th.overdue },
+	"due":      func(th *theme) *string { return &th.due },
+	"priority": func(th *theme) *string { return &th.priority },
+	"mark":     func(th *theme) *string { return &th.mark },
+	"alert":    func(th *theme) *string { return &th.alert },
+	"group":    func(th *theme) *string { return &th.group },
+	"error":    func(th *theme) *string { return &th.err },
+	"peek":     func(th *theme) *string { return &th.peek },
+}
+
+// activeTheme is the theme last applied.
+var activeTheme = themes["dark"]
+
+// parseThemeColors reads a `name:#hex,name:#hex` list of theme colors.
+func parseThemeColors(s string) map[string]string {
+	colors := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		split := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(split) != 2 || themeColors[split[0]] == nil {
+			fmt.Fprintf(os.Stderr, "ignoring theme color %s: not one of review, overdue, due, priority, mark, alert, group, error, peek\n", pair)
+			continue
+		}
+		if _, err := colorful.Hex(split[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "ignoring theme color %s: not a hex color\n", pair)
+			continue
+		}
+		colors[split[0]] = split[1]
+	}
+	return colors
+}
+
+// resolveTheme returns the configured theme, with its color overrides,
+// or the plain theme with -plain, or the mono theme if NO_COLOR is set.
+func (cfg config) resolveTheme() theme {
+	if *plainFlag {
+		return themes["plain"]
+	}
+	if os.Getenv("NO_COLOR") != "" && cfg.theme != "plain" {
+		return themes["mono"]
+	}
+	th, ok := themes[cfg.theme]
+	if !ok {
+		th = themes["dark"]
+	}
+	for name, hex := range cfg.themeColors {
+		*themeColors[name](&th) = hex
+	}
+	return th
+}
+
+// applyTheme sets the app's styles from th, and the lightness of
+// generated colors, unless -lightness was given.
+func applyTheme(th theme) {
+	activeTheme = th
+
+	color := func(s lg.Style, hex string) lg.Style {
+		if th.mono || hex == "" {
+			return s
+		}
+		return s.Foreground(lg.Color(hex))
+	}
+	reviewStyle = color(lg.NewStyle().Bold(true), th.review)
+	overdueItemStyle = color(lg.NewStyle().Underline(true), th.overdue)
+	dueStyle = color(lg.NewStyle(), th.due)
+	priorityStyle = color(lg.NewStyle().Bold(true), th.priority)
+	markStyle = color(lg.NewStyle().Bold(true), th.mark)
+	stalledStyle = color(lg.NewStyle().Bold(true), th.alert)
+	overdueStyle = color(bucketStyle.Copy(), th.alert)
+	groupStyle = color(lg.NewStyle().Bold(true), th.group)
+	errorStyle = color(lg.NewStyle().Bold(true), th.err)
+	peekStyle = color(lg.NewStyle(), th.peek)
+	applyPlainStyles(th.plain)
+
+	lightnessSet := false
+	flag.Visit(func(f *flag.Flag) {
+		lightnessSet = lightnessSet || f.Name == "lightness"
+	})
+	if !lightnessSet && th.lightness != 0 {
+		runConfig.lightness = th.lightness
+	}
+}
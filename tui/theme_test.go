@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"os"
+	"testing"
+
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+func TestThemes(t *testing.T) {
+	defer applyTheme(themes["dark"])
+	lightness := runConfig.lightness
+	defer func() { runConfig.lightness = lightness }()
+
+	cfg := runConfig
+	cfg.theme = "light"
+	cfg.themeColors = parseThemeColors("due:#0000d7,bogus:#ffffff,mark:blue")
+	if len(cfg.themeColors) != 1 {
+		t.Errorf("expected only the valid theme color, but found %v", cfg.themeColors)
+	}
+
+	applyTheme(cfg.resolveTheme())
+	if c := dueStyle.GetForeground(); c != lg.Color("#0000d7") {
+		t.Errorf("expected the overridden due color, but found %v", c)
+	}
+	if c := reviewStyle.GetForeground(); c != lg.Color(themes["light"].review) {
+		t.Errorf("expected the light review color, but found %v", c)
+	}
+	if runConfig.lightness != themes["light"].lightness {
+		t.Errorf("expected the light theme's tag lightness, but found %v", runConfig.lightness)
+	}
+
+	// NO_COLOR drops every color, tag colors included
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+	applyTheme(cfg.resolveTheme())
+	if _, ok := dueStyle.GetForeground().(lg.NoColor); !ok {
+		t.Errorf("expected no due color under NO_COLOR, but found %v", dueStyle.GetForeground())
+	}
+	if !reviewStyle.GetBold() {
+		t.Errorf("expected mono styles to keep their bold")
+	}
+	colors := populateTagColorStyles(newItems("[ ] one #work"), cfg)
+	if _, ok := colors["work"].GetForeground().(lg.NoColor); !ok {
+		t.Errorf("expected no tag color under NO_COLOR, but found %v", colors["work"].GetForeground())
+	}
+}
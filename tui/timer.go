@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// timers are the times at which ongoing items were begun, by item ID, so
+// that the time spent on each can be added to it as it is finished. They
+// are kept in the app directory, and so an item is timed while tuido is
+// closed, too.
+type timers struct {
+	path    string
+	started map[string]time.Time
+}
+
+func timersPath() string {
+	return filepath.Join(appDir, "timers.json")
+}
+
+// loadTimers reads the timers file. A missing or malformed file has no
+// timers.
+func loadTimers(path string) *timers {
+	tm := &timers{path: path, started: map[string]time.Time{}}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &tm.started)
+	}
+	return tm
+}
+
+func (tm *timers) save() error {
+	if tm.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(tm.started)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(tm.path, data, 0644)
+}
+
+// elapsed returns the time since item was begun, if it is being timed.
+func (tm *timers) elapsed(item *tuido.Item, now time.Time) (time.Duration, bool) {
+	started, ok := tm.started[item.ID()]
+	if !ok {
+		return 0, false
+	}
+	return now.Sub(started), true
+}
+
+// clock starts timing item as it is set ongoing, from status was, and
+// stops timing it as it is set to any other status, adding the time to
+// its `spent:` annotation.
+func (tm *timers) clock(item *tuido.Item, was tuido.Status) error {
+	ongoing := item.Satus() == tuido.Ongoing
+	switch {
+	case ongoing && was != tuido.Ongoing:
+		tm.started[item.ID()] = time.Now()
+	case !ongoing && was == tuido.Ongoing:
+		spent, ok := tm.elapsed(item, time.Now())
+		if !ok {
+			return nil
+		}
+		delete(tm.started, item.ID())
+		if err := item.AddSpent(spent); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	if err := tm.save(); err != nil {
+		return fmt.Errorf("error saving timers: %s", err)
+	}
+	return nil
+}
+
+// rekey follows an item text edit which changed the item's ID from
+// oldID, so that a running timer stays attached to the item.
+func (tm *timers) rekey(i *tuido.Item, oldID string) error {
+	started, ok := tm.started[oldID]
+	if !ok || i.ID() == oldID {
+		return nil
+	}
+	delete(tm.started, oldID)
+	tm.started[i.ID()] = started
+	if err := tm.save(); err != nil {
+		return fmt.Errorf("error saving timers: %s", err)
+	}
+	return nil
+}
+
+// formatClock writes a running timer, eg "1:04:09".
+func formatClock(d time.Duration) string {
+	s := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestTimers(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] write the report\n"), 0644)
+	items, _ := getItems(file)
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+
+	m.setStatus(tuido.Ongoing)
+	id := items[0].ID()
+	if _, ok := m.timers.started[id]; !ok {
+		t.Fatalf("expected a timer started for the ongoing item")
+	}
+	m.timers.started[id] = time.Now().Add(-85 * time.Minute)
+	if bar := m.statusBar(); !strings.Contains(bar, "timing 1:25:") {
+		t.Errorf("expected the live timer in the status bar, but found %q", bar)
+	}
+
+	m.setStatus(tuido.Checked)
+	data, _ := os.ReadFile(file)
+	if raw := strings.TrimSpace(string(data)); !strings.HasPrefix(raw, "[x] write the report ") || !strings.HasSuffix(raw, " spent:1h25m") {
+		t.Errorf("expected the time spent added as the item was checked, but found %q", raw)
+	}
+	if len(m.timers.started) != 0 {
+		t.Errorf("expected the timer stopped, but found %v", m.timers.started)
+	}
+}
+
+func TestTimerFollowsTextEdit(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] write the report\n"), 0644)
+	items, _ := getItems(file)
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+
+	m.setStatus(tuido.Ongoing)
+	started := time.Now().Add(-10 * time.Minute)
+	m.timers.started[items[0].ID()] = started
+
+	m.setEditMode()
+	m.itemEditor.SetValue("write the annual report")
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(tui)
+	if text := items[0].Text(); text != "write the annual report" {
+		t.Fatalf("expected the item's text edited, but found %q", text)
+	}
+
+	if got, ok := m.timers.started[items[0].ID()]; !ok || !got.Equal(started) {
+		t.Errorf("expected the timer kept under the edited item's ID, but found %v", m.timers.started)
+	}
+	if len(m.timers.started) != 1 {
+		t.Errorf("expected a single timer, but found %v", m.timers.started)
+	}
+}
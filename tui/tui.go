@@ -1,9 +1,12 @@
 package tui
 
 import (
-	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"io/fs"
+	"hash/fnv"
+	"io"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -12,6 +15,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	lg "github.com/charmbracelet/lipgloss"
@@ -20,63 +25,117 @@ import (
 )
 
 func Run() {
-	wdStr, err := os.Getwd() // [ ] only from cli flag? YES! or... follow .gitignore
-
-	if err != nil {
-		panic(err)
+	if !flag.Parsed() {
+		flag.Parse()
 	}
 
-	adoptConfigSettings(filepath.Join(wdStr, ".tuido"))
-	// [ ] read cli flags for added extensions / extension specificity
+	if *captureFlag != "" {
+		os.Exit(captureCommand(append([]string{*captureFlag}, flag.Args()...)))
+	}
 
-	files := []string{}
+	if flag.Arg(0) == "__complete" {
+		os.Exit(completeCommand(flag.Args()[1:]))
+	}
 
-	wtStat, err := os.Stat(runConfig.writeto)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if len(flag.Args()) > 0 && findCommand(flag.Arg(0)) != nil {
+		os.Exit(runCommand(flag.Arg(0), flag.Args()[1:]))
 	}
-	if wtStat.IsDir() {
-		files = append(files, getFiles(runConfig.writeto, runConfig.extensions)...)
+
+	if flag.NArg() == 1 && flag.Arg(0) == "-" {
+		runStdin()
+		return
 	}
 
-	// [ ] replace with subdir check #active=2022-05-26 #zzz=2
-	if wdStr != runConfig.writeto {
-		wdFiles := getFiles(wdStr, runConfig.extensions)
-		files = append(files, wdFiles...)
+	ws := openWorkspace(flag.Args())
+	defer ws.close()
+	files := ws.files
+
+	if *jsonlFlag {
+		printJSONL(files)
+		return
 	}
 
-	items := []*tuido.Item{}
-	for _, f := range files {
-		items = append(items, getItems(f)...)
+	if *errorsFlag {
+		ws.items()
+		skipped.print(os.Stdout)
+		return
 	}
 
-	sortItems(items)
+	// items are parsed while the app starts, behind a spinner
+	t := newTUI(nil, runConfig)
+	t.startLoading(len(files))
+	t.timers = loadTimers(timersPath())
+	key := sessionKey(flag.Args())
+	if s, ok := loadSessions(sessionsPath())[key]; ok && !*noSessionFlag {
+		t.resumeFrom = &s
+	}
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if !*noMouseFlag {
+		options = append(options, tea.WithMouseCellMotion())
+	}
+	prog := tea.NewProgram(t, options...)
+	loadInBackground(prog, ws)
 
-	prog := tea.NewProgram(newTUI(items, runConfig), tea.WithAltScreen())
+	if *watchFlag {
+		if err := watch(prog, files, runConfig.extensions); err != nil {
+			fmt.Printf("error starting -w: %s\n", err)
+			os.Exit(1)
+		}
+	}
 
-	if err := prog.Start(); err != nil {
+	final, err := prog.StartReturningModel()
+	if err != nil {
 		panic(err)
 	}
+	if t, ok := final.(tui); ok {
+		if err := writeCalendar(runConfig.ics, t.items); err != nil {
+			fmt.Printf("error writing %s: %s\n", runConfig.ics, err)
+		}
+		if err := saveSession(sessionsPath(), key, t.session()); err != nil {
+			fmt.Printf("error saving the session: %s\n", err)
+		}
+		fmt.Println(t.stats)
+	}
 }
 
 type itemType string
 
 const (
-	todo itemType = "todo"
-	done itemType = "done"
+	todo    itemType = "todo"
+	done    itemType = "done"
+	snoozed itemType = "snoozed"
 )
 
+// tabs are the views cycled by [tab], in order.
+var tabs = []itemType{todo, done, snoozed}
+
 func newTUI(items []*tuido.Item, cfg config) tui {
 	// the search bar:
+	keys := newKeyMap(cfg)
+
 	filter := textinput.New()
-	filter.Placeholder = "filter by #tag. press /"
+	filter.Placeholder = "filter by " + tuido.TagSigil + "tag or text. press " + keys.filter.Help().Key
 
 	itemEditor := textinput.New()
 	itemEditor.Prompt = ">>>"
 
+	noteEditor := textarea.New()
+	noteEditor.Prompt = ""
+	noteEditor.ShowLineNumbers = false
+	noteEditor.CharLimit = 0
+	noteEditor.Placeholder = "note. [enter] for a new line, [ctrl+s] saves, [esc] cancels"
+
+	tagEditor := textinput.New()
+
+	snoozeEditor := textinput.New()
+	snoozeEditor.Prompt = "snooze until: "
+	snoozeEditor.Placeholder = "YYYY-MM-DD, or eg 3d, 2w, 1M"
+
+	sortMode, _ := parseSortMode(cfg.sort)
+
 	return tui{
 		config:          cfg,
+		keys:            keys,
 		err:             nil,
 		items:           items,
 		renderSelection: nil,
@@ -86,7 +145,13 @@ func newTUI(items []*tuido.Item, cfg config) tui {
 		pomoEditor:      textinput.New(),
 		filter:          filter,
 		itemEditor:      itemEditor,
-		tagColors:       populateTagColorStyles(items),
+		noteEditor:      noteEditor,
+		tagEditor:       tagEditor,
+		snoozeEditor:    snoozeEditor,
+		notes:           loadNotes(notesPath()),
+		timers:          &timers{started: map[string]time.Time{}},
+		tagColors:       populateTagColorStyles(items, cfg),
+		sortMode:        sortMode,
 		h:               0,
 		w:               0,
 	}
@@ -94,30 +159,59 @@ func newTUI(items []*tuido.Item, cfg config) tui {
 
 // populateTagColorStyles returns a coloring style for
 // each #tag that exists in the list of items.
-func populateTagColorStyles(items []*tuido.Item) map[string]lg.Style {
+func populateTagColorStyles(items []*tuido.Item, cfg config) map[string]lg.Style {
 	// [ ] this should be recalculated / shifted when new tags are added
-	// [ ] audit: results in UI suggest a bug. Colors seem clustered. ##active=2022-05-26 ##zzz=2 #active=2022-05-25 #zzz=1
-	var tags []tuido.Tag
+	// [x] audit: results in UI suggest a bug. Colors seem clustered. ##active=2022-05-26 ##zzz=2 #active=2022-05-25 #zzz=1
+
+	// each tag is colored once, however many items carry it, so that
+	// -rainbow hues are spread evenly over the distinct tags
+	seen := map[string]bool{}
+	names := []string{}
 	for _, item := range items {
-		tags = append(tags, item.Tags()...)
+		for _, tag := range item.Tags() {
+			if !seen[tag.Name()] {
+				seen[tag.Name()] = true
+				names = append(names, tag.Name())
+			}
+		}
 	}
+	sort.Strings(names)
 
 	tagColors := map[string]lg.Style{}
-	interval := 360.0 / float64(len(tags))
+	interval := 360.0 / float64(len(names))
 	offset := rand.Float64() * 360
 
-	for i, tag := range tags {
-		hue := int(offset+float64(i)*interval) % 360
-		tagColors[tag.Name()] = lg.NewStyle().
+	for i, name := range names {
+		if activeTheme.mono {
+			tagColors[name] = lg.NewStyle()
+			continue
+		}
+		if hex, ok := cfg.tagColors[name]; ok {
+			tagColors[name] = lg.NewStyle().Foreground(lg.Color(hex))
+			continue
+		}
+		hue := tagHue(name)
+		if cfg.rainbow {
+			hue = int(offset+float64(i)*interval) % 360
+		}
+		tagColors[name] = lg.NewStyle().
 			Foreground(
 				lg.Color(
-					colorful.Hcl(float64(hue), .9, 0.85).Clamped().Hex(),
+					colorful.Hcl(float64(hue), cfg.chroma, cfg.lightness).Clamped().Hex(),
 				),
 			)
 	}
 	return tagColors
 }
 
+// tagHue derives a stable hue (0-359) for the named tag, so that a tag
+// keeps its color from run to run.
+func tagHue(name string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int(h.Sum32() % 360)
+}
+
 type mode int
 
 const (
@@ -128,11 +222,33 @@ const (
 	pomo
 	nag
 	peek
+	note
+	tagging
+	browse
+	legend
+	insert
+	archiving
+	batch
+	focus
+	summarizing
+	picking
+	exporting
+	deleting
+	jumping
+	snoozing
+	checkingSubtasks
+	choosingLink
 )
 
 type tui struct {
 	config config
+	keys   keyMap
 	err    error
+	// notice is a passing message for the footer, cleared on the next keypress
+	notice string
+	// conflict is a change refused as its line changed on disk, awaiting
+	// the conflict prompt
+	conflict *conflict
 
 	items       []*tuido.Item
 	itemsFilter itemType
@@ -146,6 +262,17 @@ type tui struct {
 
 	filter     textinput.Model
 	itemEditor textinput.Model
+	noteEditor textarea.Model
+
+	// tagEditor is the prompt for adding or removing a single tag
+	tagEditor textinput.Model
+	// snoozeEditor is the prompt for the date to snooze an item until
+	snoozeEditor textinput.Model
+	// tagRemoval is true when the tagEditor prompt removes a tag
+	tagRemoval bool
+
+	// notes is the sidecar store of per-item notes
+	notes *notes
 
 	// pomoEditor is the textinput.Model for the pomo clock
 	pomoEditor textinput.Model
@@ -156,10 +283,79 @@ type tui struct {
 	// pomoTimeSet is the original time set by the user
 	pomoTimeSet int
 
+	// recentFiles is the most-recently-interacted-with list of files,
+	// most recent first
+	recentFiles []string
+	// fileFilter, if set, restricts the listed items to those in the file
+	fileFilter string
+	// dirScope, if set, restricts the listed items to those under the directory
+	dirScope string
+	browser  folderBrowser
+	// projectOnly hides the items of the global directory
+	projectOnly bool
+	// stalledOnly restricts the todo view to stalled ongoing items
+	stalledOnly bool
+	// doneRange restricts the done view to items completed in the window
+	doneRange dateRange
+
 	nag  nagScreen
 	peek peekScreen
 
+	// depths are the subtask nesting depths of listed items
+	depths map[*tuido.Item]int
+	// folded are the parent items whose subtasks are hidden
+	folded map[*tuido.Item]bool
+	// blamed are the items whose commit dates have been read from git
+	blamed map[*tuido.Item]bool
+
 	tagColors map[string]lg.Style
+	legend    tagLegend
+	views     viewPicker
+	palette   palette
+	tagLayout tagLayout
+	// multiColumn flows the list into columns, per the columns config
+	multiColumn bool
+	// board lays the items of both tabs out in a column per status
+	board bool
+	// showAges toggles the column of item ages
+	showAges bool
+	// editRaw edits the item's whole source line, rather than its text
+	editRaw bool
+	// insertBeside writes new items to the current selection's file,
+	// rather than to writeto
+	insertBeside bool
+	// preview shows the lines around the selected item, from its file
+	preview  bool
+	sortMode sortMode
+	// marked items are the targets of status keys, in place of the
+	// current selection
+	marked map[*tuido.Item]bool
+	// filterAll requires listed items to carry all of the filter's tags,
+	// rather than any of them
+	filterAll bool
+	// agenda groups pending items under due date bucket headers
+	agenda bool
+	groups fileGroups
+	// relativeDates shows date tags relative to today, rather than as ISO dates
+	relativeDates bool
+
+	// loading is true while the initial scan runs in the background,
+	// with scanned of scanTotal files parsed
+	loading   bool
+	scanned   int
+	scanTotal int
+	spinner   spinner.Model
+	// pendingReloads are files changed on disk during the initial scan
+	pendingReloads []string
+	// resumeFrom is the last session here, restored once the scan completes
+	resumeFrom *session
+	// timers time the ongoing items
+	timers *timers
+
+	stats sessionStats
+	// hooks are on-change hook runs awaiting the end of the update
+	hooks []hookCall
+	undo  undoStack
 
 	// height of the window
 	h int
@@ -209,26 +405,215 @@ func (t *tui) startPomo() {
 }
 
 func (t *tui) setPeekMode() tea.Cmd {
+	t.touch()
 	t.mode = peek
 	t.peek = peekScreen{*t.currentSelection()}
 	return nil
 }
 
 func (t *tui) setEditMode() tea.Cmd {
+	if t.currentSelection() == nil {
+		return nil
+	}
+	t.touch()
 	t.mode = edit
+	t.editRaw = false
 	t.itemEditor.SetValue(t.currentSelection().Text())
 	t.itemEditor.CursorEnd()
 	t.itemEditor.Focus()
 	return nil
 }
 
-// tab cycles the view between todos and dones.
-func (t *tui) tab() {
+// toggleRawEdit switches the item editor between the item's text and its
+// whole source line, keeping the edit so far.
+func (t *tui) toggleRawEdit() {
+	current := t.currentSelection()
+	prefix := strings.TrimSuffix(current.Raw(), current.Text())
+
+	value := t.itemEditor.Value()
+	if !t.editRaw {
+		value = prefix + value
+		if !strings.HasSuffix(current.Raw(), current.Text()) {
+			// the line is translated, as todo.txt lines are: edit it as written
+			value = current.Raw()
+		}
+	} else if item := tuido.Parse(current.File(), current.Line(), value); item != nil {
+		// edits to the marker are dropped: text edits keep the item's marker
+		value = item.Text()
+	}
 
-	if t.itemsFilter == todo {
-		t.itemsFilter = done
-	} else if t.itemsFilter == done {
-		t.itemsFilter = todo
+	t.editRaw = !t.editRaw
+	t.itemEditor.SetValue(value)
+	t.itemEditor.CursorEnd()
+}
+
+func (t *tui) setNoteMode() tea.Cmd {
+	current := t.currentSelection()
+	if current == nil {
+		return nil
+	}
+	t.touch()
+	t.mode = note
+	t.noteEditor.SetWidth(max(t.w-6, 20)) // beneath the indented item
+	t.noteEditor.SetValue(t.notes.get(current))
+	t.noteEditor.Focus()
+	return nil
+}
+
+// setStatus sets the status of the current selection.
+func (t *tui) setStatus(s tuido.Status) {
+	if len(t.marked) > 0 {
+		t.err = t.setItemsStatus(t.markedItems(), s)
+		t.marked = nil
+		t.touch()
+		return
+	}
+
+	current := t.currentSelection()
+	if current == nil {
+		return
+	}
+
+	pending := unchecked([]*tuido.Item{current})
+	err := t.track([]*tuido.Item{current}, func() error {
+		was := current.Satus()
+		if err := current.SetStatus(s); err != nil {
+			return err
+		}
+		return t.timers.clock(current, was)
+	})
+	t.stats.record(current, err)
+	if err == nil {
+		t.queueHook(current)
+	}
+	if err == nil && s == tuido.Checked {
+		t.stats.checked++
+		if err := t.recur(pending); err != nil {
+			t.err = err
+		}
+	}
+	t.touch()
+
+	// on the board, the selection follows the card to its new column
+	if t.board {
+		t.populateRenderSelection()
+		t.selectItem(current)
+	}
+}
+
+// copyMarkdown copies the listed items to the clipboard as a markdown
+// task list.
+func (t *tui) copyMarkdown() tea.Cmd {
+	if len(t.listed()) == 0 {
+		return nil
+	}
+	return copyCmd(tuido.Markdown(t.listed()), fmt.Sprintf("copied %d items as markdown", len(t.listed())))
+}
+
+// copyReference copies the current selection to the clipboard as a
+// `file:line: text` reference, or as its text alone. The marked items, if
+// there are any, are copied instead as a markdown task list.
+func (t *tui) copyReference(textOnly bool) tea.Cmd {
+	if marked := t.markedItems(); len(marked) > 0 {
+		return copyCmd(tuido.Markdown(marked), fmt.Sprintf("copied %d marked items as markdown", len(marked)))
+	}
+
+	current := t.currentSelection()
+	if current == nil {
+		return nil
+	}
+
+	ref := current.Location() + ": " + current.Text()
+	if textOnly {
+		ref = current.Text()
+	}
+	return copyCmd(ref, "copied "+ref)
+}
+
+// setTaggingMode prompts for a tag to add to (or remove from) the
+// marked items, or else the current selection.
+func (t *tui) setTaggingMode(remove bool) tea.Cmd {
+	if t.currentSelection() == nil {
+		return nil
+	}
+	t.touch()
+	t.mode = tagging
+	t.tagRemoval = remove
+	if remove {
+		t.tagEditor.Prompt = "remove " + tuido.TagSigil
+	} else {
+		t.tagEditor.Prompt = "add " + tuido.TagSigil
+	}
+	if len(t.marked) > 0 {
+		t.tagEditor.Prompt = t.markCount() + t.tagEditor.Prompt
+	}
+	t.tagEditor.SetValue("")
+	t.tagEditor.Focus()
+	return nil
+}
+
+// applyTagEdit adds or removes the tag in the tagEditor prompt on the
+// marked items, which are then unmarked, or else on the current
+// selection.
+func (t *tui) applyTagEdit() error {
+	input := strings.TrimPrefix(strings.TrimSpace(t.tagEditor.Value()), tuido.TagSigil)
+	if input == "" {
+		return nil
+	}
+	tags := tuido.Tags(tuido.TagSigil + input)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	targets := []*tuido.Item{t.currentSelection()}
+	if len(t.marked) > 0 {
+		targets = t.markedItems()
+		t.marked = nil
+	}
+
+	var firstErr error
+	err := t.track(targets, func() error {
+		return tuido.Batch(func() error {
+			for _, item := range targets {
+				var err error
+				if t.tagRemoval {
+					err = item.RemoveTag(tags[0].Name())
+				} else {
+					err = item.AddTag(tags[0])
+				}
+				t.stats.record(item, err)
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+			return firstErr
+		})
+	})
+	t.refreshTagColors()
+	t.populateRenderSelection()
+	return err
+}
+
+// refreshTagColors recalculates the tag color map if any item
+// carries a tag that does not yet have a color.
+func (t *tui) refreshTagColors() {
+	for _, item := range t.items {
+		for _, tag := range item.Tags() {
+			if _, ok := t.tagColors[tag.Name()]; !ok {
+				t.tagColors = populateTagColorStyles(t.items, t.config)
+				return
+			}
+		}
+	}
+}
+
+// tab cycles the view between todos, dones, and snoozed items.
+func (t *tui) tab() {
+	for i, tab := range tabs {
+		if t.itemsFilter == tab {
+			t.itemsFilter = tabs[(i+1)%len(tabs)]
+			break
+		}
 	}
 
 	t.populateRenderSelection()
@@ -249,113 +634,248 @@ func (t *tui) currentSelection() *tuido.Item {
 func (t *tui) populateRenderSelection() {
 	t.renderSelection = []*tuido.Item{}
 
+	now := time.Now()
+	for _, i := range t.items {
+		if t.inView(i, now) {
+			t.renderSelection = append(t.renderSelection, i)
+		}
+	}
+
+	t.applyTagFilters()
+	t.applyFileFilter()
+	t.applyDirScope()
+	t.applyProjectScope()
+	t.blameListed()
+	t.sortMode.sort(t.renderSelection)
+	if t.board {
+		t.applyBoard()
+	} else if t.agendaActive() {
+		t.applyAgenda()
+	} else {
+		t.nestRenderSelection()
+	}
+	if t.groupsActive() {
+		t.applyGroups()
+	}
+	// ensure the previous selection value is still in range
+	t.setSelection(t.selection)
+}
+
+// inView reports whether the item belongs in the current todo, done, or
+// snoozed view.
+func (t *tui) inView(i *tuido.Item, now time.Time) bool {
+	if t.board {
+		pending := i.Satus().Pending()
+		return (pending && i.Active()) || (!pending && t.doneRange.contains(i, now))
+	}
 	if t.itemsFilter == todo {
-		for _, i := range t.items {
-			if (i.Satus() == tuido.Ongoing || i.Satus() == tuido.Open) &&
-				i.Active() {
-				t.renderSelection = append(t.renderSelection, i)
-			}
+		if t.stalledOnly {
+			return i.Stalled(t.config.stalled)
 		}
+		return i.Satus().Pending() && i.Active()
 	}
 
 	if t.itemsFilter == done {
-		for _, i := range t.items {
-			if i.Satus() == tuido.Checked || i.Satus() == tuido.Obsolete {
-				t.renderSelection = append(t.renderSelection, i)
+		return i.Satus().Finished() && t.doneRange.contains(i, now)
+	}
+
+	if t.itemsFilter == snoozed {
+		return i.Satus().Pending() && !i.Active()
+	}
+
+	return false
+}
+
+// nestRenderSelection reorders the (sorted) render selection so that
+// listed subtasks follow their listed parents, and records the nesting
+// depth of each item. Subtasks whose parent is not listed are listed at
+// the top level, and those of folded parents are not listed.
+func (t *tui) nestRenderSelection() {
+	listed := map[*tuido.Item]bool{}
+	for _, item := range t.renderSelection {
+		listed[item] = true
+	}
+
+	nested := []*tuido.Item{}
+	t.depths = map[*tuido.Item]int{}
+
+	var visit func(item *tuido.Item, depth int)
+	visit = func(item *tuido.Item, depth int) {
+		nested = append(nested, item)
+		t.depths[item] = depth
+		if t.folded[item] {
+			return
+		}
+		for _, child := range item.Children() {
+			if listed[child] {
+				visit(child, depth+1)
 			}
 		}
 	}
 
-	t.applyTagFilters()
-	sortItems(t.renderSelection)
-	// ensure the previous selection value is still in range
-	t.setSelection(t.selection)
+	for _, item := range t.renderSelection {
+		if item.Parent() == nil || !listed[item.Parent()] {
+			visit(item, 0)
+		}
+	}
+
+	t.renderSelection = nested
 }
 
-func (t *tui) applyTagFilters() {
-	filterTags := tuido.Tags(t.filter.Value())
-	if len(filterTags) != 0 {
+func (t *tui) applyFileFilter() {
+	if t.fileFilter == "" {
+		return
+	}
+
+	filtered := []*tuido.Item{}
+	for _, item := range t.renderSelection {
+		if item.File() == t.fileFilter {
+			filtered = append(filtered, item)
+		}
+	}
+	t.renderSelection = filtered
+}
 
-		filtered := []*tuido.Item{}
+// maxRecentFiles is the length of the recent files jump list.
+const maxRecentFiles = 8
 
-		for _, item := range t.renderSelection {
-			itemTags := item.Tags()
+// touch records the current selection's file in the recent files list.
+func (t *tui) touch() {
+	current := t.currentSelection()
+	if current == nil {
+		return
+	}
 
-			for _, iTag := range itemTags {
-				for _, fTag := range filterTags {
-					// [ ] should not use the prefix when a tag is "complete" (followed by a space) in the prompt
-					if strings.HasPrefix(iTag.Name(), fTag.Name()) {
-						filtered = append(filtered, item)
-						continue
-					}
-				}
-			}
+	recent := []string{current.File()}
+	for _, f := range t.recentFiles {
+		if f != current.File() && len(recent) < maxRecentFiles {
+			recent = append(recent, f)
 		}
-
-		t.renderSelection = filtered
 	}
+	t.recentFiles = recent
 }
 
-func (t tui) Init() tea.Cmd { return tick() }
+// cycleRecentFiles steps the file filter through the recent files list,
+// from most to least recent, and then back to showing all files.
+func (t *tui) cycleRecentFiles() {
+	if len(t.recentFiles) == 0 {
+		return
+	}
 
-func getItems(file string) []*tuido.Item {
-	items := []*tuido.Item{}
+	next := t.recentFiles[0]
+	if t.fileFilter != "" {
+		next = ""
+		for i, f := range t.recentFiles {
+			if f == t.fileFilter && i+1 < len(t.recentFiles) {
+				next = t.recentFiles[i+1]
+			}
+		}
+	}
 
-	f, err := os.Open(file)
-	defer f.Close()
+	t.fileFilter = next
+	t.populateRenderSelection()
+}
 
-	if err != nil {
-		panic(err)
+func (t *tui) applyTagFilters() {
+	q := parseFilter(t.filter.Value(), t.filterAll)
+	if q.empty() {
+		return
 	}
 
-	scanner := bufio.NewScanner(f)
-	line := 1
-	for scanner.Scan() {
-		if tuido.IsTuido(scanner.Text()) {
-			item := tuido.New(file, line, scanner.Text())
-			items = append(items, &item)
+	filtered := []*tuido.Item{}
+	for _, item := range t.renderSelection {
+		if q.matches(item) {
+			filtered = append(filtered, item)
 		}
-		line++
 	}
+	t.renderSelection = filtered
+}
 
-	return items
+func (t tui) Init() tea.Cmd {
+	if t.loading {
+		return tea.Batch(tick(), t.spinner.Tick)
+	}
+	return tick()
 }
 
-func getFiles(wd string, extensions []string) []string {
+// getItems parses the items of file. The reason a file which is opened
+// is not read in full is recorded as skipped.
+func getItems(file string) ([]*tuido.Item, error) {
+	items, err := tuido.ReadFile(file)
+	var skip *tuido.SkipError
+	if errors.As(err, &skip) {
+		skipped.skip(file, "%s", skip.Reason)
+		return items, nil
+	}
+	return items, err
+}
+
+// readItems parses the items from r, which holds the contents of file,
+// recording the reason it is not read in full, if any, as skipped.
+func readItems(file string, r io.Reader) []*tuido.Item {
+	items, err := tuido.ReadItems(file, r)
+	if err != nil {
+		skipped.skip(file, "%s", err)
+	}
+	return items
+}
 
-	files := []string{}
-	filepath.WalkDir(wd, func(path string, d fs.DirEntry, err error) error {
-		// apply .tuido configured extensions if they exist, but do not
-		// read a configured writeto. writeto is decided by the root
-		// working directory or user config
-		if d.IsDir() {
-			cfg := parseConfigIfExists(filepath.Join(path, ".tuido"))
-			if cfg != nil {
-				extensions = cfg.extensions
+// printJSONL writes items to stdout, one JSON object per line, as
+// each file is parsed.
+func printJSONL(files []string) {
+	enc := json.NewEncoder(os.Stdout)
+	for _, f := range files {
+		items, err := getItems(f)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "skipping %s: %s\n", f, err)
+			continue
+		}
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				fmt.Fprintln(os.Stderr, err)
 			}
 		}
+	}
+}
 
-		for _, suffix := range extensions {
+// getFiles lists the files to parse under wd, per the run config: see
+// scanOptions.
+func getFiles(wd string, extensions []string) []string {
+	return tuido.Files(wd, scanOptions(extensions))
+}
 
-			if strings.HasSuffix(
-				strings.ToLower(path),
-				suffix,
-			) {
-				files = append(files, path)
+// scanOptions are the options of a scan for files with the extensions,
+// from the run config. The archive is left out, and a directory's
+// `.tuido` config can set the extensions parsed below it, but not its
+// other settings: writeto, eg, is decided by the root working directory
+// or the user config.
+func scanOptions(extensions []string) tuido.ScanOptions {
+	return tuido.ScanOptions{
+		Extensions: extensions,
+		SkipDirs:   runConfig.skipDirs,
+		MaxDepth:   runConfig.maxDepth,
+		Exclude: func(path string) bool {
+			// archived items are out of sight
+			return path == runConfig.archive
+		},
+		DirExtensions: func(dir string) []string {
+			if cfg := parseConfigIfExists(filepath.Join(dir, ".tuido")); cfg != nil {
+				return cfg.extensions
 			}
-
-		}
-		return nil
-	})
-	return files
+			return nil
+		},
+		Skipped: func(path, reason string) {
+			skipped.skip(path, "%s", reason)
+		},
+	}
 }
 
 func sortItems(items []*tuido.Item) {
 	sort.SliceStable(items, func(i, j int) bool {
-		if items[i].Importance() > items[j].Importance() {
+		if items[i].Priority() > items[j].Priority() {
 			return true
 		}
-		if items[i].Importance() < items[j].Importance() {
+		if items[i].Priority() < items[j].Priority() {
 			return false
 		}
 
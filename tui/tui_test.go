@@ -0,0 +1,322 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestGetFilesSkipsIgnored(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"todo.xit",
+		".git/notes.md",
+		"node_modules/pkg/readme.md",
+		"build/todo.xit",
+		"docs/archive/old.md",
+		"docs/plan.md",
+		"docs/ideas.draft.md",
+	} {
+		path := filepath.Join(dir, f)
+		os.MkdirAll(filepath.Dir(path), 0777)
+		os.WriteFile(path, []byte("[ ] item\n"), 0644)
+	}
+	os.WriteFile(filepath.Join(dir, ".tuidoignore"), []byte("# comment\nbuild/\ndocs/archive/*\n*.draft.md\n"), 0644)
+
+	files := getFiles(dir, []string{"xit", "md"})
+
+	expected := []string{filepath.Join(dir, "docs/plan.md"), filepath.Join(dir, "todo.xit")}
+	if strings.Join(files, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected files %v, but found %v", expected, files)
+	}
+}
+
+func TestGetFilesHonorsGitignore(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"todo.xit",
+		"dist/todo.xit",
+		"src/gen/todo.xit",
+		"src/todo.xit",
+		"src/scratch.md",
+		"src/lib/scratch.md",
+		"logs/keep.md",
+		"logs/debug.md",
+		"notes.md",
+	} {
+		path := filepath.Join(dir, f)
+		os.MkdirAll(filepath.Dir(path), 0777)
+		os.WriteFile(path, []byte("[ ] item\n"), 0644)
+	}
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("dist/\n/notes.md\nlogs/*.md\n!logs/keep.md\n**/gen\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "src/.gitignore"), []byte("scratch.md\n"), 0644)
+	// .tuidoignore patterns override .gitignore ones
+	os.WriteFile(filepath.Join(dir, ".tuidoignore"), []byte("!notes.md\n"), 0644)
+
+	files := getFiles(dir, []string{"xit", "md"})
+
+	expected := []string{}
+	for _, f := range []string{"logs/keep.md", "notes.md", "src/todo.xit", "todo.xit"} {
+		expected = append(expected, filepath.Join(dir, f))
+	}
+	if strings.Join(files, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected files %v, but found %v", expected, files)
+	}
+}
+
+func TestGetFilesMaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"todo.xit", "a/todo.xit", "a/b/todo.xit", "c/notes.log"} {
+		path := filepath.Join(dir, f)
+		os.MkdirAll(filepath.Dir(path), 0777)
+		os.WriteFile(path, []byte("[ ] item\n"), 0644)
+	}
+	// a .tuido without an extensions line keeps the inherited extensions
+	os.WriteFile(filepath.Join(dir, "a/.tuido"), []byte("maxtags=3\n"), 0644)
+
+	defer func(depth int) { runConfig.maxDepth = depth }(runConfig.maxDepth)
+
+	for depth, expected := range map[int][]string{
+		0: {"a/b/todo.xit", "a/todo.xit", "todo.xit"},
+		1: {"todo.xit"},
+		2: {"a/todo.xit", "todo.xit"},
+	} {
+		runConfig.maxDepth = depth
+		want := []string{}
+		for _, f := range expected {
+			want = append(want, filepath.Join(dir, f))
+		}
+		if files := getFiles(dir, []string{"xit"}); strings.Join(files, ",") != strings.Join(want, ",") {
+			t.Errorf("maxdepth %d: expected files %v, but found %v", depth, want, files)
+		}
+	}
+}
+
+func TestParsePathArgs(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "notes.log")
+	os.WriteFile(file, []byte("[ ] item\n"), 0644)
+
+	paths, err := parsePathArgs([]string{dir, file, file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(paths.dirs, ",") != dir || strings.Join(paths.files, ",") != file {
+		t.Errorf("expected dirs [%s] and files [%s], but found %v and %v", dir, file, paths.dirs, paths.files)
+	}
+
+	if _, err := parsePathArgs([]string{filepath.Join(dir, "missing.xit")}); err == nil {
+		t.Errorf("expected an error for a missing path")
+	}
+}
+
+func TestGetFilesFollowsSymlinksOnce(t *testing.T) {
+	dir, outside := t.TempDir(), t.TempDir()
+	dir, _ = filepath.EvalSymlinks(dir)
+	outside, _ = filepath.EvalSymlinks(outside)
+
+	os.MkdirAll(filepath.Join(dir, "a"), 0777)
+	os.WriteFile(filepath.Join(dir, "todo.xit"), []byte("[ ] item\n"), 0644)
+	os.WriteFile(filepath.Join(outside, "more.xit"), []byte("[ ] item\n"), 0644)
+
+	for link, target := range map[string]string{
+		"a/loop":    dir,                              // a cycle back to the root
+		"a/self":    filepath.Join(dir, "a"),          // a cycle to its own directory
+		"again.xit": filepath.Join(dir, "todo.xit"),   // a second path to a parsed file
+		"outside":   outside,                          // a directory out of the tree
+		"broken.md": filepath.Join(dir, "missing.md"), // a dangling link
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Skip("symlinks unsupported:", err)
+		}
+	}
+
+	done := make(chan []string)
+	go func() { done <- getFiles(dir, []string{"xit", "md"}) }()
+
+	select {
+	case files := <-done:
+		expected := []string{filepath.Join(outside, "more.xit"), filepath.Join(dir, "todo.xit")}
+		sort.Strings(files)
+		sort.Strings(expected)
+		if strings.Join(files, ",") != strings.Join(expected, ",") {
+			t.Errorf("expected files %v, but found %v", expected, files)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("walk of a symlink cycle did not terminate")
+	}
+}
+
+func TestReadItemsRobustly(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("x", 200*1024) // past bufio's default 64KB line limit
+	bom := filepath.Join(dir, "bom.xit")
+	os.WriteFile(bom, []byte("\ufeff[ ] first\n"+long+"\n[ ] after a long line\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "wide.xit"), []byte("\xff\xfe[\x00 \x00]\x00"), 0644)
+
+	items, err := getItems(bom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Raw() != "[ ] first" || items[1].Line() != 3 {
+		t.Fatalf("expected both items read, past the byte order mark and the long line, but found %v", items)
+	}
+	if err := items[0].SetStatus(tuido.Checked); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(bom); !strings.HasPrefix(string(data), "\ufeff[x] first") {
+		t.Errorf("expected the byte order mark kept, but found %q", data[:20])
+	}
+
+	if items, _ := getItems(filepath.Join(dir, "wide.xit")); len(items) != 0 {
+		t.Errorf("expected a UTF-16 file skipped, but found %v", items)
+	}
+
+	// directories are not read, whatever their name
+	os.Mkdir(filepath.Join(dir, "folder.xit"), 0777)
+	files := getFiles(dir, []string{"xit"})
+	sort.Strings(files)
+	expected := []string{bom, filepath.Join(dir, "wide.xit")}
+	if strings.Join(files, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected only regular files, %v, but found %v", expected, files)
+	}
+}
+
+func TestKeyBindings(t *testing.T) {
+	cfg := runConfig
+	cfg.keys = parseKeys("down:n|ctrl+n,jump:x,up")
+	if len(cfg.keys) != 1 {
+		t.Fatalf("expected malformed and unknown bindings to be skipped, but found %v", cfg.keys)
+	}
+
+	tui := newTUI(newItems("[ ] first", "[ ] second", "[ ] third"), cfg)
+	tui.populateRenderSelection()
+
+	for _, k := range []string{"n", "ctrl+n", "j"} {
+		var msg tea.KeyMsg
+		if k == "ctrl+n" {
+			msg = tea.KeyMsg{Type: tea.KeyCtrlN}
+		} else {
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		tui.navigate(msg)
+	}
+	if tui.selection != 2 {
+		t.Errorf("expected n and ctrl+n to move down, and j to be unbound, but the selection is %d", tui.selection)
+	}
+}
+
+func TestEditorArgs(t *testing.T) {
+	tests := map[string]string{
+		"vim":            "+12 todo.xit",
+		"/usr/bin/nvim":  "+12 todo.xit",
+		"code":           "-g todo.xit:12",
+		"hx":             "todo.xit:12",
+		"unknown-editor": "todo.xit",
+	}
+	for editor, expected := range tests {
+		if args := strings.Join(editorArgs(editor, "todo.xit", 12), " "); args != expected {
+			t.Errorf("expected %s to be run with %q, but found %q", editor, expected, args)
+		}
+	}
+}
+
+func TestTagColors(t *testing.T) {
+	items := newItems("[ ] one #urgent #home", "[ ] two #urgent", "[ ] three #urgent #work")
+	cfg := runConfig
+
+	// colors are derived from names, and so are the same for any items
+	first := populateTagColorStyles(items, cfg)
+	again := populateTagColorStyles(items[2:], cfg)
+	if first["urgent"].GetForeground() != again["urgent"].GetForeground() {
+		t.Errorf("expected #urgent to keep its color")
+	}
+
+	// rainbow hues are spread over the distinct tags, not their uses
+	cfg.rainbow = true
+	colors := populateTagColorStyles(items, cfg)
+	if len(colors) != 3 {
+		t.Errorf("expected a color per distinct tag, but found %d", len(colors))
+	}
+	distinct := map[string]bool{}
+	for _, style := range colors {
+		distinct[fmt.Sprint(style.GetForeground())] = true
+	}
+	if len(distinct) != 3 {
+		t.Errorf("expected 3 distinct rainbow colors, but found %d", len(distinct))
+	}
+
+	// configured colors take precedence
+	cfg.tagColors = map[string]string{"urgent": "#ff0000"}
+	if c := populateTagColorStyles(items, cfg)["urgent"].GetForeground(); c != lg.Color("#ff0000") {
+		t.Errorf("expected the configured #urgent color, but found %v", c)
+	}
+}
+
+func TestCommandBindings(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] first\n[ ] second\n"), 0644)
+	items, _ := getItems(file)
+
+	cfg := runConfig
+	cfg.keys = parseKeys("done:D|j")
+	m := newTUI(items, cfg)
+	m.populateRenderSelection()
+
+	// the default key of a rebound action no longer answers
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = model.(tui)
+	expectContent(t, file, "[ ] first\n[ ] second\n")
+
+	// a rebound key takes precedence over its navigation and command uses
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = model.(tui)
+	if m.selection != 0 {
+		t.Errorf("expected j to mark done rather than move down, but the selection is %d", m.selection)
+	}
+	if m.currentSelection().Satus() != tuido.Checked {
+		t.Errorf("expected j to mark the item done, but found %s", m.currentSelection().String())
+	}
+
+	if !strings.Contains(m.keys.controls(), "D/j: mark done") {
+		t.Errorf("expected the help to list the rebound keys")
+	}
+}
+
+func TestMaxTagsInline(t *testing.T) {
+	for raw, expected := range map[string]string{
+		"[ ] pay #due #duedate": "pay #due +1",
+		"[ ] pay #duedate #due": "pay #duedate +1",
+	} {
+		items := newItems(raw)
+		m := newTUI(items, runConfig)
+		m.config.maxTags = 1
+		m.tagLayout = tagsInline
+
+		line := escapeSequence.ReplaceAllString(m.renderTuido(*items[0], 80), "")
+		if !strings.HasSuffix(line, expected) {
+			t.Errorf("expected %q to render as %q, but found %q", raw, expected, line)
+		}
+	}
+}
+
+func TestReboundKeyClash(t *testing.T) {
+	cfg := runConfig
+	cfg.keys = parseKeys("legend:z,files:z,markdown:z")
+	keys := newKeyMap(cfg)
+
+	// actions rebound to the same key are matched in order of name
+	for n := 0; n < 20; n++ {
+		if name := keys.command(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")}); name != "files" {
+			t.Fatalf("expected the first action by name, but found %q", name)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// editKind is the kind of change made to an item.
+type editKind int
+
+const (
+	// edited items had their line changed, eg their status or text
+	edited editKind = iota
+	// created items were added to their file
+	created
+	// deleted items were removed from their file
+	deleted
+)
+
+// change records an item's change, and its line prior to an edit, so
+// that the change can be reverted.
+type change struct {
+	item *tuido.Item
+	kind editKind
+	prev string
+}
+
+// maxUndo is the number of batches kept for undo.
+const maxUndo = 100
+
+// undoStack holds batches of item changes. Each batch is undone (and
+// redone) as a unit.
+type undoStack struct {
+	batches [][]change
+	redos   [][]change
+}
+
+// push records a new batch of changes, which discards any redos.
+func (u *undoStack) push(batch []change) {
+	if len(batch) == 0 {
+		return
+	}
+	u.batches = append(u.batches, batch)
+	if len(u.batches) > maxUndo {
+		u.batches = u.batches[len(u.batches)-maxUndo:]
+	}
+	u.redos = nil
+}
+
+// amend adds changes to the latest batch, so that they are undone with
+// it.
+func (u *undoStack) amend(changes []change) {
+	if len(u.batches) == 0 {
+		u.push(changes)
+		return
+	}
+	last := len(u.batches) - 1
+	u.batches[last] = append(u.batches[last], changes...)
+}
+
+// undo reverts the most recent batch of changes, and returns the files
+// whose lines were added or removed, which should be re-read.
+func (u *undoStack) undo() ([]string, error) {
+	if len(u.batches) == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+
+	batch := u.batches[len(u.batches)-1]
+	u.batches = u.batches[:len(u.batches)-1]
+
+	redo, err := revert(batch)
+	u.redos = append(u.redos, redo)
+	return shifted(batch), err
+}
+
+// redo re-applies the most recently undone batch of changes, as undo.
+func (u *undoStack) redo() ([]string, error) {
+	if len(u.redos) == 0 {
+		return nil, fmt.Errorf("nothing to redo")
+	}
+
+	batch := u.redos[len(u.redos)-1]
+	u.redos = u.redos[:len(u.redos)-1]
+
+	undo, err := revert(batch)
+	u.batches = append(u.batches, undo)
+	return shifted(batch), err
+}
+
+// revert reverses each change in batch, and returns the changes which
+// would restore the batch again. Reversed edits write each file once.
+func revert(batch []change) ([]change, error) {
+	reverted := []change{}
+	err := tuido.Batch(func() error {
+		for i := len(batch) - 1; i >= 0; i-- {
+			c := batch[i]
+			switch c.kind {
+			case created:
+				if err := tuido.Delete(c.item); err != nil {
+					return err
+				}
+				reverted = append(reverted, change{c.item, deleted, ""})
+			case deleted:
+				if err := tuido.Restore(c.item); err != nil {
+					return err
+				}
+				reverted = append(reverted, change{c.item, created, ""})
+			default:
+				current := c.item.Raw()
+				if err := c.item.SetRaw(c.prev); err != nil {
+					return err
+				}
+				reverted = append(reverted, change{c.item, edited, current})
+			}
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// shifted returns the files of batch in which lines were added or
+// removed.
+func shifted(batch []change) []string {
+	files := []string{}
+	seen := map[string]bool{}
+	for _, c := range batch {
+		if c.kind != edited && !seen[c.item.File()] {
+			seen[c.item.File()] = true
+			files = append(files, c.item.File())
+		}
+	}
+	return files
+}
+
+// applyUndo undoes, or redoes, the last batch of changes, and re-reads
+// the files in which lines were added or removed.
+func (t *tui) applyUndo(redo bool) {
+	op := t.undo.undo
+	if redo {
+		op = t.undo.redo
+	}
+	files, err := op()
+	t.err = err
+	for _, file := range files {
+		t.reloadFile(file)
+	}
+	t.populateRenderSelection()
+}
+
+// track runs edit, and records the changes it makes to the lines of
+// items as one undo batch.
+func (t *tui) track(items []*tuido.Item, edit func() error) error {
+	for _, item := range items {
+		if item == nil {
+			return fmt.Errorf("no item selected")
+		}
+	}
+
+	prior := make([]string, len(items))
+	for i, item := range items {
+		prior[i] = item.Raw()
+	}
+
+	err := edit()
+
+	changes := []change{}
+	for i, item := range items {
+		if item.Raw() != prior[i] {
+			changes = append(changes, change{item, edited, prior[i]})
+		}
+	}
+	t.undo.push(changes)
+	t.noteConflict(items, edit, err)
+	return err
+}
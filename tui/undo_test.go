@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestUndoTextAndCreation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] first\n[ ] second\n"), 0644)
+	items, _ := getItems(file)
+
+	m := newTUI(items, runConfig)
+	m.config.writeto = file
+	m.populateRenderSelection()
+
+	current := m.currentSelection()
+	if err := m.track([]*tuido.Item{current}, func() error { return current.SetText("first, reworded") }); err != nil {
+		t.Fatal(err)
+	}
+	m.insertNewItem("third")
+	expectContent(t, file, "[ ] first, reworded\n[ ] second\n[ ] third\n")
+
+	m.applyUndo(false)
+	expectContent(t, file, "[ ] first, reworded\n[ ] second\n")
+	if len(m.items) != 2 {
+		t.Errorf("expected the created item dropped from the list, but found %d items", len(m.items))
+	}
+
+	m.applyUndo(false)
+	expectContent(t, file, "[ ] first\n[ ] second\n")
+
+	m.applyUndo(true)
+	m.applyUndo(true)
+	if m.err != nil {
+		t.Fatal(m.err)
+	}
+	expectContent(t, file, "[ ] first, reworded\n[ ] second\n[ ] third\n")
+}
+
+func TestRedoRecurringCheck(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] water plants every:2d #due=2024-01-31\n"), 0644)
+	items, _ := getItems(file)
+
+	m := newTUI(items, runConfig)
+	m.populateRenderSelection()
+	m.setStatus(tuido.Checked)
+	checked, _ := os.ReadFile(file)
+
+	m.applyUndo(false)
+	expectContent(t, file, "[ ] water plants every:2d #due=2024-01-31\n")
+
+	// the redone check and the restored next occurrence are written together
+	m.applyUndo(true)
+	if m.err != nil {
+		t.Fatal(m.err)
+	}
+	expectContent(t, file, string(checked))
+	if len(m.items) != 2 {
+		t.Errorf("expected the next occurrence listed again, but found %d items", len(m.items))
+	}
+}
+
+func expectContent(t *testing.T, file, expected string) {
+	t.Helper()
+	if data, _ := os.ReadFile(file); string(data) != expected {
+		t.Errorf("expected file content %q, but found %q", expected, data)
+	}
+}
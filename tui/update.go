@@ -1,8 +1,10 @@
 package tui
 
 import (
-	"fmt"
+	"os"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nilock/tuido/tuido"
 )
@@ -23,6 +25,34 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return t, tick()
 	}
 
+	if t.loading {
+		return t.updateLoading(msg)
+	}
+
+	if msg, ok := msg.(hookFailedMsg); ok {
+		t.err = msg.err
+		return t, nil
+	}
+
+	if msg, ok := msg.(clipboardMsg); ok {
+		t.err = msg.err
+		if msg.err == nil {
+			t.notice = msg.notice
+		}
+		return t, nil
+	}
+
+	if msg, ok := msg.(editorFinishedMsg); ok {
+		t.err = msg.err
+		t.reloadFile(msg.file)
+		return t, nil
+	}
+
+	if msg, ok := msg.(fileChangedMsg); ok {
+		t.reloadFile(msg.file)
+		return t, nil
+	}
+
 	if t.mode == nag {
 		mode, complete := t.nag.Update(msg)
 		t.mode = mode
@@ -39,6 +69,13 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if t.mode == summarizing {
+		if _, ok := msg.(tea.KeyMsg); ok {
+			t.mode = navigation
+			return t, nil
+		}
+	}
+
 	if t.mode == peek {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
@@ -99,18 +136,238 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return t, nil
 	}
 
+	if t.mode == note {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			key := msg.String()
+			if key == "esc" {
+				t.mode = navigation // abandon changes
+				return t, nil
+			}
+			if key == "ctrl+s" {
+				t.err = t.notes.set(t.currentSelection(), strings.TrimSpace(t.noteEditor.Value()))
+				t.mode = navigation
+				return t, nil
+			}
+		}
+
+		var cmd tea.Cmd
+		t.noteEditor, cmd = t.noteEditor.Update(msg)
+		return t, cmd
+	}
+
+	if t.mode == focus {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			t.updateFocus(msg)
+		}
+		if msg, ok := msg.(tea.WindowSizeMsg); ok {
+			t.h = msg.Height
+			t.w = msg.Width
+		}
+		return t, t.flushHooks()
+	}
+
+	if t.mode == archiving {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			t.mode = navigation
+			if msg.String() == "y" {
+				t.err = t.archiveListed()
+			}
+		}
+		return t, nil
+	}
+
+	if t.mode == choosingLink {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			t.mode = navigation
+			t.chooseLink(msg.String())
+		}
+		return t, nil
+	}
+
+	if t.mode == checkingSubtasks {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			t.mode = navigation
+			switch msg.String() {
+			case "y":
+				t.err = t.checkWithSubtasks()
+			case "n":
+				t.setStatus(tuido.Checked)
+			}
+		}
+		return t, t.flushHooks()
+	}
+
+	if t.mode == deleting {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			t.mode = navigation
+			if msg.String() == "y" {
+				t.err = t.deleteSelection()
+			}
+		}
+		return t, nil
+	}
+
+	if t.mode == exporting {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if msg.String() == "esc" {
+				t.mode = navigation
+			}
+			if format, ok := exportKeys[msg.String()]; ok {
+				t.err = t.exportListed(format)
+				t.mode = navigation
+			}
+		}
+		return t, nil
+	}
+
+	if t.mode == batch {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if msg.String() == "esc" {
+				t.mode = navigation
+			}
+			s, ok := batchKeys[msg.String()]
+			if !ok {
+				s, ok = t.config.statusKey(msg.String())
+			}
+			if ok {
+				t.err = t.setBatchStatus(s)
+				t.mode = navigation
+			}
+		}
+		return t, t.flushHooks()
+	}
+
+	if t.mode == browse {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "up", "k":
+				t.browser.move(-1)
+			case "down", "j":
+				t.browser.move(1)
+			case "enter":
+				t.dirScope = t.browser.selected()
+				if t.dirScope == t.config.root {
+					t.dirScope = ""
+				}
+				t.populateRenderSelection()
+				t.mode = navigation
+			case "esc", "b":
+				t.mode = navigation
+			case "backspace":
+				t.dirScope = ""
+				t.populateRenderSelection()
+				t.mode = navigation
+			}
+		}
+		return t, nil
+	}
+
+	if t.mode == legend {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return t, t.updateLegend(msg)
+		}
+		return t, nil
+	}
+
+	if t.mode == picking {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return t, t.updateViews(msg)
+		}
+		return t, nil
+	}
+
+	if t.mode == jumping {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return t, t.updatePalette(msg)
+		}
+		return t, nil
+	}
+
+	if t.mode == snoozing {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "esc":
+				t.mode = navigation
+				return t, nil
+			case "enter":
+				t.err = t.applySnooze()
+				t.mode = navigation
+				return t, nil
+			}
+		}
+
+		var cmd tea.Cmd
+		t.snoozeEditor, cmd = t.snoozeEditor.Update(msg)
+		return t, cmd
+	}
+
+	if t.mode == tagging {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			key := msg.String()
+			if key == "esc" {
+				t.mode = navigation
+				return t, nil
+			}
+			if key == "enter" {
+				t.err = t.applyTagEdit()
+				t.mode = navigation
+				return t, nil
+			}
+		}
+
+		var cmd tea.Cmd
+		t.tagEditor, cmd = t.tagEditor.Update(msg)
+		return t, cmd
+	}
+
+	if t.mode == insert {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "esc":
+				t.mode = navigation
+				return t, nil
+			case "enter":
+				t.insertNewItem(t.itemEditor.Value())
+				t.mode = navigation
+				return t, nil
+			case "tab":
+				t.toggleInsertTarget()
+				return t, nil
+			}
+		}
+
+		var cmd tea.Cmd
+		t.itemEditor, cmd = t.itemEditor.Update(msg)
+		return t, cmd
+	}
+
 	if t.mode == edit {
 		if msg, ok := msg.(tea.KeyMsg); ok {
 			key := msg.String()
 			if key == "esc" {
 				t.mode = navigation // abandon changes
 			}
+			if key == "tab" {
+				t.toggleRawEdit()
+				return t, nil
+			}
 			if key == "enter" {
 				if txt := t.itemEditor.Value(); txt != "" {
-					err := t.currentSelection().SetText(txt)
-					if err != nil {
-						fmt.Println("error: ", err)
+					current := t.currentSelection()
+					oldText, oldID := current.Text(), current.ID()
+					err := t.track([]*tuido.Item{current}, func() error {
+						if t.editRaw {
+							return current.SetRaw(txt)
+						}
+						return current.SetText(txt)
+					})
+					t.err = err
+					t.stats.record(current, err)
+					t.notes.rekey(current, oldText)
+					if err == nil {
+						t.err = t.timers.rekey(current, oldID)
 					}
+					t.refreshTagColors() // the edit may have added tags
 					t.mode = navigation
 				}
 			}
@@ -126,6 +383,10 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		if t.filter.Focused() { // [x] replace this w/ the mode-switch as with edit
 			k := msg.String()
+			if k == "tab" && t.completeTag() {
+				t.populateRenderSelection()
+				return t, nil
+			}
 			if k == "esc" ||
 				k == "tab" ||
 				k == "down" {
@@ -138,78 +399,215 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		switch msg.String() {
-		// navigation
-		case "up":
-			t.setSelection(t.selection - 1)
-		case "k":
-			t.setSelection(t.selection - 1)
-		case "down":
-			t.setSelection(t.selection + 1)
-		case "j":
-			t.setSelection(t.selection + 1)
-		case "pgdown": // [ ] these paging functions are not "accurate" #ui #polish
-			t.setSelection(t.selection + (len(t.renderSelection) / (t.h - 6)))
-		case "pgup":
-			t.setSelection(t.selection - (len(t.renderSelection) / (t.h - 6)))
-		case "tab":
-			t.tab()
-		case "/":
+		t.notice = ""
+
+		if t.conflict != nil {
+			t.resolveConflict(msg.String())
+			return t, nil
+		}
+
+		if key.Matches(msg, t.keys.filter) {
 			t.filter.Focus()
-		case "p":
+			return t, nil
+		}
+
+		if key.Matches(msg, t.keys.quit) {
+			return t, tea.Quit
+		}
+		command := t.keys.command(msg)
+		if !t.keys.rebound[command] && t.navigate(msg) {
+			return t, nil
+		}
+
+		// a collapsed group stands in for its items, which can't be
+		// changed one by one until it is expanded
+		if t.onCollapsedGroup(t.selection) && !groupSafeCommands[command] {
+			t.notice = "group collapsed: C expands it"
+			return t, nil
+		}
+
+		// read-only items are listed, but not changed
+		if notice := t.readOnlyNotice(command); notice != "" {
+			t.notice = notice
+			return t, nil
+		}
+
+		switch command {
+		case "pomo":
 			t.setPomoMode()
-		case "?":
+		case "help":
 			t.mode = help
 		// editing current selection
-		case "x":
-			t.currentSelection().SetStatus(tuido.Checked)
-		case "-":
-			t.currentSelection().SetStatus(tuido.Obsolete)
-		case "~":
-			t.currentSelection().SetStatus(tuido.Obsolete)
-		case "s":
-			t.currentSelection().SetStatus(tuido.Obsolete)
-		case "@":
-			t.currentSelection().SetStatus(tuido.Ongoing)
-		case "a":
-			t.currentSelection().SetStatus(tuido.Ongoing)
-		case " ":
-			t.currentSelection().SetStatus(tuido.Open)
-		case "!":
+		case "done":
+			t.checkSelection()
+		case "obsolete": // all obsolete keys write the xit [~] marker
+			t.setStatus(tuido.Obsolete)
+		case "ongoing":
+			t.setStatus(tuido.Ongoing)
+		case "review":
+			t.setStatus(tuido.Review)
+		case "open":
+			t.setStatus(tuido.Open)
+		case "escalate":
 			current := t.currentSelection()
-			t.currentSelection().Escalate()
+			t.stats.record(current, t.track([]*tuido.Item{current}, current.Escalate))
+			t.touch()
 			t.populateRenderSelection()
 			for i, item := range t.renderSelection {
 				if current == item {
 					t.setSelection(i)
 				}
 			}
-		case "1":
+		case "relax":
 			current := t.currentSelection()
-			t.currentSelection().Deescalate()
+			if current != nil && current.PriorityMarker() == "" {
+				// nothing to relax, which is not a failed save
+				t.notice = "already priority 0"
+			} else {
+				t.stats.record(current, t.track([]*tuido.Item{current}, current.Deescalate))
+			}
+			t.touch()
 			t.populateRenderSelection()
 			for i, item := range t.renderSelection {
 				if current == item {
 					t.setSelection(i)
 				}
 			}
-		case "e":
+		case "edit":
 			t.setEditMode()
-		case "n":
+		case "editor":
+			return t, t.openInEditor()
+		case "note":
+			t.setNoteMode()
+		case "tag":
+			t.setTaggingMode(false)
+		case "untag":
+			t.setTaggingMode(true)
+		case "new":
 			t.tryCreateNewItem()
-		case "z":
-			t.currentSelection().Snooze()
-		case "enter":
+		case "snooze":
+			current := t.currentSelection()
+			t.stats.record(current, t.track([]*tuido.Item{current}, current.Snooze))
+			t.touch()
+		case "snoozeuntil":
+			t.setSnoozingMode()
+		case "files":
+			t.cycleRecentFiles()
+		case "browse":
+			t.setBrowseMode()
+		case "global":
+			t.toggleProjectOnly()
+		case "legend":
+			t.setLegendMode()
+		case "colors":
+			// reshuffle the tag palette
+			cfg := t.config
+			cfg.rainbow = true
+			t.tagColors = populateTagColorStyles(t.items, cfg)
+		case "columns":
+			t.multiColumn = !t.multiColumn
+		case "prevcolumn":
+			if t.board {
+				t.moveBoardColumn(-1)
+			} else {
+				t.moveColumn(-1)
+			}
+		case "nextcolumn":
+			if t.board {
+				t.moveBoardColumn(1)
+			} else {
+				t.moveColumn(1)
+			}
+		case "board":
+			t.toggleBoard()
+		case "markdown":
+			return t, t.copyMarkdown()
+		case "reference":
+			return t, t.copyReference(false)
+		case "copy":
+			return t, t.copyReference(true)
+		case "summary":
+			t.mode = summarizing
+		case "delete":
+			t.setDeleteMode()
+		case "link":
+			t.openLink()
+		case "moveup":
+			t.moveItem(-1)
+		case "movedown":
+			t.moveItem(1)
+		case "export":
+			t.setExportMode()
+		case "focus":
+			t.marked = nil // focus mode triages one item at a time
+			t.mode = focus
+		case "stalled":
+			t.stalledOnly = !t.stalledOnly
+			t.populateRenderSelection()
+		case "batch":
+			t.setBatchMode()
+		case "undo":
+			t.applyUndo(false)
+		case "redo":
+			t.applyUndo(true)
+		case "ages":
+			t.showAges = !t.showAges
+			t.blameListed()
+		case "preview":
+			t.preview = !t.preview
+		case "mark":
+			t.toggleMark()
+		case "unmark":
+			t.marked = nil
+		case "sort":
+			t.sortMode = t.sortMode.next()
+			t.populateRenderSelection()
+		case "anyall":
+			t.filterAll = !t.filterAll
+			t.populateRenderSelection()
+		case "agenda":
+			if t.itemsFilter == done {
+				t.setArchiveMode()
+			} else {
+				t.agenda = !t.agenda
+				t.groups.active = t.groups.active && !t.agenda
+				t.populateRenderSelection()
+			}
+		case "groups":
+			t.toggleGroups()
+		case "views":
+			t.setViewsMode()
+		case "palette":
+			t.setPaletteMode()
+		case "collapse":
+			t.toggleCollapse()
+		case "fold":
+			t.toggleFold()
+		case "dates":
+			t.relativeDates = !t.relativeDates
+		case "layout":
+			t.tagLayout = t.tagLayout.next()
+		case "donerange":
+			if t.itemsFilter == done {
+				t.doneRange = t.doneRange.next()
+				t.populateRenderSelection()
+			}
+		case "peek":
 			t.setPeekMode()
-		case "q":
-			return t, tea.Quit
+		default:
+			if s, ok := t.config.statusCommand(command); ok {
+				t.setStatus(s)
+			}
 		}
 
+	case tea.MouseMsg:
+		t.updateMouse(msg)
+
 	case tea.WindowSizeMsg:
 		t.h = msg.Height
 		t.w = msg.Width
 	}
-	return t, nil
+	return t, t.flushHooks()
 }
 
 func (t *tui) tryCreateNewItem() {
@@ -220,13 +618,73 @@ func (t *tui) tryCreateNewItem() {
 	}
 }
 
+// createNewItem opens the prompt for a new item. Nothing is written
+// until the prompt is submitted.
 func (t *tui) createNewItem() {
-	newItem := tuido.New(t.config.writeto, -1, "")
-	t.items = append(t.items, &newItem)
-	// write directly to renderselection instead of repopulating,
-	// to avoid a sorting move before setSelection is called.
-	t.renderSelection = append(t.renderSelection, &newItem)
-
-	t.setSelection(len(t.renderSelection) - 1)
-	t.setEditMode()
+	t.itemEditor.SetValue("")
+	t.itemEditor.Focus()
+	t.insertBeside = false
+	t.mode = insert
+}
+
+// insertTarget is the location that a new item is appended to: the
+// writeto location, or else the current selection's file.
+func (t tui) insertTarget() string {
+	if current := t.currentSelection(); t.insertBeside && current != nil {
+		return current.File()
+	}
+	return t.config.writeto
+}
+
+// toggleInsertTarget switches new items between the writeto location and
+// the current selection's file, if it is a local file.
+func (t *tui) toggleInsertTarget() {
+	current := t.currentSelection()
+	if current == nil {
+		return
+	}
+	if _, err := os.Stat(current.File()); err != nil {
+		return
+	}
+	t.insertBeside = !t.insertBeside
+}
+
+// insertNewItem appends an item with text, and the configured default
+// status and tags, to the insertTarget. Empty text is discarded.
+func (t *tui) insertNewItem(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+
+	newItem, err := createItem(t.insertTarget(), text, t.config)
+	if err != nil {
+		t.err = err
+		return
+	}
+	t.stats.record(newItem, nil)
+	t.undo.push([]change{{newItem, created, ""}})
+	t.items = append(t.items, newItem)
+	t.refreshTagColors()
+	t.populateRenderSelection()
+
+	for i, item := range t.renderSelection {
+		if item == newItem {
+			t.setSelection(i)
+		}
+	}
+}
+
+// createItem appends an item with text to target, with the configured
+// default status and tags.
+func createItem(target, text string, cfg config) (*tuido.Item, error) {
+	for _, tag := range cfg.newTags {
+		text += " " + tuido.TagSigil + strings.TrimPrefix(tag, tuido.TagSigil)
+	}
+
+	item, err := tuido.Create(target, cfg.newStatus, text)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
 }
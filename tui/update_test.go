@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestFileChangeDuringNavigation runs the model in a bubbletea program,
+// and sends it navigation keys and file change events from several
+// goroutines at once, as the terminal input and the file watcher do.
+// Run with -race.
+func TestFileChangeDuringNavigation(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "todo.xit")
+	if err := os.WriteFile(file, []byte("[ ] one\n[ ] two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := getItems(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, typing, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer typing.Close()
+	prog := tea.NewProgram(newTUI(items, runConfig), tea.WithInput(input), tea.WithOutput(io.Discard), tea.WithoutRenderer())
+	done := make(chan tea.Model)
+	go func() {
+		model, err := prog.StartReturningModel()
+		if err != nil {
+			t.Error(err)
+		}
+		done <- model
+	}()
+
+	var senders sync.WaitGroup
+	for _, key := range []tea.KeyType{tea.KeyDown, tea.KeyUp, tea.KeyDown} {
+		senders.Add(1)
+		go func(key tea.KeyType) {
+			defer senders.Done()
+			for i := 0; i < 50; i++ {
+				prog.Send(tea.KeyMsg{Type: key})
+			}
+		}(key)
+	}
+	senders.Add(1)
+	go func() {
+		defer senders.Done()
+		os.WriteFile(file, []byte("[ ] one\n[ ] two\n[ ] three\n"), 0644)
+		for i := 0; i < 10; i++ {
+			prog.Send(fileChanged(file)())
+		}
+	}()
+	senders.Wait()
+	prog.Quit()
+
+	m := (<-done).(tui)
+	if len(m.items) != 3 {
+		t.Errorf("expected 3 items after reload, but found %d", len(m.items))
+	}
+	if len(m.renderSelection) != 3 {
+		t.Errorf("expected 3 listed items after reload, but found %d", len(m.renderSelection))
+	}
+}
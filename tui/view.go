@@ -2,10 +2,13 @@ package tui
 
 import (
 	"fmt"
+	"math"
+	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/charmbracelet/lipgloss"
 	lg "github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
 	"github.com/nilock/tuido/tuido"
 )
 
@@ -38,29 +41,83 @@ var ( // header styles
 	tabGapStyle lg.Style = tabStyle.Copy().BorderTop(false).BorderLeft(false).BorderRight(false)
 )
 
-func (t tui) header() string {
-	var todoTab, doneTab string
+// reviewStyle sets items awaiting review apart from other pending items
+var reviewStyle lg.Style = lg.NewStyle().Foreground(lg.Color("#d7af5f")).Bold(true)
 
-	if t.itemsFilter == todo {
-		todoTab = activeTabStyle.Render(string(todo))
-	} else {
-		todoTab = tabStyle.Render(string(todo))
-	}
+// overdueItemStyle marks items which are past their due date
+var overdueItemStyle lg.Style = lg.NewStyle().Foreground(lg.Color("#ff875f")).Underline(true)
 
-	if t.itemsFilter == done {
-		doneTab = activeTabStyle.Render(string(done))
-	} else {
-		doneTab = tabStyle.Render(string(done))
+// dueStyle marks an item's [x]it! `-> DATE` due date
+var dueStyle lg.Style = lg.NewStyle().Foreground(lg.Color("#5fafd7"))
+
+// priorityStyle is the badge of an item's leading importance markers
+var priorityStyle lg.Style = lg.NewStyle().Foreground(lg.Color("#ff5f87")).Bold(true)
+
+// markStyle is the gutter indicator of items marked for a bulk change
+var markStyle lg.Style = lg.NewStyle().Foreground(lg.Color("#5fafff")).Bold(true)
+
+// stalledStyle warns of ongoing items which have not been finished in a while
+var stalledStyle lg.Style = lg.NewStyle().Foreground(lg.Color("#ff5f5f")).Bold(true)
+
+// errorStyle reports errors in the footer
+var errorStyle lg.Style = lg.NewStyle().Foreground(lg.Color("#ff2222")).Bold(true)
+
+func (t tui) header() string {
+	rendered := []string{}
+	for _, tab := range tabs {
+		if t.itemsFilter == tab {
+			rendered = append(rendered, activeTabStyle.Render(string(tab)))
+		} else {
+			rendered = append(rendered, tabStyle.Render(string(tab)))
+		}
 	}
 
-	tabs := lg.JoinHorizontal(lg.Bottom, todoTab, doneTab)
-	searchBox := tabGapStyle.Render(t.filter.View())
+	tabRow := lg.JoinHorizontal(lg.Bottom, rendered...)
+	searchBox := t.filter.View()
+	if expr := parseFilter(t.filter.Value(), false).expr; expr != nil && expr.Err() != nil {
+		searchBox += errorStyle.Render("  " + expr.Err().Error())
+	}
+	if t.filterAll && len(parseFilter(t.filter.Value(), true).tags) > 1 {
+		searchBox += lg.NewStyle().Faint(true).Render("  all tags")
+	}
+	if t.fileFilter != "" {
+		searchBox += lg.NewStyle().Faint(true).Render("  in " + filepath.Base(t.fileFilter))
+	}
+	if t.stalledOnly && t.itemsFilter == todo {
+		searchBox += stalledStyle.Render("  stalled only")
+	}
+	if t.agendaActive() {
+		searchBox += lg.NewStyle().Faint(true).Render("  agenda")
+	}
+	if t.groupsActive() {
+		searchBox += lg.NewStyle().Faint(true).Render("  by file")
+	}
+	if t.projectOnly {
+		searchBox += lg.NewStyle().Faint(true).Render("  project only")
+	}
+	if t.dirScope != "" {
+		searchBox += lg.NewStyle().Faint(true).Render("  under " + filepath.Base(t.dirScope) + "/")
+	}
+	if t.itemsFilter == done && t.doneRange != anyTime {
+		searchBox += lg.NewStyle().Faint(true).Render("  completed " + t.doneRange.String())
+	}
+	searchBox = tabGapStyle.Render(searchBox)
 	helpPrompt := tabGapStyle.Copy().Faint(true).Render("? - help")
+	if t.w < narrowWidth {
+		helpPrompt = ""
+	}
 	gap := tabGapStyle.Render(strings.Repeat(" ", max(0, t.w-lg.Width(
-		lg.JoinHorizontal(lg.Bottom, tabs, searchBox, helpPrompt))-5),
+		lg.JoinHorizontal(lg.Bottom, tabRow, searchBox, helpPrompt))-5),
 	))
 
-	return lg.JoinHorizontal(lg.Bottom, tabs, searchBox, gap, helpPrompt)
+	row := lg.JoinHorizontal(lg.Bottom, tabRow, searchBox, gap, helpPrompt)
+	if t.w < narrowWidth {
+		row = lg.NewStyle().MaxWidth(t.w).Render(row)
+	}
+	if completions := t.completionsView(); completions != "" {
+		return lg.JoinVertical(lg.Left, row, completions)
+	}
+	return row
 }
 
 func (t tui) footer() string {
@@ -71,37 +128,95 @@ func (t tui) footer() string {
 
 	var right string
 
-	if t.err != nil {
-		right = lipgloss.
-			NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#ff2222")).
-			Render(t.err.Error())
+	if t.conflict != nil {
+		right = errorStyle.Render(t.conflictPrompt())
+	} else if t.err != nil {
+		right = errorStyle.Render(t.err.Error())
 	} else {
 
-		if t.mode == navigation {
-			right = footStyle.Render(t.pagination())
+		if t.mode == navigation && t.notice != "" {
+			right = footStyle.Copy().Faint(true).Render(t.notice)
+		} else if t.mode == navigation {
+			dates := "dates: iso"
+			if t.relativeDates {
+				dates = "dates: relative"
+			}
+			right = lg.JoinHorizontal(lg.Bottom,
+				footStyle.Copy().Faint(true).Render(t.markCount()+dates+"  "),
+				footStyle.Render(t.pagination()))
+		} else if t.mode == insert {
+			right = footStyle.Copy().Faint(true).
+				Render("[enter] - Add Item,  [tab] - Switch file,  [esc] - Cancel")
 		} else if t.mode == edit {
+			toggle := "Edit whole line"
+			if t.editRaw {
+				toggle = "Edit text"
+			}
 			right = footStyle.Copy().Faint(true).
-				Render("[enter] - Save Changes,  [esc] - Discard Changes")
+				Render("[enter] - Save Changes,  [tab] - " + toggle + ",  [esc] - Discard Changes")
 		} else if t.mode == peek {
-		  right = footStyle.Copy().Faint(true).Render("[esc] - Return to list view")
-    }
+			right = footStyle.Copy().Faint(true).Render("[esc] - Return to list view")
+		} else if t.mode == archiving {
+			right = footStyle.Copy().Bold(true).Render(t.archivePrompt())
+		} else if t.mode == choosingLink {
+			right = footStyle.Copy().Bold(true).Render(t.linkPrompt())
+		} else if t.mode == checkingSubtasks {
+			right = footStyle.Copy().Bold(true).Render(t.checkSubtasksPrompt())
+		} else if t.mode == deleting {
+			right = footStyle.Copy().Bold(true).Render(t.deletePrompt())
+		} else if t.mode == exporting {
+			right = footStyle.Copy().Bold(true).Render(t.exportPrompt())
+		} else if t.mode == batch {
+			right = footStyle.Copy().Bold(true).Render(t.batchPrompt())
+		} else if t.mode == browse {
+			right = footStyle.Copy().Faint(true).
+				Render("[enter] - Scope to folder,  [backspace] - Clear scope,  [esc] - Close")
+		} else if t.mode == legend && t.legend.pending != "" {
+			right = footStyle.Copy().Bold(true).Render("[y] - Apply and save color,  [any key] - Cancel")
+		} else if t.mode == legend && t.legend.editor.Focused() {
+			right = footStyle.Copy().Faint(true).Render("[enter] - Preview color,  [esc] - Cancel")
+		} else if t.mode == legend {
+			right = footStyle.Copy().Faint(true).Render("[f] - Filter by tag,  [backspace] - Clear filter,  [enter] - Set tag color,  [r] - Rename tag,  [esc] - Close")
+		} else if t.mode == picking && t.views.editor.Focused() {
+			right = footStyle.Copy().Faint(true).Render("[enter] - Save view,  [esc] - Cancel")
+		} else if t.mode == picking {
+			right = footStyle.Copy().Faint(true).Render("[enter]/[1-9] - Apply view,  [s] - Save filter,  [d] - Delete,  [esc] - Close")
+		} else if t.mode == jumping {
+			right = footStyle.Copy().Faint(true).Render("[up]/[down] - Choose,  [enter] - Jump to item,  [esc] - Close")
+		} else if t.mode == tagging || t.mode == snoozing {
+			right = footStyle.Copy().Faint(true).
+				Render("[enter] - Apply,  [esc] - Cancel")
+		} else if t.mode == note {
+			right = footStyle.Copy().Faint(true).
+				Render("[enter] - Save Note,  [esc] - Discard Changes")
+		}
 	}
 
 	spacerWidth := max(0, t.w-lg.Width(lg.JoinHorizontal(lg.Bottom, itemStr, right))-5)
 	gap := footStyle.Render(strings.Repeat(" ", spacerWidth))
 
+	// narrow windows show the prompt or message alone, cut to fit
+	if t.w < narrowWidth && right != "" {
+		return lg.NewStyle().MaxWidth(t.w).Render(right)
+	}
 	return lg.JoinHorizontal(lg.Bottom, itemStr, gap, right)
 }
 
+// markCount labels the number of marked items, if any.
+func (t tui) markCount() string {
+	if len(t.marked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d marked  ", len(t.marked))
+}
+
 func (t tui) pagination() string {
 	ret := ""
 	bold := lg.NewStyle().Bold(true).SetString("●")
 	faint := lg.NewStyle().Faint(true).SetString("●")
 
 	if t.pages > 1 {
-		if t.pages < 8 {
+		if t.pages < 8 && !activeTheme.plain {
 			for i := 0; i < t.pages; i++ {
 				if i == t.currentPage {
 					ret += bold.String()
@@ -110,9 +225,12 @@ func (t tui) pagination() string {
 				}
 			}
 		} else {
-			ret = faint.Render(fmt.Sprintf("%d of %d", t.currentPage+1, t.pages))
+			ret = faint.Render(fmt.Sprintf("page %d of %d", t.currentPage+1, t.pages))
 		}
 	}
+	if n := len(t.renderSelection); n > 0 {
+		ret += lg.NewStyle().Faint(true).Render(fmt.Sprintf("  item %d of %d", t.selection+1, n))
+	}
 	return ret
 }
 
@@ -120,6 +238,9 @@ func (t tui) View() string {
 	if t.h == 0 {
 		return ""
 	}
+	if t.loading {
+		return t.loadingView()
+	}
 	switch t.mode {
 	case nag:
 		return t.nag.View()
@@ -143,16 +264,17 @@ func (t tui) View() string {
 
 	case help:
 		controls := "\n[press any key to exit help]\n\n"
-		controls += "n: new item\ne: edit item\nz: snooze item\n!: escalate item\n1: relax item\np: begin a pomodoro\n\n"
-		controls += "x: mark done\ns: mark obsolete (strikethrough)\na: mark ongoing (at)\n[space]: mark open\n\n"
-		controls += "[tab]: cycle between todo and done tabs\n/: filter todos by tag\n?: enter help\n\n"
-		controls += "q: quit"
+		controls += t.keys.controls()
 
 		txt := lg.NewStyle().Width(28).Align(lg.Left).
 			Render("\n\n\ntuido reads txt, md, and xit files from the working directory and locates xit style todo items, allowing for quick navigation and discovery.\n\nUpdating an item's status in tuido writes the corresponding change to disk.")
 		return lg.JoinHorizontal(lg.Top, "  ", controls, "    ", txt)
 	case peek:
 		return t.peek.View(t.h, t.w, t.footer)
+	case focus:
+		return t.focusView()
+	case summarizing:
+		return t.summaryView()
 	default:
 		if len(t.renderSelection) == 0 { // init population
 			t.populateRenderSelection()
@@ -160,66 +282,225 @@ func (t tui) View() string {
 
 		header := t.header()
 		footer := t.footer()
+		status := t.statusBar()
 
 		rows := []string{}
 
-		availableHeight := t.h - (lg.Height(header) + lg.Height(footer))
+		availableHeight := t.h - (lg.Height(header) + lg.Height(status) + lg.Height(footer))
 
-		body := t.renderVisibleListedItems(availableHeight, t.w)
+		var prompt string
+		if t.mode == insert {
+			prompt = lg.NewStyle().Faint(true).Render("  new item in "+t.insertTarget()) + "\n  " + t.itemEditor.View()
+			availableHeight -= lg.Height(prompt)
+		}
+
+		// the preview is beside the list in wide windows, unless a side
+		// panel is open, and otherwise below it
+		sidePanel := t.mode == browse || t.mode == legend || t.mode == picking || t.mode == jumping
+		besidePreview := t.preview && t.previewBeside() && !sidePanel
+		var preview string
+		if t.preview && !besidePreview {
+			preview = t.previewPanel(availableHeight, false)
+			availableHeight -= lg.Height(preview)
+		}
+
+		var body string
+		if t.mode == jumping {
+			body = t.palette.View(t.w, availableHeight)
+		} else if t.mode == browse {
+			panel := t.browser.View(t.config.root, availableHeight)
+			body = lg.JoinHorizontal(lg.Top, panel, t.renderVisibleListedItems(availableHeight, t.w-lg.Width(panel)))
+		} else if t.mode == legend {
+			panel := t.legend.View(t.tagColors, availableHeight)
+			body = lg.JoinHorizontal(lg.Top, panel, t.renderVisibleListedItems(availableHeight, t.w-lg.Width(panel)))
+		} else if t.mode == picking {
+			panel := t.views.View(t.config.views, availableHeight)
+			body = lg.JoinHorizontal(lg.Top, panel, t.renderVisibleListedItems(availableHeight, t.w-lg.Width(panel)))
+		} else if t.board {
+			body = t.renderBoard(availableHeight, t.w)
+		} else if besidePreview {
+			panel := t.previewPanel(availableHeight, true)
+			body = lg.JoinHorizontal(lg.Top, t.renderVisibleListedItems(availableHeight, t.w-lg.Width(panel)), panel)
+		} else {
+			body = t.renderVisibleListedItems(availableHeight, t.w)
+		}
 
 		// recalculate footer because pages data was set during body render
-		rows = append(rows, header, body, t.footer())
+		rows = append(rows, header, body)
+		if preview != "" {
+			rows = append(rows, preview)
+		}
+		if prompt != "" {
+			rows = append(rows, prompt)
+		}
+		rows = append(rows, status, t.footer())
 		return lg.JoinVertical(lg.Left, rows...)
 	}
 }
 
 func (t *tui) renderVisibleListedItems(height, width int) string {
-	renderedItems := t.renderedItemCollection(width - 1) // providing a margin
+	columns := t.columnCount()
+	colWidth := width / columns
+
+	renderedItems := t.renderedItemCollection(colWidth - 1) // providing a margin
+	stacks, starts := stackItems(renderedItems, height)
+
+	// the column holding the current selection
+	current := 0
+	for c, start := range starts {
+		if t.selection >= start {
+			current = c
+		}
+	}
+
+	t.currentPage = current / columns
+	t.pages = (len(stacks) + columns - 1) / columns
+
+	visible := []string{}
+	for c := t.currentPage * columns; c < len(stacks) && c < (t.currentPage+1)*columns; c++ {
+		visible = append(visible, lg.NewStyle().Width(colWidth).Render(stacks[c]))
+	}
+	renderedList := lg.JoinHorizontal(lg.Top, visible...)
+
+	gap := height - lg.Height(renderedList)
+	for i := 0; i < gap; i++ {
+		renderedList += "\n"
+	}
+
+	return lg.NewStyle().MaxHeight(height).Render(
+		renderedList,
+	)
+}
 
-	pages := []string{}
+// stackItems stacks rendered items vertically into as many stacks (pages,
+// or columns) of the given height as are required. It returns the stacks,
+// and the index of the first item in each stack.
+func stackItems(renderedItems []string, height int) ([]string, []int) {
+	stacks := []string{}
+	starts := []int{0}
 
-	pageUnderConstruction := ""
+	stackUnderConstruction := ""
 
 	for i, renderedItem := range renderedItems {
-		pagePlusNextItem := ""
+		stackPlusNextItem := ""
 
-		if pageUnderConstruction == "" {
-			pagePlusNextItem = renderedItem // do not vertically stack the empty page w/ the renderedItem
+		if stackUnderConstruction == "" {
+			stackPlusNextItem = renderedItem // do not vertically stack the empty stack w/ the renderedItem
 		} else {
-			pagePlusNextItem = lg.JoinVertical(lg.Left,
-				pageUnderConstruction,
+			stackPlusNextItem = lg.JoinVertical(lg.Left,
+				stackUnderConstruction,
 				renderedItem,
 			)
 		}
 
-		if lg.Height(pagePlusNextItem) <= height {
-			pageUnderConstruction = pagePlusNextItem
+		if lg.Height(stackPlusNextItem) <= height || stackUnderConstruction == "" {
+			stackUnderConstruction = stackPlusNextItem
 		} else {
-			pages = append(pages, pageUnderConstruction)
-			pageUnderConstruction = renderedItem
+			stacks = append(stacks, stackUnderConstruction)
+			starts = append(starts, i)
+			stackUnderConstruction = renderedItem
 		}
+	}
 
-		if i == t.selection {
-			t.currentPage = len(pages)
+	if len(stacks) == 0 || len(stackUnderConstruction) != 0 {
+		stacks = append(stacks, stackUnderConstruction)
+	}
+
+	return stacks, starts
+}
+
+// minColumnWidth is the narrowest column allowed by automatic columns.
+const minColumnWidth = 60
+
+// columnCount returns the number of columns of items to render.
+func (t tui) columnCount() int {
+	if !t.multiColumn {
+		return 1
+	}
+	if t.config.columns > 1 {
+		return t.config.columns
+	}
+	return max(1, t.w/minColumnWidth)
+}
+
+// moveColumn moves the selection delta columns to the left or right,
+// keeping its position within the column where possible.
+func (t *tui) moveColumn(delta int) {
+	columns := t.columnCount()
+	if columns == 1 {
+		return
+	}
+
+	_, starts := stackItems(t.renderedItemCollection(t.w/columns-1), t.listHeight())
+	starts = append(starts, len(t.renderSelection))
+
+	for c := 0; c < len(starts)-1; c++ {
+		if t.selection >= starts[c] && t.selection < starts[c+1] {
+			target := c + delta
+			if target < 0 || target >= len(starts)-1 {
+				return
+			}
+			offset := t.selection - starts[c]
+			t.setSelection(min(starts[target]+offset, starts[target+1]-1))
+			return
 		}
 	}
+}
 
-	if len(pages) == 0 || len(pageUnderConstruction) != 0 {
-		pages = append(pages, pageUnderConstruction)
+// movePage moves the selection to the first item of the page delta pages
+// away. Moving past the first or last page selects the first or last item.
+func (t *tui) movePage(delta int) {
+	if len(t.renderSelection) == 0 {
+		return
 	}
+	columns := t.columnCount()
+	_, starts := stackItems(t.renderedItemCollection(t.w/columns-1), t.listHeight())
 
-	t.pages = len(pages)
+	pages := []int{}
+	for c := 0; c < len(starts); c += columns {
+		pages = append(pages, starts[c])
+	}
 
-	renderedList := pages[t.currentPage]
+	current := 0
+	for p, start := range pages {
+		if t.selection >= start {
+			current = p
+		}
+	}
 
-	gap := height - lg.Height(renderedList)
-	for i := 0; i < gap; i++ {
-		renderedList += "\n"
+	target := current + delta
+	switch {
+	case target < 0:
+		t.setSelection(0)
+	case target >= len(pages):
+		t.setSelection(len(t.renderSelection) - 1)
+	default:
+		t.setSelection(pages[target])
 	}
+}
 
-	return lg.NewStyle().MaxHeight(height).Render(
-		renderedList,
-	)
+// moveHalfPage moves the selection by half of the items of the current
+// page, down for a positive dir and up for a negative one.
+func (t *tui) moveHalfPage(dir int) {
+	if len(t.renderSelection) == 0 {
+		return
+	}
+	_, starts := stackItems(t.renderedItemCollection(t.w/t.columnCount()-1), t.listHeight())
+	starts = append(starts, len(t.renderSelection))
+
+	perPage := len(t.renderSelection)
+	for c := 0; c < len(starts)-1; c++ {
+		if t.selection >= starts[c] && t.selection < starts[c+1] {
+			perPage = starts[c+1] - starts[c]
+		}
+	}
+	t.setSelection(t.selection + dir*max(1, perPage/2))
+}
+
+// listHeight is the height available to the item list, between the
+// header and the footer.
+func (t tui) listHeight() int {
+	return t.h - (lg.Height(t.header()) + lg.Height(t.footer()))
 }
 
 func (t tui) renderedItemCollection(width int) []string {
@@ -227,20 +508,63 @@ func (t tui) renderedItemCollection(width int) []string {
 	selected := lg.NewStyle().Bold(true)
 
 	renderedItems := []string{}
+	now := time.Now()
 
 	for i, item := range t.renderSelection {
 		renderedItem := ""
+		itemWidth := width - 2*t.depths[item]
 		if i == t.selection {
 			cursor := "> "
+			if t.marked[item] {
+				cursor = ">" + markStyle.Render("*")
+			}
 			if t.mode == edit {
 				renderedItem = lg.JoinHorizontal(lg.Top, cursor, selected.Render(t.itemEditor.View()))
+			} else if t.mode == note {
+				renderedItem = lg.JoinVertical(lg.Left,
+					lg.JoinHorizontal(lg.Top, cursor, selected.Render(t.renderTuido(*item, itemWidth))),
+					lg.NewStyle().PaddingLeft(4).Render(t.noteEditor.View()),
+				)
+			} else if t.mode == tagging {
+				renderedItem = lg.JoinVertical(lg.Left,
+					lg.JoinHorizontal(lg.Top, cursor, selected.Render(t.renderTuido(*item, itemWidth))),
+					"    "+t.tagEditor.View(),
+				)
+			} else if t.mode == snoozing {
+				renderedItem = lg.JoinVertical(lg.Left,
+					lg.JoinHorizontal(lg.Top, cursor, selected.Render(t.renderTuido(*item, itemWidth))),
+					"    "+t.snoozeEditor.View(),
+				)
 			} else {
-				renderedItem = lg.JoinHorizontal(lg.Top, cursor, selected.Render(t.renderTuido(*item, width)))
+				renderedItem = lg.JoinHorizontal(lg.Top, cursor, selected.Render(t.renderTuido(*item, itemWidth)))
 			}
 
 		} else {
 			leadingSpace := "  "
-			renderedItem = lg.JoinHorizontal(lg.Top, leadingSpace, t.renderTuido(*item, width))
+			if t.marked[item] {
+				leadingSpace = markStyle.Render("*") + " "
+			}
+			renderedItem = lg.JoinHorizontal(lg.Top, leadingSpace, t.renderTuido(*item, itemWidth))
+		}
+		if depth := t.depths[item]; depth > 0 {
+			renderedItem = lg.JoinHorizontal(lg.Top, strings.Repeat("  ", depth), renderedItem)
+		}
+		if t.agendaActive() {
+			if header := t.bucketHeader(i, now); header != "" {
+				renderedItem = lg.JoinVertical(lg.Left, header, renderedItem)
+			}
+		}
+		if t.groupsActive() {
+			header := t.groupHeader(i)
+			if t.onCollapsedGroup(i) {
+				cursor := "  "
+				if i == t.selection {
+					cursor = "> "
+				}
+				renderedItem = cursor + header
+			} else if header != "" {
+				renderedItem = lg.JoinVertical(lg.Left, header, renderedItem)
+			}
 		}
 		renderedItems = append(renderedItems, renderedItem)
 	}
@@ -250,22 +574,213 @@ func (t tui) renderedItemCollection(width int) []string {
 // renderTuido applies tagColor to the items tags, splits long items
 // over multiple lines, and returns the text
 func (t tui) renderTuido(item tuido.Item, width int) string {
-	ret := item.String()
+	str := item.String()
+	box, body := str[:4], str[4:]
 	tags := item.Tags()
 
+	if item.Satus() == tuido.Review {
+		box = reviewStyle.Render(str[:3]) + " "
+	}
+	if custom, ok := t.customBox(item, str[:3]); ok {
+		box = custom + " "
+	}
+	if item.Overdue() && t.itemsFilter == todo {
+		box = overdueItemStyle.Render(str[:3]) + " "
+	}
+	if item.Stalled(t.config.stalled) {
+		box = stalledStyle.Render(str[:3]) + " "
+	} else if t.stale(item) {
+		box = lg.NewStyle().Faint(true).Render(str[:3]) + " "
+	}
+	if activeTheme.plain {
+		box = plainBox(item)
+	}
+
+	if t.showAges {
+		box = t.renderAge(item) + " " + box
+	}
+
+	if words := parseFilter(t.filter.Value(), t.filterAll).words; len(words) > 0 {
+		body = highlightWords(body, words, tags, highlightStyle.Render)
+	}
+
+	if marker := item.PriorityMarker(); marker != "" {
+		body = priorityStyle.Render(marker) + strings.TrimPrefix(body, marker)
+	}
+
+	if marker := item.DueMarker(); marker != "" {
+		style := dueStyle
+		if item.Overdue() && t.itemsFilter == todo {
+			style = overdueItemStyle
+		}
+		body = strings.Replace(body, marker, style.Render(marker), 1)
+	}
+
+	for _, url := range item.URLs() {
+		body = strings.Replace(body, url, urlStyle.Render(url), 1)
+	}
+
+	now := time.Now()
+	renderedTags := []string{}
 	for _, tag := range tags {
-		ret = strings.ReplaceAll(ret, "#"+tag.String(), t.tagColors[tag.Name()].Render("#"+tag.String()))
+		renderedTags = append(renderedTags, t.tagColors[tag.Name()].Render(t.tagText(tag, now)))
+	}
+
+	// cap the number of tags shown, per the maxtags config. Hidden tags
+	// still participate in filtering and coloring.
+	shown := len(tags)
+	if t.config.maxTags > 0 && shown > t.config.maxTags {
+		shown = t.config.maxTags
+	}
+	more := ""
+	if hidden := len(tags) - shown; hidden > 0 {
+		more = lg.NewStyle().Faint(true).Render(fmt.Sprintf(" +%d", hidden))
+	}
+
+	switch t.tagLayout {
+	case tagsInline:
+		// tags are matched as whole words, in order, so that hiding #due
+		// leaves #duedate be
+		pending := map[string][]int{}
+		for i, tag := range tags {
+			pending[tag.Token()] = append(pending[tag.Token()], i)
+		}
+		words := []string{}
+		for _, word := range strings.Split(body, " ") {
+			if queue := pending[word]; len(queue) > 0 {
+				pending[word] = queue[1:]
+				if queue[0] >= shown {
+					continue
+				}
+				word = renderedTags[queue[0]]
+			}
+			words = append(words, word)
+		}
+		body = strings.Join(words, " ") + more
+	case tagsBelow:
+		body = stripTags(body)
+		if len(tags) != 0 {
+			body += glyph("\n", "  ") + strings.Join(renderedTags[:shown], " ") + more
+		}
+	case tagsCollapsed:
+		body = stripTags(body)
+		if len(tags) != 0 {
+			body += lg.NewStyle().Faint(true).Render(fmt.Sprintf(" +%d tags", len(tags)))
+		}
+	}
+
+	// subtask progress for parent items
+	if finished, total := item.Progress(); total > 0 {
+		body += lg.NewStyle().Faint(true).Render(fmt.Sprintf(" [%d/%d]", finished, total))
+		if t.foldedItem(item) {
+			body += lg.NewStyle().Faint(true).Render(glyph(" ▸", " (folded)"))
+		}
+	}
+
+	// indicate items with sidecar notes
+	if t.notes.get(&item) != "" {
+		body += lg.NewStyle().Faint(true).Render(glyph(" ✎", " (note)"))
+	}
+
+	// the plain theme flags items in words, and cuts them to one line
+	if activeTheme.plain {
+		return lg.NewStyle().MaxWidth(width - 2).Render(box + body + t.plainFlags(item))
 	}
 
 	// +2 here because of the leading 'cursor' space
-	if len(ret)+2 > width {
-		rowsRequired := (len(ret) - 4) / (width - 6) // -6 here instead of 4 because of the cursor spaces
-		bodyStyle := lg.NewStyle().Height(rowsRequired)
+	if lg.Width(box+body)+2 > width || strings.Contains(body, "\n") {
+		boxWidth := lg.Width(box)
+		bodyStyle := lg.NewStyle().Width(width - boxWidth - 2) // -2 for the cursor spaces
 
-		ret = lg.JoinHorizontal(lg.Top, bodyStyle.Width(4).Render(ret[:4]), bodyStyle.Width(width-6).Render(ret[4:]))
+		return lg.JoinHorizontal(lg.Top, lg.NewStyle().Width(boxWidth).Render(box), bodyStyle.Render(body))
 	}
 
-	return ret
+	return box + body
+}
+
+// tagText is the displayed text of a tag. With relative dates toggled
+// on, date values are shown relative to now (eg, `#due=in 3 days`).
+func (t tui) tagText(tag tuido.Tag, now time.Time) string {
+	if !t.relativeDates {
+		return tag.Token()
+	}
+	date, err := time.Parse("2006-01-02", tag.Value())
+	if err != nil {
+		return tag.Token()
+	}
+	return tuido.TagSigil + tag.Name() + "=" + relativeDate(date, now)
+}
+
+// relativeDate describes date in days from now.
+func relativeDate(date, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(day.Sub(today).Hours() / 24)
+
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	case days > 0:
+		return fmt.Sprintf("in %d days", days)
+	default:
+		return fmt.Sprintf("%d days ago", -days)
+	}
+}
+
+// staleAge is the item age, in days, rendered fully red in the age column.
+const staleAge = 60
+
+// renderAge renders the days since the item's creation (see Age), colored
+// along a gradient from fresh (green) to stale (red).
+func (t tui) renderAge(item tuido.Item) string {
+	age, ok := item.Age()
+	if !ok {
+		return lg.NewStyle().Faint(true).Render(fmt.Sprintf("%4s", "-"))
+	}
+
+	days := int(age.Hours() / 24)
+	frac := math.Min(math.Max(float64(days)/staleAge, 0), 1)
+
+	if activeTheme.mono {
+		return fmt.Sprintf("%3dd", days)
+	}
+	fresh := colorful.Hcl(130, t.config.chroma, t.config.lightness)
+	stale := colorful.Hcl(10, t.config.chroma, t.config.lightness)
+	color := fresh.BlendHcl(stale, frac).Clamped().Hex()
+
+	return lg.NewStyle().Foreground(lg.Color(color)).Render(fmt.Sprintf("%3dd", days))
+}
+
+// tagLayout is the presentation of tags in item rows.
+type tagLayout int
+
+const (
+	// tagsInline renders tags in place in the item text
+	tagsInline tagLayout = iota
+	// tagsBelow moves tags to a second line under the item text
+	tagsBelow
+	// tagsCollapsed hides tags behind a count
+	tagsCollapsed
+)
+
+func (l tagLayout) next() tagLayout {
+	return (l + 1) % (tagsCollapsed + 1)
+}
+
+// stripTags removes #tag tokens from s.
+func stripTags(s string) string {
+	kept := []string{}
+	for _, token := range strings.Split(s, " ") {
+		if tuido.IsTagToken(token) {
+			continue
+		}
+		kept = append(kept, token)
+	}
+	return strings.TrimRight(strings.Join(kept, " "), " ")
 }
 
 func min(a, b int) int {
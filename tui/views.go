@@ -0,0 +1,264 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// savedView is a filter expression saved under a name.
+type savedView struct {
+	name   string
+	filter string
+}
+
+// parseViews reads a `name:filter;name:filter` list of saved views.
+// Filters may hold spaces, and anything but `;`.
+func parseViews(s string) []savedView {
+	views := []savedView{}
+	for _, entry := range strings.Split(s, ";") {
+		split := strings.SplitN(entry, ":", 2)
+		if len(split) != 2 || strings.TrimSpace(split[0]) == "" {
+			if strings.TrimSpace(entry) != "" {
+				fmt.Fprintf(os.Stderr, "ignoring view %s: not a name:filter pair\n", entry)
+			}
+			continue
+		}
+		views = append(views, savedView{strings.TrimSpace(split[0]), strings.TrimSpace(split[1])})
+	}
+	return views
+}
+
+func formatViews(views []savedView) string {
+	entries := []string{}
+	for _, v := range views {
+		entries = append(entries, v.name+":"+v.filter)
+	}
+	return strings.Join(entries, ";")
+}
+
+// mergeViews returns base with the views of over layered on top: a view
+// of over replaces the base view of the same name.
+func mergeViews(base, over []savedView) []savedView {
+	merged := append([]savedView{}, base...)
+	for _, v := range over {
+		merged = putView(merged, v)
+	}
+	return merged
+}
+
+// putView replaces the view of v's name in views, or appends v.
+func putView(views []savedView, v savedView) []savedView {
+	for i := range views {
+		if views[i].name == v.name {
+			views[i] = v
+			return views
+		}
+	}
+	return append(views, v)
+}
+
+// viewPicker is the side panel listing saved views, from which a view
+// is applied, or the current filter saved as one.
+type viewPicker struct {
+	cursor int
+	// editor is the prompt for the name to save the current filter as
+	editor textinput.Model
+}
+
+// setViewsMode opens the saved views side panel.
+func (t *tui) setViewsMode() {
+	editor := textinput.New()
+	editor.Prompt = "save as: "
+	t.views = viewPicker{editor: editor}
+	t.mode = picking
+}
+
+// updateViews processes keystrokes in the saved views panel. [enter] or
+// a view's number applies it; `s` saves the current filter, and `d`
+// deletes the selected view.
+func (t *tui) updateViews(msg tea.KeyMsg) tea.Cmd {
+	p := &t.views
+	views := t.config.views
+
+	if p.editor.Focused() {
+		switch msg.String() {
+		case "esc":
+			p.editor.Blur()
+		case "enter":
+			name := strings.TrimSpace(p.editor.Value())
+			p.editor.Blur()
+			if name == "" {
+				return nil
+			}
+			t.err = t.saveView(savedView{name, strings.TrimSpace(t.filter.Value())})
+		default:
+			var cmd tea.Cmd
+			p.editor, cmd = p.editor.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	switch key := msg.String(); key {
+	case "up", "k":
+		p.cursor = max(p.cursor-1, 0)
+	case "down", "j":
+		p.cursor = max(min(p.cursor+1, len(views)-1), 0)
+	case "enter":
+		if len(views) == 0 {
+			return nil
+		}
+		if p.cursor < len(views) {
+			t.applyView(views[p.cursor])
+		}
+	case "s":
+		if strings.TrimSpace(t.filter.Value()) == "" {
+			t.err = fmt.Errorf("the filter is empty - nothing to save")
+			return nil
+		}
+		p.editor.SetValue("")
+		p.editor.Focus()
+	case "d":
+		if len(views) == 0 {
+			return nil
+		}
+		if p.cursor < len(views) {
+			t.err = t.deleteView(views[p.cursor].name)
+			p.cursor = max(min(p.cursor, len(t.config.views)-1), 0)
+		}
+	case "esc", "V":
+		t.mode = navigation
+	default:
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if n := int(key[0] - '1'); n < len(views) {
+				t.applyView(views[n])
+			}
+		}
+	}
+	return nil
+}
+
+// applyView sets the filter to a saved view's, and returns to the list.
+func (t *tui) applyView(v savedView) {
+	// a trailing space matches the last tag exactly
+	t.filter.SetValue(v.filter + " ")
+	t.mode = navigation
+	t.notice = "view: " + v.name
+	t.populateRenderSelection()
+}
+
+// saveView adds (or replaces) a saved view, and saves it to the user
+// configuration file.
+func (t *tui) saveView(v savedView) error {
+	// the views are stored as a `name:filter;name:filter` list
+	if strings.ContainsAny(v.name, ":;") {
+		return fmt.Errorf("view names cannot hold `:` or `;` - not saving %s", v.name)
+	}
+	if strings.Contains(v.filter, ";") {
+		return fmt.Errorf("view filters cannot hold `;` - not saving %s", v.name)
+	}
+	t.config.views = putView(t.config.views, v)
+	t.notice = "saved view " + v.name
+	return editUserViews(func(views []savedView) []savedView {
+		return putView(views, v)
+	})
+}
+
+// deleteView removes a saved view, and deletes it from the user
+// configuration file. Views of a project `.tuido` file are only removed
+// for this run.
+func (t *tui) deleteView(name string) error {
+	t.config.views = withoutView(t.config.views, name)
+	return editUserViews(func(views []savedView) []savedView {
+		return withoutView(views, name)
+	})
+}
+
+func withoutView(views []savedView, name string) []savedView {
+	kept := []savedView{}
+	for _, v := range views {
+		if v.name != name {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// editUserViews rewrites the `views` line of the user configuration file
+// with the result of edit, applied to the views it holds.
+func editUserViews(edit func([]savedView) []savedView) error {
+	path, err := userConfigPath()
+	if err != nil {
+		return err
+	}
+	views := []savedView{}
+	if cfg := parseConfigIfExists(path); cfg != nil {
+		views = cfg.views
+	}
+	return setConfigLine(path, "views", formatViews(edit(views)))
+}
+
+func (p viewPicker) View(views []savedView, height int) string {
+	rows := []string{}
+	for i, v := range views {
+		row := fmt.Sprintf("%d %s", i+1, v.name)
+		if i >= 9 {
+			row = "  " + v.name
+		}
+		if i == p.cursor {
+			row = lg.NewStyle().Bold(true).Render("> ") + row
+		} else {
+			row = "  " + row
+		}
+		rows = append(rows, row, lg.NewStyle().Faint(true).Render("    "+v.filter))
+	}
+	if len(views) == 0 {
+		rows = append(rows, lg.NewStyle().Faint(true).Render("no saved views.\n[s] saves the filter"))
+	}
+
+	if p.editor.Focused() {
+		rows = append(rows, "", p.editor.View())
+	}
+
+	return lg.NewStyle().
+		Width(browserWidth).
+		Height(height).
+		MaxHeight(height).
+		Border(themeBorder(lg.NormalBorder()), false, true, false, false).
+		Render(strings.Join(rows, "\n"))
+}
+
+// setConfigLine writes the `name=value` line of the configuration file
+// at path, replacing an existing line of that name, and creating the
+// line (or the file) if necessary.
+func setConfigLine(path, name, value string) error {
+	line := name + "=" + value
+
+	lines := []string{}
+	if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+
+	replaced := false
+	for i, l := range lines {
+		if strings.HasPrefix(l, name+"=") {
+			lines[i] = line
+			replaced = true
+		}
+	}
+	if !replaced {
+		// config is read from the top of the file, so new settings go first
+		lines = append([]string{line}, lines...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSavedViews(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tuido.conf")
+	os.WriteFile(path, []byte("views=work:#work #due=2022-06-01;home:#home\nmaxtags=2\n"), 0644)
+
+	cfg := parseConfigIfExists(path)
+	if cfg == nil || len(cfg.views) != 2 || cfg.views[0].filter != "#work #due=2022-06-01" {
+		t.Fatalf("expected two views, with filters holding `=`, but found %v", cfg)
+	}
+	if cfg.maxTags != 2 {
+		t.Errorf("expected the config after the views line to be read")
+	}
+
+	views := putView(cfg.views, savedView{"home", "#home -#chores"})
+	if err := setConfigLine(path, "views", formatViews(views)); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "views=work:#work #due=2022-06-01;home:#home -#chores\nmaxtags=2\n") {
+		t.Errorf("expected the views line replaced in place, but found %q", data)
+	}
+
+	m := newTUI(newItems("[ ] at the office #work", "[ ] at home #home"), runConfig)
+	m.applyView(views[1])
+	if len(m.renderSelection) != 1 || m.renderSelection[0].Text() != "at home #home" {
+		t.Errorf("expected the home view applied, but found %v", m.renderSelection)
+	}
+}
+
+func TestSavedViewsEmpty(t *testing.T) {
+	m := newTUI(newItems("[ ] at home #home"), runConfig)
+	m.config.views = nil
+	m.setViewsMode()
+
+	// no views leaves nothing to select, apply, or delete
+	for _, key := range []string{"j", "d"} {
+		m.updateViews(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	m.updateViews(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.views.cursor != 0 {
+		t.Errorf("expected the cursor to stay at 0, but found %d", m.views.cursor)
+	}
+
+	for _, v := range []savedView{{"a:b", "#home"}, {"a;b", "#home"}, {"home", "#home;#work"}} {
+		if err := m.saveView(v); err == nil {
+			t.Errorf("expected an error saving the view %q:%q", v.name, v.filter)
+		}
+	}
+	if len(m.config.views) != 0 {
+		t.Errorf("expected no views to be saved, but found %v", m.config.views)
+	}
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+	"github.com/nilock/tuido/tuido"
+)
+
+// watchDebounce is how long a file must be quiet after a change before it
+// is reloaded, so that a single save (often several writes) reloads once.
+const watchDebounce = 200 * time.Millisecond
+
+// watch reports changes to files, and to other parsed files in their
+// directories, to prog as fileChangedMsgs. Watching continues in the
+// background for the life of the program.
+func watch(prog *tea.Program, files []string, extensions []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	known := map[string]bool{}
+	dirs := map[string]bool{}
+	for _, f := range files {
+		known[f] = true
+		dir := filepath.Dir(f)
+		if !dirs[dir] {
+			dirs[dir] = true
+			if err := watcher.Add(dir); err != nil {
+				skipped.skip(dir, "not watched: %s", err)
+			}
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var mu sync.Mutex
+		timers := map[string]*time.Timer{}
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&fsnotify.Create != 0 && watchNewDir(watcher, event.Name) {
+					// files created along with the directory, eg by a
+					// move or checkout, are read now that it is watched
+					for _, f := range getFiles(event.Name, extensions) {
+						prog.Send(fileChangedMsg{f})
+					}
+					continue
+				}
+				if !known[event.Name] && !tuido.HasExtension(event.Name, extensions) {
+					continue
+				}
+
+				file := event.Name
+				mu.Lock()
+				if timer, ok := timers[file]; ok {
+					timer.Reset(watchDebounce)
+				} else {
+					timers[file] = time.AfterFunc(watchDebounce, func() {
+						mu.Lock()
+						delete(timers, file)
+						mu.Unlock()
+						prog.Send(fileChangedMsg{file})
+					})
+				}
+				mu.Unlock()
+
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// watchNewDir watches path, and its subdirectories, if it is a newly
+// created directory which is not skipped by the scan. It reports
+// whether path is a directory.
+func watchNewDir(watcher *fsnotify.Watcher, path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+
+	filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		if tuido.SkipsDir(info.Name(), runConfig.skipDirs) || p == runConfig.archive {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(p); err != nil {
+			skipped.skip(p, "not watched: %s", err)
+		}
+		return nil
+	})
+	return true
+}
@@ -0,0 +1,206 @@
+package tuido
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Archive moves items out of their source files, appending them to
+// target (a file, eg done.md, or a directory receiving datestamped .xit
+// files). Archived items are tagged with their original location, eg
+// #from=notes.xit:12, and with a #completed date, if they have none.
+//
+// Every item's line is checked before anything is written, so that a
+// stale item is refused without archiving the others. Items are appended
+// to the archive before they are removed from their sources, so that a
+// failure writing a source leaves items duplicated rather than lost. It
+// returns the source files which were changed; the line numbers of the
+// items remaining in those files are stale.
+func Archive(items []*Item, target string) ([]string, error) {
+	byFile := map[string][]*Item{}
+	for _, item := range items {
+		if item.ReadOnly() {
+			return nil, fmt.Errorf("item is read-only - cannot archive %s", item.Location())
+		}
+		byFile[item.file] = append(byFile[item.file], item)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	// every source line is checked before the archive is written, so that
+	// stale items are refused without being archived
+	remaining := map[string][]byte{}
+	for file, fileItems := range byFile {
+		content, err := withoutLines(file, fileItems)
+		if err != nil {
+			return nil, err
+		}
+		remaining[file] = content
+	}
+
+	// a missing target without an extension is taken to be a directory
+	dir := filepath.Dir(target)
+	if _, err := os.Stat(target); os.IsNotExist(err) && filepath.Ext(target) == "" {
+		dir = target
+	}
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, err
+	}
+
+	// items are written in the syntax of the archive
+	dest := appendTarget(target)
+	today := time.Now().Format("2006-01-02")
+	lines := []string{}
+	for _, item := range items {
+		x := item.trimmed()
+		if item.Completed() == nil {
+			x += " " + Tag{"completed", today}.Token()
+		}
+		x += " " + Tag{"from", item.Location()}.Token()
+		lines = append(lines, syntaxFor(dest).encode("", x))
+	}
+	f, err := os.OpenFile(dest, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for file, content := range remaining {
+		if err := writeContent(file, content); err != nil {
+			return changed, err
+		}
+		changed = append(changed, file)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// removeLines deletes the lines of items from file, in a single write,
+// after checking that every line is still as expected. Within a Batch,
+// the batch's copy of file is changed.
+func removeLines(file string, items []*Item) error {
+	content, err := withoutLines(file, items)
+	if err != nil {
+		return err
+	}
+	return writeContent(file, content)
+}
+
+// withoutLines returns the content of file less the lines of items, or an
+// error if any of the lines is no longer as expected.
+func withoutLines(file string, items []*Item) ([]byte, error) {
+	content, err := readContent(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// a byte order mark is kept at the start of the file, but is not part
+	// of the first line
+	text := string(content)
+	bom := ""
+	if strings.HasPrefix(text, utf8BOM) {
+		bom, text = utf8BOM, strings.TrimPrefix(text, utf8BOM)
+	}
+	finalEOL := strings.HasSuffix(text, "\n")
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+
+	remove := map[int]bool{}
+	for _, item := range items {
+		n := item.line - 1
+		if n < 0 || n >= len(lines) || strings.TrimSuffix(lines[n], "\r") != item.raw {
+			return nil, fmt.Errorf("todo no longer in expected location, or changed on disk... %s", item.Location())
+		}
+		remove[n] = true
+	}
+
+	kept := []string{}
+	for n, line := range lines {
+		if !remove[n] {
+			kept = append(kept, line)
+		}
+	}
+
+	if len(kept) == 0 {
+		return nil, nil
+	}
+	text = bom + strings.Join(kept, "\n")
+	if finalEOL {
+		text += "\n"
+	}
+	return []byte(text), nil
+}
+
+// Delete removes the item's line from its file. The line numbers of the
+// items after it in the file are stale.
+func Delete(i *Item) error {
+	if i.ReadOnly() {
+		return fmt.Errorf("item is read-only - cannot delete %s", i.Location())
+	}
+	return removeLines(i.file, []*Item{i})
+}
+
+// Shift moves the line numbers of the items of file after line by n, eg
+// by -1 for the items after a deleted line.
+func Shift(items []*Item, file string, line int, n int) {
+	for _, item := range items {
+		if item.file == file && item.line > line {
+			item.line += n
+		}
+	}
+}
+
+// Restore inserts the line of a deleted item back into its file, at the
+// item's line number. The line numbers of the items after it in the file
+// are stale.
+func Restore(i *Item) error {
+	if i.ReadOnly() {
+		return fmt.Errorf("item is read-only - cannot restore %s", i.Location())
+	}
+	content, err := readContent(i.file)
+	if err != nil {
+		return err
+	}
+
+	// the byte order mark and line endings (LF or CRLF) of the file are
+	// kept, as by fileInsert
+	text := string(content)
+	bom := ""
+	if strings.HasPrefix(text, utf8BOM) {
+		bom, text = utf8BOM, strings.TrimPrefix(text, utf8BOM)
+	}
+	finalEOL := text == "" || strings.HasSuffix(text, "\n")
+	lines := []string{}
+	if text != "" {
+		lines = strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	}
+	eol := "\n"
+	if len(lines) > 0 && strings.HasSuffix(lines[0], "\r") {
+		eol = "\r\n"
+	}
+	for n := range lines {
+		lines[n] = strings.TrimSuffix(lines[n], "\r")
+	}
+	n := i.line - 1
+	if n < 0 || n > len(lines) {
+		return fmt.Errorf("cannot restore %s: the file has %d lines", i.Location(), len(lines))
+	}
+
+	lines = append(lines[:n], append([]string{i.raw}, lines[n:]...)...)
+	text = bom + strings.Join(lines, eol)
+	if finalEOL {
+		text += eol
+	}
+	return writeContent(i.file, []byte(text))
+}
@@ -0,0 +1,96 @@
+package tuido
+
+import "os"
+
+// pending, when non-nil, holds the deferred file writes of a Batch.
+var pending *writeBatch
+
+// writeBatch is the updated content of each file written within a Batch,
+// and the lines of the items written, as they were before the batch.
+type writeBatch struct {
+	files map[string][]byte
+	order []string
+	prior map[string]map[*Item]string
+}
+
+// Batch runs fn, deferring the disk writes of item updates made within
+// it, so that each affected file is read and rewritten once, when fn
+// returns, rather than once per update. Batches do not nest: a Batch
+// within fn writes with the outer one.
+//
+// If a file can't be written, its items are reverted to their lines from
+// before the batch, and the first such error is returned, unless fn
+// itself returned an error.
+func Batch(fn func() error) error {
+	if pending != nil {
+		return fn()
+	}
+	pending = &writeBatch{files: map[string][]byte{}, prior: map[string]map[*Item]string{}}
+	b := pending
+	defer func() { pending = nil }()
+
+	err := fn()
+	pending = nil
+
+	for _, file := range b.order {
+		if werr := writeAtomic(file, b.files[file]); werr != nil {
+			for item, raw := range b.prior[file] {
+				item.raw = raw
+			}
+			if err == nil {
+				err = werr
+			}
+		}
+	}
+	return err
+}
+
+// replace applies item's new line to the batch's copy of its file.
+func (b *writeBatch) replace(i *Item, newRaw string) error {
+	content, ok := b.files[i.file]
+	if !ok {
+		var err error
+		if content, err = os.ReadFile(i.file); err != nil {
+			return err
+		}
+	}
+
+	content, err := replaceLine(i.file, content, i.line, i.raw, newRaw)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		b.order = append(b.order, i.file)
+		b.prior[i.file] = map[*Item]string{}
+	}
+	b.files[i.file] = content
+	if _, seen := b.prior[i.file][i]; !seen {
+		b.prior[i.file][i] = i.raw
+	}
+	return nil
+}
+
+// readContent returns the content of file, as changed so far within the
+// current Batch, if any.
+func readContent(file string) ([]byte, error) {
+	if pending != nil {
+		if content, ok := pending.files[file]; ok {
+			return content, nil
+		}
+	}
+	return os.ReadFile(file)
+}
+
+// writeContent replaces the content of file, or, within a Batch, the
+// batch's copy of it, which is written when the batch ends.
+func writeContent(file string, content []byte) error {
+	if pending == nil {
+		return writeAtomic(file, content)
+	}
+	if _, ok := pending.files[file]; !ok {
+		pending.order = append(pending.order, file)
+		pending.prior[file] = map[*Item]string{}
+	}
+	pending.files[file] = content
+	return nil
+}
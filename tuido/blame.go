@@ -0,0 +1,86 @@
+package tuido
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Blame reads, from git, when the line of each of items was committed,
+// for Age. Items of files outside a git repository, or which git does not
+// track, are left without a commit date. Lines not yet committed count as
+// committed today.
+func Blame(items []*Item) {
+	byFile := map[string][]*Item{}
+	for _, item := range items {
+		byFile[item.file] = append(byFile[item.file], item)
+	}
+	for file, fileItems := range byFile {
+		times := blameFile(file)
+		for _, item := range fileItems {
+			if t, ok := times[item.line]; ok {
+				t := t
+				item.committed = &t
+			}
+		}
+	}
+}
+
+// blameFile returns the author time of each line of file, by line number,
+// from `git blame`, or nil if git cannot blame the file.
+func blameFile(file string) map[int]time.Time {
+	cmd := exec.Command("git", "blame", "--line-porcelain", "--", filepath.Base(file))
+	cmd.Dir = filepath.Dir(file)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	times := map[int]time.Time{}
+	line, uncommitted := 0, false
+	scanner := NewLineScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case strings.HasPrefix(scanner.Text(), "\t") || len(fields) < 2:
+			// the line's content, or a field without a value
+		case len(fields[0]) == 40 && len(fields) >= 3:
+			// the header of a line: its commit, and original and final line
+			line, _ = strconv.Atoi(fields[2])
+			uncommitted = strings.Trim(fields[0], "0") == ""
+		case fields[0] == "author-time":
+			if uncommitted {
+				y, m, d := time.Now().Date()
+				times[line] = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+				break
+			}
+			if secs, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+				times[line] = time.Unix(secs, 0)
+			}
+		}
+	}
+	return times
+}
+
+// Committed returns when the item's line was committed to git, as read by
+// Blame, if it has been.
+func (i Item) Committed() *time.Time {
+	return i.committed
+}
+
+// Age returns the time since the item was created: since its creation
+// date (see Created), or else since its line was committed to git (see
+// Blame). It reports false if neither is known.
+func (i Item) Age() (time.Duration, bool) {
+	since := i.Created()
+	if since == nil {
+		since = i.committed
+	}
+	if since == nil {
+		return 0, false
+	}
+	return time.Since(*since), true
+}
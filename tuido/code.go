@@ -0,0 +1,134 @@
+package tuido
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// TodoComments are comment keywords, eg "TODO" and "FIXME", read as open
+// items in source code: `// TODO: frobnicate #backend`. None by default.
+var TodoComments = []string{}
+
+// code is the syntax of source code files, whose items are read from
+// line comments only. Item-like text elsewhere, eg in a string literal
+// `"[ ] not an item"`, is ignored.
+//
+// NB: block comments (/* ... */) are not read.
+type code struct {
+	// comment begins a line comment, eg "//".
+	comment string
+	// quotes are the characters which delimit string literals.
+	quotes string
+}
+
+// codeSyntaxes are the code syntaxes, by file extension.
+var codeSyntaxes = map[string]code{}
+
+func init() {
+	for _, lang := range []struct {
+		code
+		exts string
+	}{
+		{code{"//", "\"'`"}, "go js jsx mjs ts tsx"},
+		{code{"//", "\"'"}, "c h cc cpp hpp cs java kt kts scala swift dart php"},
+		{code{"//", "\""}, "rs"}, // 'a is a lifetime, not a quote
+		{code{"#", "\"'"}, "py rb sh bash zsh pl r yml yaml toml"},
+		{code{"--", "\"'"}, "lua sql hs elm"},
+	} {
+		for _, ext := range strings.Fields(lang.exts) {
+			codeSyntaxes["."+ext] = lang.code
+		}
+	}
+}
+
+// InCode reports whether the item is read from a line comment of a
+// source code file, among the lines of the code.
+func (i Item) InCode() bool {
+	_, ok := syntaxFor(i.file).(code)
+	return ok
+}
+
+// codeSyntax is the code syntax of file, by its extension.
+func codeSyntax(file string) syntax {
+	if c, ok := codeSyntaxes[strings.ToLower(filepath.Ext(file))]; ok {
+		return c
+	}
+	return nil
+}
+
+// commentText returns the text of raw's line comment, and where it begins, or
+// -1 if raw has no line comment outside of a string literal.
+func (c code) commentText(raw string) (string, int) {
+	var quote rune
+	escaped := false
+	for n, ch := range raw {
+		switch {
+		case quote != 0 && escaped:
+			escaped = false
+		case quote != 0 && ch == '\\' && quote != '`':
+			escaped = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case strings.ContainsRune(c.quotes, ch):
+			quote = ch
+		case strings.HasPrefix(raw[n:], c.comment):
+			start := n + len(c.comment)
+			body := strings.TrimLeft(raw[start:], " \t")
+			return body, len(raw) - len(body)
+		}
+	}
+	return "", -1
+}
+
+// todoComment returns the TodoComments keyword which begins text, with
+// any following colon and space, eg "TODO: ", or "".
+func todoComment(text string) string {
+	for _, keyword := range TodoComments {
+		if !strings.HasPrefix(text, keyword) {
+			continue
+		}
+		rest := text[len(keyword):]
+		rest = strings.TrimPrefix(rest, ":")
+		if rest != "" && rest[0] != ' ' {
+			continue // eg "TODOS", or "TODO:x"
+		}
+		body := strings.TrimLeft(rest, " ")
+		return text[:len(text)-len(body)]
+	}
+	return ""
+}
+
+func (c code) isItem(raw string) bool {
+	text, start := c.commentText(raw)
+	if start < 0 {
+		return false
+	}
+	return IsTuido(text) || todoComment(text) != ""
+}
+
+func (c code) decode(raw string) string {
+	text, _ := c.commentText(raw)
+	if keyword := todoComment(text); keyword != "" && !IsTuido(text) {
+		return Open.String() + " " + text[len(keyword):]
+	}
+	return text
+}
+
+// encode keeps the code and comment prefix of raw. Open items read from
+// a TodoComments keyword keep it; others are given a status marker.
+func (c code) encode(raw string, x string) string {
+	text, start := c.commentText(raw)
+	if start < 0 {
+		return c.comment + " " + x
+	}
+	if keyword := todoComment(text); keyword != "" && !IsTuido(text) && strToStatus(x) == Open {
+		return raw[:start] + keyword + strings.TrimPrefix(x[3:], " ")
+	}
+	return raw[:start] + x
+}
+
+func (c code) tags(text string) []Tag {
+	return Tags(text)
+}
@@ -0,0 +1,80 @@
+package tuido
+
+// Collection is a set of scanned items, for programs which build on
+// tuido: editors, bots, and dashboards. See Scan.
+//
+// Items are written through to their files as they are changed, eg by
+// SetStatus or SetText. Changes made within Batch are written once per
+// file, at its end.
+//
+// A Collection is not safe for concurrent use, nor for use alongside
+// another: the settings of the package, eg Markers and ReadOnly, apply to
+// every collection, and a batch is open for the whole package, so the
+// changes made to another collection's items during Batch are deferred
+// with it. Use one collection at a time, from one goroutine.
+type Collection struct {
+	// Items are the items of the scanned files, ordered by file path,
+	// then by line.
+	Items []*Item
+	// Files are the files scanned, in order.
+	Files []string
+
+	roots   []string
+	options ScanOptions
+}
+
+// Filter returns the items which match query, a filter expression, eg
+// `#work and due:<7d not is:done`. See ParseQuery. A malformed query is
+// an error.
+func (c *Collection) Filter(query string) ([]*Item, error) {
+	q := ParseQuery(query)
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+	matched := []*Item{}
+	for _, item := range c.Items {
+		if q.Match(item) {
+			matched = append(matched, item)
+		}
+	}
+	return matched, nil
+}
+
+// Find returns the item with the id, or nil if there is none. See ID.
+func (c *Collection) Find(id string) *Item {
+	for _, item := range c.Items {
+		if item.ID() == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// Add appends a new item with status s and body text to file, as Create
+// does, and adds it to the collection.
+func (c *Collection) Add(file string, s Status, text string) (*Item, error) {
+	item, err := Create(file, s, text)
+	if err != nil {
+		return nil, err
+	}
+	c.Items = append(c.Items, &item)
+	return &item, nil
+}
+
+// Batch runs fn, writing the files it changes once each, as the package
+// Batch does. It is the package's one batch, not the collection's own:
+// changes made within it to any item are deferred.
+func (c *Collection) Batch(fn func() error) error {
+	return Batch(fn)
+}
+
+// Reload scans the collection's roots again, with its options, for the
+// changes made outside of it.
+func (c *Collection) Reload() error {
+	fresh, err := Scan(c.roots, c.options)
+	if err != nil {
+		return err
+	}
+	*c = *fresh
+	return nil
+}
@@ -0,0 +1,28 @@
+package tuido
+
+import "fmt"
+
+// ConflictError reports that an item's line has changed on disk since it
+// was read, eg by an edit in another program, so that writing the item
+// would clobber the change.
+type ConflictError struct {
+	File string
+	Line int
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("todo no longer in expected location, or changed on disk... %s:%d", e.File, e.Line)
+}
+
+// forcing, while true, writes items over their lines whatever the lines
+// now hold. See Force.
+var forcing bool
+
+// Force runs fn, writing its item updates over the items' lines even if
+// the lines have changed on disk since they were read. Items whose line
+// no longer exists still fail, with a ConflictError.
+func Force(fn func() error) error {
+	forcing = true
+	defer func() { forcing = false }()
+	return fn()
+}
@@ -0,0 +1,41 @@
+package tuido
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Surrounding reads up to n lines on either side of the item's line from
+// its source file. Fewer lines are returned near the start or end of the
+// file. It is an error if the item's line has changed on disk.
+func (i Item) Surrounding(n int) (before, after []string, err error) {
+	f, err := os.Open(i.file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	found := false
+	scanner := NewLineScanner(f)
+	for line := 1; scanner.Scan() && line <= i.line+n; line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		switch {
+		case line < i.line-n:
+		case line < i.line:
+			before = append(before, text)
+		case line == i.line:
+			found = text == i.raw
+		default:
+			after = append(after, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if !found {
+		return nil, nil, fmt.Errorf("%s has changed since it was read - line %d no longer holds this item", i.file, i.line)
+	}
+	return before, after, nil
+}
@@ -0,0 +1,29 @@
+package tuido
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+)
+
+// CSV renders items as CSV, with a header row, one row per item:
+// id, file, line, status, due, text, and tags (space separated).
+func CSV(items []*Item) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write([]string{"id", "file", "line", "status", "due", "text", "tags"})
+	for _, i := range items {
+		tags := []string{}
+		for _, t := range i.Tags() {
+			tags = append(tags, t.String())
+		}
+		w.Write([]string{
+			i.ID(), i.file, strconv.Itoa(i.line), string(i.Satus()), i.dueString(), i.Text(), strings.Join(tags, " "),
+		})
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
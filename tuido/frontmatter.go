@@ -0,0 +1,78 @@
+package tuido
+
+import (
+	"strings"
+)
+
+// FrontMatter reads todos listed under a `todos:` key of a markdown
+// file's YAML front matter, eg:
+//
+//	---
+//	title: weekend
+//	todos:
+//	  - fix the gutter
+//	  - "[x] mow the lawn"
+//	---
+//
+// Entries without a status box are read as open items.
+//
+// NB: front matter items are read-only. Entries which are already
+// in [x]it form, unquoted (`- [ ] fix the gutter`), are left to the
+// regular line parser, and so can be written back as usual.
+type FrontMatter struct {
+	lines   int
+	open    bool
+	closed  bool
+	inTodos bool
+}
+
+// Scan consumes the next line of file, returning a front matter
+// item if the line is one.
+func (fm *FrontMatter) Scan(file string, raw string) *Item {
+	fm.lines++
+	line := strings.TrimRight(raw, " \t\r")
+
+	if fm.closed {
+		return nil
+	}
+	if !fm.open {
+		if fm.lines == 1 && line == "---" {
+			fm.open = true
+		} else {
+			fm.closed = true
+		}
+		return nil
+	}
+	if line == "---" || line == "..." {
+		fm.closed = true
+		return nil
+	}
+
+	// top level keys begin or end the todos list
+	if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+		fm.inTodos = line == "todos:"
+		return nil
+	}
+	if !fm.inTodos || IsTuido(raw) {
+		return nil
+	}
+
+	entry := strings.TrimSpace(line)
+	if !strings.HasPrefix(entry, "- ") {
+		return nil
+	}
+	entry = strings.Trim(strings.TrimSpace(entry[2:]), `"'`)
+	if entry == "" {
+		return nil
+	}
+	if !IsTuido(entry) {
+		entry = Open.String() + " " + entry
+	}
+
+	return &Item{
+		file:     file,
+		line:     fm.lines,
+		raw:      entry,
+		readOnly: true,
+	}
+}
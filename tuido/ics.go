@@ -0,0 +1,119 @@
+package tuido
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsStatus are the iCalendar VTODO statuses of the item statuses.
+var icsStatus = map[Status]string{
+	Open:     "NEEDS-ACTION",
+	Ongoing:  "IN-PROCESS",
+	Review:   "IN-PROCESS",
+	Checked:  "COMPLETED",
+	Obsolete: "CANCELLED",
+}
+
+// icsStatusOf returns the VTODO status of s. Custom statuses are
+// completed, if Done, or else still needed.
+func icsStatusOf(s Status) string {
+	if status, ok := icsStatus[s]; ok {
+		return status
+	}
+	if s.Finished() {
+		return "COMPLETED"
+	}
+	return "NEEDS-ACTION"
+}
+
+// ICS renders the items which have a due date as an iCalendar (RFC 5545)
+// calendar, with a VTODO per item, due on its due date, and an alarm at
+// 9am of that day. Items without a due date are left out. Each VTODO's
+// UID is the item's id, so that calendar apps update, rather than
+// duplicate, the items of a re-imported export.
+func ICS(items []*Item) ([]byte, error) {
+	stamp := icsTime(time.Now())
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//tuido//tuido//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	for _, i := range items {
+		due := i.Due()
+		if due == nil {
+			continue
+		}
+		lines = append(lines,
+			"BEGIN:VTODO",
+			"UID:"+i.ID()+"@tuido",
+			"DTSTAMP:"+stamp,
+			"DUE;VALUE=DATE:"+due.Format("20060102"),
+			"SUMMARY:"+icsEscape(i.Text()),
+			"DESCRIPTION:"+icsEscape(i.Location()),
+			"STATUS:"+icsStatusOf(i.Satus()),
+		)
+		if p := i.Priority(); p > 0 {
+			lines = append(lines, "PRIORITY:"+strconv.Itoa(icsPriority(p)))
+		}
+		if completed := i.Completed(); completed != nil {
+			lines = append(lines, "COMPLETED:"+icsTime(*completed))
+		}
+		lines = append(lines,
+			"BEGIN:VALARM",
+			"ACTION:DISPLAY",
+			"DESCRIPTION:"+icsEscape(i.Text()),
+			"TRIGGER;RELATED=END:PT9H",
+			"END:VALARM",
+			"END:VTODO",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(icsFold(line))
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// icsPriority maps an [x]it! priority to an iCalendar priority, where 1
+// is the highest: ! is 5 (medium), !! is 3, and !!! or more is 1.
+func icsPriority(p int) int {
+	if p >= 3 {
+		return 1
+	}
+	return 7 - 2*p
+}
+
+// icsEscape escapes the characters which are special in iCalendar text
+// values.
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icsTime formats t as an iCalendar date-time in UTC, eg 20220410T000000Z.
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsFold splits line into lines of at most 75 octets, each continuation
+// starting with a space, without splitting a UTF-8 character.
+func icsFold(line string) string {
+	parts := []string{}
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		parts = append(parts, line[:cut])
+		line = line[cut:]
+		limit = 74 // for the leading space
+	}
+	parts = append(parts, line)
+	return strings.Join(parts, "\r\n ")
+}
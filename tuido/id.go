@@ -0,0 +1,45 @@
+package tuido
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+)
+
+// idSigil prefixes inline item ids, eg "[ ] call the plumber §a1b2c3d4"
+const idSigil = "§"
+
+// idAnnotation also prefixes inline item ids, for keyboards without a §,
+// eg "[ ] call the plumber id:a1b2c3d4"
+const idAnnotation = "id:"
+
+// ID returns a stable identifier for the item, for reference from
+// scripts and sidecar data.
+//
+// An inline id written into the item (`§a1b2c3d4`, or `id:a1b2c3d4`) is
+// used when present.
+// Otherwise the id is a short hash of the item's file and its text, less
+// any tags and `spent:` time. Hashed ids survive the item moving within
+// its file, status changes, tag changes, and time tracking, but not edits
+// to its text.
+//
+// NB: identical items in the same file share a hashed id. Give one of
+// them an inline id to tell them apart.
+func (i Item) ID() string {
+	words := []string{}
+
+	for _, token := range strings.Split(i.Text(), " ") {
+		for _, prefix := range []string{idSigil, idAnnotation} {
+			if strings.HasPrefix(token, prefix) && len(token) > len(prefix) {
+				return token[len(prefix):]
+			}
+		}
+		if IsTagToken(token) || strings.HasPrefix(token, spentAnnotation) {
+			continue
+		}
+		words = append(words, token)
+	}
+
+	sum := sha1.Sum([]byte(i.file + "\x00" + strings.Join(words, " ")))
+	return hex.EncodeToString(sum[:4])
+}
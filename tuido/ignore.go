@@ -0,0 +1,140 @@
+package tuido
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultSkipDirs are directories which are not descended into while
+// scanning for items, unless ScanOptions name others.
+var DefaultSkipDirs = []string{".git", "node_modules", "vendor", ".idea"}
+
+// ignoreList is the set of patterns read from the `.gitignore` files of a
+// scan, and from a `.tuidoignore` file at its root.
+//
+// Patterns follow `.gitignore` semantics: a pattern without a `/` matches
+// a file or directory of that name at any depth below the file it was
+// read from, and one with a `/` matches paths relative to it. A trailing
+// `/` matches directories only, `**` matches any number of directories,
+// and a leading `!` re-includes paths matched by an earlier pattern.
+// Blank lines and lines beginning with `#` are skipped.
+type ignoreList struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	// base is the directory of the ignore file the rule was read from
+	base     string
+	pattern  string
+	anchored bool
+	dirOnly  bool
+	negate   bool
+}
+
+// loadIgnoreList reads the `.gitignore` and then the `.tuidoignore` file at
+// root, so that `.tuidoignore` patterns take precedence.
+func loadIgnoreList(root string) *ignoreList {
+	l := &ignoreList{}
+	l.load(root, ".gitignore")
+	l.load(root, ".tuidoignore")
+	return l
+}
+
+// load appends the patterns of the ignore file name in dir, if it exists.
+func (l *ignoreList) load(dir, name string) {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		p := strings.TrimSpace(scanner.Text())
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+		r := ignoreRule{base: dir}
+		if strings.HasPrefix(p, "!") {
+			r.negate = true
+			p = p[1:]
+		}
+		if strings.HasSuffix(p, "/") {
+			r.dirOnly = true
+			p = strings.TrimRight(p, "/")
+		}
+		r.anchored = strings.Contains(p, "/")
+		r.pattern = strings.TrimPrefix(p, "/")
+		if r.pattern != "" {
+			l.rules = append(l.rules, r)
+		}
+	}
+}
+
+// ignores reports whether path is matched by the list. The last matching
+// pattern decides, so a later `!pattern` can re-include a path.
+func (l *ignoreList) ignores(p string, isDir bool) bool {
+	ignored := false
+	for _, r := range l.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		rel, err := filepath.Rel(r.base, p)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		var matched bool
+		if r.anchored {
+			matched = matchGlob(r.pattern, rel)
+		} else {
+			matched, _ = path.Match(r.pattern, path.Base(rel))
+		}
+		if matched {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// matchGlob matches a slash separated path against a glob pattern, in
+// which a `**` element matches any number of path elements.
+func matchGlob(pattern, rel string) bool {
+	return matchElems(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchElems(pattern, elems []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(elems); i++ {
+				if matchElems(pattern[1:], elems[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(elems) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], elems[0]); !ok {
+			return false
+		}
+		pattern, elems = pattern[1:], elems[1:]
+	}
+	return len(elems) == 0
+}
+
+// SkipsDir reports whether the directory named name is one of skipDirs,
+// and so is skipped while scanning.
+func SkipsDir(name string, skipDirs []string) bool {
+	for _, d := range skipDirs {
+		if name == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package tuido
+
+import (
+	"encoding/json"
+)
+
+// jsonItem is the serialized form of an Item.
+type jsonItem struct {
+	ID     string   `json:"id"`
+	File   string   `json:"file"`
+	Line   int      `json:"line"`
+	Status string   `json:"status"`
+	Due    string   `json:"due,omitempty"`
+	Text   string   `json:"text"`
+	Tags   []string `json:"tags"`
+}
+
+// MarshalJSON serializes the item's location, status, due date, text,
+// and tags.
+func (i Item) MarshalJSON() ([]byte, error) {
+	tags := []string{}
+	for _, t := range i.Tags() {
+		tags = append(tags, t.String())
+	}
+
+	return json.Marshal(jsonItem{
+		ID:     i.ID(),
+		File:   i.file,
+		Line:   i.line,
+		Status: string(i.Satus()),
+		Due:    i.dueString(),
+		Text:   i.Text(),
+		Tags:   tags,
+	})
+}
+
+// dueString is the item's due date as YYYY-MM-DD, or "" if it has none.
+func (i Item) dueString() string {
+	if due := i.Due(); due != nil {
+		return due.Format("2006-01-02")
+	}
+	return ""
+}
+
+// JSON renders items as an indented JSON array.
+func JSON(items []*Item) ([]byte, error) {
+	if items == nil {
+		items = []*Item{}
+	}
+	return json.MarshalIndent(items, "", "  ")
+}
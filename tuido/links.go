@@ -0,0 +1,30 @@
+package tuido
+
+import (
+	"regexp"
+	"strings"
+)
+
+// urlPattern matches web links, up to the next space.
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// URLs returns the web links in the item's text, in order. Punctuation
+// ending a sentence is not part of a link, nor is a closing parenthesis
+// which the link didn't open, eg in "(see https://example.com)".
+func (i Item) URLs() []string {
+	urls := []string{}
+	for _, url := range urlPattern.FindAllString(i.Text(), -1) {
+		for {
+			trimmed := strings.TrimRight(url, ".,;:!?'")
+			if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+				trimmed = trimmed[:len(trimmed)-1]
+			}
+			if trimmed == url {
+				break
+			}
+			url = trimmed
+		}
+		urls = append(urls, url)
+	}
+	return urls
+}
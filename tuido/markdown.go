@@ -0,0 +1,19 @@
+package tuido
+
+import (
+	"strings"
+)
+
+// Markdown returns the item as a markdown task list entry, eg "- [x] this one".
+func (i Item) Markdown() string {
+	return "- " + i.Satus().String() + " " + i.Text()
+}
+
+// Markdown renders items as a markdown task list, one item per line.
+func Markdown(items []*Item) string {
+	lines := []string{}
+	for _, item := range items {
+		lines = append(lines, item.Markdown())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,74 @@
+package tuido
+
+import (
+	"strings"
+)
+
+// Indent returns the width of the item's leading whitespace, counting
+// tabs as four spaces.
+func (i Item) Indent() int {
+	width := 0
+	for _, ch := range i.raw {
+		switch ch {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// Parent returns the item that this item is nested under, or nil.
+func (i Item) Parent() *Item {
+	return i.parent
+}
+
+// Children returns the items nested directly under this item.
+func (i Item) Children() []*Item {
+	return i.children
+}
+
+// Progress returns the number of the item's direct subtasks which are
+// finished (checked, obsolete, or of a Done custom status), and the
+// total number of subtasks.
+func (i Item) Progress() (int, int) {
+	finished := 0
+	for _, c := range i.children {
+		if c.Satus().Finished() {
+			finished++
+		}
+	}
+	return finished, len(i.children)
+}
+
+// Nest links the items of a single file, given in line order, into
+// parent/subtask relationships by their indentation. An item is a
+// subtask of the nearest preceding item with a smaller indent, eg:
+//
+//	[ ] pack for the beach
+//	    [x] towels
+//	    [ ] sunscreen
+func Nest(items []*Item) {
+	stack := []*Item{}
+
+	for _, item := range items {
+		item.parent = nil
+		item.children = nil
+	}
+
+	for _, item := range items {
+		for len(stack) > 0 && stack[len(stack)-1].Indent() >= item.Indent() {
+			stack = stack[:len(stack)-1]
+		}
+		// code comment items are not nested: their indentation is the code's
+		if _, inCode := syntaxFor(item.file).(code); len(stack) > 0 && !inCode && !strings.Contains(item.raw, "// ") {
+			parent := stack[len(stack)-1]
+			item.parent = parent
+			parent.children = append(parent.children, item)
+		}
+		stack = append(stack, item)
+	}
+}
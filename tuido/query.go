@@ -0,0 +1,361 @@
+package tuido
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Query is a parsed filter expression, such as
+// `#work and (#urgent or due:<7d) not is:ongoing`. See ParseQuery.
+type Query struct {
+	root  queryNode
+	words []string
+	err   error
+}
+
+// queryNode is a term of a Query.
+type queryNode interface {
+	match(i *Item) bool
+}
+
+type (
+	andNode []queryNode
+	orNode  []queryNode
+	notNode struct{ queryNode }
+	tagNode struct {
+		name   string
+		prefix bool
+	}
+	wordNode   string
+	statusNode func(i *Item) bool
+	dueNode    struct {
+		op   string
+		date *time.Time
+	}
+)
+
+func (n andNode) match(i *Item) bool {
+	for _, term := range n {
+		if !term.match(i) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n orNode) match(i *Item) bool {
+	for _, term := range n {
+		if term.match(i) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n notNode) match(i *Item) bool {
+	return !n.queryNode.match(i)
+}
+
+func (n tagNode) match(i *Item) bool {
+	for _, t := range i.Tags() {
+		if t.name == n.name || n.prefix && strings.HasPrefix(t.name, n.name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n wordNode) match(i *Item) bool {
+	words := []string{}
+	for _, word := range strings.Split(i.Text(), " ") {
+		if !IsTagToken(word) {
+			words = append(words, word)
+		}
+	}
+	return strings.Contains(strings.ToLower(strings.Join(words, " ")), string(n))
+}
+
+func (n statusNode) match(i *Item) bool {
+	return n(i)
+}
+
+func (n dueNode) match(i *Item) bool {
+	due := i.Due()
+	if n.date == nil {
+		return due == nil
+	}
+	if due == nil {
+		return false
+	}
+	switch n.op {
+	case "<":
+		return due.Before(*n.date)
+	case "<=":
+		return !due.After(*n.date)
+	case ">":
+		return due.After(*n.date)
+	case ">=":
+		return !due.Before(*n.date)
+	}
+	return due.Equal(*n.date)
+}
+
+// IsQuery reports whether s is written as a filter expression, rather
+// than as a plain list of tags and words: whether it has an `and`, `or`,
+// or `not` operator, parentheses, or an `is:` or `due:` predicate.
+func IsQuery(s string) bool {
+	for _, token := range queryTokens(s) {
+		t := strings.ToLower(strings.TrimLeft(token, "!-"))
+		if t == "and" || t == "or" || t == "not" || t == "(" || t == ")" ||
+			strings.HasPrefix(t, "is:") || strings.HasPrefix(t, "due:") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseQuery parses a filter expression. Its terms are:
+//
+//	#tag          items with the tag. The last term, if a tag not yet
+//	              followed by a space, matches tags beginning with it
+//	word          items whose text (tags aside) contains it, ignoring case
+//	is:status     items of a status: open, ongoing, review, checked,
+//	              obsolete, or a custom status, or pending, done,
+//	              overdue, or snoozed
+//	due:<7d       items due before a date: YYYY-MM-DD, today, or a period
+//	              from today (eg 3d, 2w, 1M, 1y), after one of <, <=, >,
+//	              >=, or = (the default). due:none is items without one
+//
+// Terms are combined with `and` (or side by side), `or`, and `not` (or a
+// leading `!` or `-`), in that order of precedence, and grouped with
+// parentheses: `#work (#urgent or due:<7d) not is:ongoing`. Expressions
+// still being typed are read as far as they go: a trailing operator, or
+// an unclosed parenthesis, is ignored, as is one which closes nothing. Unknown statuses and dates are
+// reported by Err, and the query then matches nothing.
+func ParseQuery(s string) Query {
+	// parentheses which close nothing are dropped
+	tokens, depth := []string{}, 0
+	for _, token := range queryTokens(s) {
+		switch token {
+		case "(":
+			depth++
+		case ")":
+			if depth == 0 {
+				continue
+			}
+			depth--
+		}
+		tokens = append(tokens, token)
+	}
+
+	p := queryParser{tokens: tokens, typing: !strings.HasSuffix(s, " ")}
+	var root queryNode = andNode{}
+	if term := p.or(); term != nil {
+		root = term
+	}
+	return Query{root: root, words: p.words, err: p.err}
+}
+
+// Match reports whether the item matches the query.
+func (q Query) Match(i *Item) bool {
+	return q.err == nil && q.root.match(i)
+}
+
+// Err returns the first malformed term of the query, if any.
+func (q Query) Err() error {
+	return q.err
+}
+
+// Words returns the plain words of the query, lower cased, other than
+// those it negates, eg to highlight them in matched items.
+func (q Query) Words() []string {
+	return q.words
+}
+
+// queryTokens splits s at spaces and parentheses, keeping parentheses as
+// tokens of their own.
+func queryTokens(s string) []string {
+	s = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s)
+	return strings.Fields(s)
+}
+
+type queryParser struct {
+	tokens []string
+	pos    int
+	// typing is set while the last token is not followed by a space
+	typing  bool
+	negated int
+	words   []string
+	err     error
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) lower() string {
+	return strings.ToLower(p.peek())
+}
+
+func (p *queryParser) or() queryNode {
+	terms := orNode{}
+	for {
+		if term := p.and(); term != nil {
+			terms = append(terms, term)
+		}
+		if p.lower() != "or" {
+			break
+		}
+		p.pos++
+	}
+	switch len(terms) {
+	case 0:
+		return nil
+	case 1:
+		return terms[0]
+	}
+	return terms
+}
+
+func (p *queryParser) and() queryNode {
+	terms := andNode{}
+	for {
+		switch p.lower() {
+		case "", "or", ")":
+			if len(terms) == 1 {
+				return terms[0]
+			}
+			if len(terms) == 0 {
+				return nil
+			}
+			return terms
+		case "and":
+			p.pos++
+			continue
+		}
+		if term := p.unary(); term != nil {
+			terms = append(terms, term)
+		}
+	}
+}
+
+func (p *queryParser) unary() queryNode {
+	token := p.peek()
+	stripped := strings.TrimLeft(token, "!-")
+	switch {
+	case token == "":
+		return nil
+	case strings.ToLower(token) == "not" || stripped == "":
+		// `not`, or a lone `!` or `-`, eg before a parenthesis
+		p.pos++
+		return p.negate()
+	case stripped != token:
+		p.tokens[p.pos] = stripped
+		return p.negate()
+	case token == "(":
+		p.pos++
+		term := p.or()
+		if p.peek() == ")" {
+			p.pos++
+		}
+		return term
+	}
+	p.pos++
+	return p.term(token, p.pos == len(p.tokens))
+}
+
+// negate returns the negation of the next unary term.
+func (p *queryParser) negate() queryNode {
+	p.negated++
+	term := p.unary()
+	p.negated--
+	if term == nil {
+		return nil
+	}
+	return notNode{term}
+}
+
+// term parses a tag, a predicate, or a word.
+func (p *queryParser) term(token string, last bool) queryNode {
+	lower := strings.ToLower(token)
+	switch {
+	case token == TagSigil:
+		return nil
+	case IsTagToken(token):
+		return tagNode{Tags(token)[0].Name(), last && p.typing}
+	case strings.HasPrefix(lower, "is:"):
+		term, err := statusTerm(lower[len("is:"):])
+		p.fail(err)
+		return term
+	case strings.HasPrefix(lower, "due:"):
+		term, err := dueTerm(token[len("due:"):])
+		p.fail(err)
+		return term
+	}
+	if p.negated%2 == 0 {
+		p.words = append(p.words, lower)
+	}
+	return wordNode(lower)
+}
+
+func (p *queryParser) fail(err error) {
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+}
+
+// statusTerm parses the status of an `is:` predicate.
+func statusTerm(s string) (queryNode, error) {
+	pending := func(i *Item) bool {
+		return i.Satus().Pending()
+	}
+	switch s {
+	case "pending":
+		return statusNode(pending), nil
+	case "done":
+		return statusNode(func(i *Item) bool { return !pending(i) }), nil
+	case "overdue":
+		return statusNode(func(i *Item) bool { return pending(i) && i.Overdue() }), nil
+	case "snoozed":
+		return statusNode(func(i *Item) bool { return !i.Active() }), nil
+	}
+	status, err := ParseStatus(s)
+	if err != nil {
+		return nil, fmt.Errorf("unknown is:%s - use a status, or pending, done, overdue, or snoozed", s)
+	}
+	return statusNode(func(i *Item) bool { return i.Satus() == status }), nil
+}
+
+// dueTerm parses the comparison and date of a `due:` predicate.
+func dueTerm(s string) (queryNode, error) {
+	op := ""
+	for _, o := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(s, o) {
+			op, s = o, s[len(o):]
+			break
+		}
+	}
+	if s == "none" && (op == "" || op == "=") {
+		return dueNode{}, nil
+	}
+
+	y, m, d := time.Now().Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	switch {
+	case s == "today":
+	case snoozeShorthand.MatchString(s):
+		y, m, d := toDate(s).Date()
+		date = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	default:
+		parsed, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read due:%s - expected eg due:<7d, due:today, or due:>=2022-06-01", s)
+		}
+		date = parsed
+	}
+	return dueNode{op, &date}, nil
+}
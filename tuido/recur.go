@@ -0,0 +1,147 @@
+package tuido
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// everyInterval matches a recurrence interval, eg "week", "2d", or
+// "3months".
+var everyInterval = regexp.MustCompile(`^([0-9]*)(d|days?|w|weeks?|m|months?|y|years?|weekdays?)$`)
+
+// recurDropped are the tags which are not carried over to the next
+// occurrence of a recurring item.
+var recurDropped = map[string]bool{"completed": true, "created": true, "started": true, "lastDone": true, "zzz": true}
+
+// Every returns the recurrence interval of the item, from an `every:week`
+// annotation or an `#every=week` tag, or "" if the item does not recur.
+func (i Item) Every() string {
+	for _, t := range i.Tags() {
+		if t.name == "every" {
+			return t.value
+		}
+	}
+	for _, word := range strings.Split(i.Text(), " ") {
+		if strings.HasPrefix(word, "every:") {
+			return word[len("every:"):]
+		}
+	}
+	return ""
+}
+
+// nextOccurrence returns the date one recurrence interval after from. An
+// interval is an optional count and a unit: d(ay), w(eek), m(onth),
+// y(ear), or weekday, eg "week", "2d", or "3months". Months and years are
+// calendar months and years: a month after January 31st is the last day
+// of February.
+func nextOccurrence(from time.Time, every string) (time.Time, error) {
+	m := everyInterval.FindStringSubmatch(strings.ToLower(every))
+	if m == nil {
+		return time.Time{}, fmt.Errorf("cannot read recurrence every:%s - expected eg every:week or every:2d", every)
+	}
+	n := 1
+	if m[1] != "" {
+		n, _ = strconv.Atoi(m[1])
+	}
+	if n < 1 {
+		return time.Time{}, fmt.Errorf("cannot read recurrence every:%s - the interval must be at least 1", every)
+	}
+
+	switch m[2][0] {
+	case 'd':
+		return from.AddDate(0, 0, n), nil
+	case 'm':
+		return addMonths(from, n), nil
+	case 'y':
+		return addMonths(from, 12*n), nil
+	}
+	if !strings.HasPrefix(m[2], "weekday") {
+		return from.AddDate(0, 0, 7*n), nil
+	}
+	next := from
+	for n > 0 {
+		next = next.AddDate(0, 0, 1)
+		if next.Weekday() != time.Saturday && next.Weekday() != time.Sunday {
+			n--
+		}
+	}
+	return next, nil
+}
+
+// Recur appends the next occurrence of a recurring item to its file, as
+// a fresh open item with the next due date, and returns it. The next due
+// date is one interval after the item's due date, or after today if it
+// has none. Items which do not recur return nil.
+//
+// The item's completion and start stamps are not copied.
+func (i *Item) Recur() (*Item, error) {
+	every := i.Every()
+	if every == "" {
+		return nil, nil
+	}
+	if i.ReadOnly() {
+		return nil, fmt.Errorf("item is read-only - cannot recur %s", i.Location())
+	}
+
+	y, mo, d := time.Now().Date()
+	from := time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+	if due := i.Due(); due != nil {
+		from = *due
+	}
+	next, err := nextOccurrence(from, every)
+	if err != nil {
+		return nil, err
+	}
+	date := next.Format("2006-01-02")
+
+	words := []string{}
+	hasDue := false
+	for _, word := range strings.Split(i.Text(), " ") {
+		if IsTagToken(word) {
+			t := newTag(word)
+			if recurDropped[t.name] {
+				continue
+			}
+			if t.name == "due" {
+				word = Tag{"due", date}.Token()
+				hasDue = true
+			}
+		}
+		words = append(words, word)
+	}
+	text := stampCreated(strings.Join(words, " "))
+	if marker := i.DueMarker(); marker != "" {
+		text = strings.Replace(text, marker, "-> "+date, 1)
+		hasDue = true
+	}
+	if !hasDue {
+		text += " " + Tag{"due", date}.Token()
+	}
+
+	// the copy of an [x]it item keeps its indentation, or bullet
+	prior := ""
+	if _, ok := syntaxFor(i.file).(xit); ok {
+		prior = i.raw
+	}
+	raw := syntaxFor(i.file).encode(prior, Open.String()+" "+text)
+	line, err := appendLine(i.file, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &Item{file: i.file, line: line, raw: raw}, nil
+}
+
+// addMonths returns the date n months after t, on the same day of the
+// month, or on the month's last day if it is shorter.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,300 @@
+package tuido
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultExtensions are the file extensions parsed for items, unless
+// ScanOptions name others.
+var DefaultExtensions = []string{"xit", "md", "txt", "todo"}
+
+// ScanOptions configure Files and Scan. The zero value parses the
+// DefaultExtensions, and skips the DefaultSkipDirs, at any depth.
+type ScanOptions struct {
+	// Extensions are the file extensions parsed for items.
+	Extensions []string
+	// SkipDirs are the names of directories which are not descended
+	// into. Nil skips the DefaultSkipDirs, and an empty list none.
+	SkipDirs []string
+	// MaxDepth is the number of directory levels scanned, counting the
+	// scan root as the first. 0 is no limit.
+	MaxDepth int
+
+	// Exclude, if set, leaves out the files and directories it reports.
+	Exclude func(path string) bool
+	// DirExtensions, if set, returns the extensions parsed in a directory
+	// and below it, in place of those above, or none to keep them.
+	DirExtensions func(dir string) []string
+	// Skipped, if set, is told of each file or directory which is not
+	// read, or not read in full, and why.
+	Skipped func(path, reason string)
+}
+
+func (o ScanOptions) extensions() []string {
+	if o.Extensions == nil {
+		return DefaultExtensions
+	}
+	return o.Extensions
+}
+
+func (o ScanOptions) skipDirs() []string {
+	if o.SkipDirs == nil {
+		return DefaultSkipDirs
+	}
+	return o.SkipDirs
+}
+
+func (o ScanOptions) skip(path string, format string, a ...interface{}) {
+	if o.Skipped != nil {
+		o.Skipped(path, fmt.Sprintf(format, a...))
+	}
+}
+
+// Files lists the files to parse under dir: those with one of the
+// extensions, outside of skipped directories, and not ignored by the
+// `.gitignore` files of dir and below, or by a `.tuidoignore` file in
+// dir. Symlinks are followed, each file and directory being listed or
+// walked once, and only regular files are listed.
+func Files(dir string, o ScanOptions) []string {
+	extensions := o.extensions()
+	files := []string{}
+	ignore := loadIgnoreList(dir)
+
+	// resolved paths of the directories and files already visited, so
+	// that symlinks to them are not walked or parsed again
+	seen := map[string]bool{}
+	realDirs := map[string]string{}
+
+	// depth is the directory level of root below dir, which is level 0
+	var walk func(root string, extensions []string, depth int)
+	walk = func(root string, extensions []string, depth int) {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// an unreadable directory is skipped, rather than ending the walk
+				o.skip(path, "unreadable: %s", err)
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if o.Exclude != nil && o.Exclude(path) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if path != root && (ignore.ignores(path, d.IsDir()) || d.IsDir() && SkipsDir(d.Name(), o.skipDirs())) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			level := depth
+			if rel, err := filepath.Rel(root, path); err == nil && rel != "." {
+				level += strings.Count(rel, string(filepath.Separator)) + 1
+			}
+			tooDeep := o.MaxDepth > 0 && level >= o.MaxDepth
+			if d.IsDir() && path != root && tooDeep {
+				return fs.SkipDir
+			}
+
+			// symlinks are followed, to directories and files not yet seen
+			if d.Type()&fs.ModeSymlink != 0 {
+				real, err := filepath.EvalSymlinks(path)
+				if err != nil {
+					o.skip(path, "broken symlink: %s", err)
+					return nil
+				}
+				info, err := os.Stat(real)
+				if err != nil {
+					o.skip(path, "unreadable: %s", err)
+					return nil
+				}
+				if info.IsDir() {
+					if !SkipsDir(d.Name(), o.skipDirs()) && !tooDeep {
+						walk(real, extensions, level)
+					}
+				} else if info.Mode().IsRegular() && HasExtension(real, extensions) && !seen[real] {
+					// listed by the real path, so that writes replace the
+					// file rather than the link
+					seen[real] = true
+					files = append(files, real)
+				}
+				return nil
+			}
+
+			// directories may parse extensions of their own
+			if d.IsDir() {
+				real, err := filepath.EvalSymlinks(path)
+				if err != nil {
+					real = path
+				}
+				if seen[real] {
+					return fs.SkipDir
+				}
+				seen[real] = true
+				realDirs[path] = real
+
+				// nested .gitignore files apply below their directory
+				if path != dir {
+					ignore.load(path, ".gitignore")
+				}
+
+				if o.DirExtensions != nil {
+					if dirExtensions := o.DirExtensions(path); len(dirExtensions) != 0 {
+						extensions = dirExtensions
+					}
+				}
+				return nil
+			}
+
+			// only regular files are read: a fifo or device would block
+			if d.Type().IsRegular() && HasExtension(path, extensions) {
+				real := filepath.Join(realDirs[filepath.Dir(path)], d.Name())
+				if !seen[real] {
+					seen[real] = true
+					files = append(files, path)
+				}
+			}
+			return nil
+		})
+	}
+	walk(dir, extensions, 0)
+	return files
+}
+
+// SkipError is the reason a file, or the rest of a file, was not read.
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return e.Reason
+}
+
+// ReadItems parses the items from r, which holds the contents of file,
+// and nests them by indentation. A file which is UTF-16 encoded, or
+// binary, has no items, and one whose read fails part way has those read
+// before the failure. Either is reported by a *SkipError.
+func ReadItems(file string, r io.Reader) ([]*Item, error) {
+	items := []*Item{}
+
+	frontMatter := FrontMatter{}
+	readFrontMatter := strings.HasSuffix(strings.ToLower(file), ".md")
+
+	scanner := NewLineScanner(r)
+	line := 1
+	for scanner.Scan() {
+		if line == 1 && IsUTF16(scanner.Text()) {
+			return []*Item{}, &SkipError{"UTF-16 encoded - only UTF-8 files are read"}
+		}
+		if strings.IndexByte(scanner.Text(), 0) >= 0 {
+			return []*Item{}, &SkipError{"binary file"}
+		}
+		if readFrontMatter {
+			if item := frontMatter.Scan(file, scanner.Text()); item != nil {
+				items = append(items, item)
+				line++
+				continue
+			}
+		}
+		if item := Parse(file, line, scanner.Text()); item != nil {
+			items = append(items, item)
+		}
+		line++
+	}
+
+	Nest(items)
+	if err := scanner.Err(); err != nil {
+		return items, &SkipError{fmt.Sprintf("read stopped at line %d: %s", line, err)}
+	}
+	return items, nil
+}
+
+// ReadFile parses the items of file, as ReadItems does. The items of a
+// file which cannot be written are read-only.
+func ReadFile(file string) ([]*Item, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return []*Item{}, err
+	}
+	defer f.Close()
+
+	items, err := ReadItems(file, f)
+	if len(items) > 0 && !Writable(file) {
+		for _, item := range items {
+			item.SetReadOnly()
+		}
+	}
+	return items, err
+}
+
+// Writable reports whether file can be opened for writing. It is opened,
+// but not written to.
+func Writable(file string) bool {
+	f, err := os.OpenFile(file, os.O_WRONLY, 0)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// Scan parses the items of roots, each a directory, whose Files are
+// parsed, or a file. Items are ordered by file path, then by line. Files
+// which cannot be read are passed to o.Skipped, and left out, but a root
+// which does not exist is an error.
+func Scan(roots []string, o ScanOptions) (*Collection, error) {
+	files := []string{}
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			files = append(files, Files(root, o)...)
+		} else {
+			files = append(files, root)
+		}
+	}
+	sort.Strings(files)
+
+	c := &Collection{Files: files, roots: roots, options: o}
+	for _, file := range files {
+		items, err := ReadFile(file)
+		var skip *SkipError
+		if errors.As(err, &skip) {
+			o.skip(file, "%s", skip.Reason)
+		} else if err != nil {
+			o.skip(file, "unreadable: %s", err)
+		}
+		c.Items = append(c.Items, items...)
+	}
+	return c, nil
+}
+
+// HasExtension reports whether path's file extension is one of
+// extensions, ignoring case. Dotfiles without a further extension, like
+// `.gitignore`, have no extension.
+func HasExtension(path string, extensions []string) bool {
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	if ext == "" || ext == base {
+		return false
+	}
+
+	for _, e := range extensions {
+		if strings.EqualFold(ext[1:], strings.TrimPrefix(e, ".")) {
+			return true
+		}
+	}
+	return false
+}
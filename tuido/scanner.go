@@ -0,0 +1,39 @@
+package tuido
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// MaxLineLength is the longest line read from a file. Longer lines (eg,
+// of minified or generated files) stop the read, rather than being split.
+const MaxLineLength = 1 << 20
+
+// utf8BOM is the byte order mark that some editors write at the start of
+// UTF-8 files. It is not part of the first line's text.
+const utf8BOM = "\ufeff"
+
+// NewLineScanner returns a scanner of the lines of r, of up to
+// MaxLineLength bytes, with any byte order mark dropped from the first.
+func NewLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineLength)
+	first := true
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if first && token != nil {
+			first = false
+			token = bytes.TrimPrefix(token, []byte(utf8BOM))
+		}
+		return advance, token, err
+	})
+	return scanner
+}
+
+// IsUTF16 reports whether data, the start of a file, has a UTF-16 byte
+// order mark. Such files are not read: only UTF-8 (and so ASCII) is.
+func IsUTF16(data string) bool {
+	return strings.HasPrefix(data, "\xff\xfe") || strings.HasPrefix(data, "\xfe\xff")
+}
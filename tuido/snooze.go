@@ -0,0 +1,71 @@
+package tuido
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// snoozeShorthand matches a snooze period, as for the `a` shorthand, eg
+// "3d", "2w", "1M", or "1y".
+var snoozeShorthand = regexp.MustCompile(`^[0-9]+[dwMy]$`)
+
+// SnoozedUntil returns the date the item is snoozed until, from a
+// `snooze:2022-06-01` annotation or else an `#active=2022-06-01` tag (as
+// set by Snooze), or nil if it is not snoozed. Malformed dates are taken
+// as not snoozed.
+func (i Item) SnoozedUntil() *time.Time {
+	for _, word := range strings.Split(i.Text(), " ") {
+		if strings.HasPrefix(word, "snooze:") {
+			if until, err := time.Parse("2006-01-02", word[len("snooze:"):]); err == nil {
+				return &until
+			}
+		}
+	}
+	for _, t := range i.Tags() {
+		if t.name == "active" {
+			if until := parseTagDate(t); !until.IsZero() {
+				return until
+			}
+		}
+	}
+	return nil
+}
+
+// SnoozeUntil snoozes the item until a date, written as a
+// `snooze:YYYY-MM-DD` annotation. The date is given as YYYY-MM-DD, or as
+// a period from today, eg "3d", "2w", "1M" (a month), or "1y". Any
+// earlier snooze (an annotation, or the `#active` and `#zzz` tags of
+// Snooze) is replaced; an empty date wakes the item.
+func (i *Item) SnoozeUntil(date string) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot snooze")
+	}
+	date = strings.TrimSpace(date)
+	if snoozeShorthand.MatchString(date) {
+		date = toDate(date).Format("2006-01-02")
+	}
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("cannot snooze until %q - expected eg 2022-06-01 or 3d", date)
+		}
+	}
+
+	words := []string{}
+	for _, word := range strings.Split(i.Text(), " ") {
+		if strings.HasPrefix(word, "snooze:") {
+			continue
+		}
+		if IsTagToken(word) {
+			if name := newTag(word).name; name == "active" || name == "zzz" {
+				continue
+			}
+		}
+		words = append(words, word)
+	}
+	if date != "" {
+		words = append(words, "snooze:"+date)
+	}
+	return i.SetText(strings.Join(words, " "))
+}
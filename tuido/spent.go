@@ -0,0 +1,68 @@
+package tuido
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// spentAnnotation prefixes the time spent on an item, eg `spent:1h25m`.
+const spentAnnotation = "spent:"
+
+// Spent returns the time spent on the item: that of its `spent:1h25m`
+// annotation, and of any `#spent` tag, in minutes, as pomodoros once
+// recorded it.
+func (i Item) Spent() time.Duration {
+	var spent time.Duration
+	for _, word := range strings.Split(i.Text(), " ") {
+		if strings.HasPrefix(word, spentAnnotation) {
+			if d, err := time.ParseDuration(word[len(spentAnnotation):]); err == nil {
+				spent += d
+			}
+		}
+	}
+	for _, t := range i.Tags() {
+		if t.name == "spent" {
+			if minutes, err := strconv.ParseFloat(t.value, 64); err == nil {
+				spent += time.Duration(minutes * float64(time.Minute))
+			}
+		}
+	}
+	return spent
+}
+
+// AddSpent adds d to the time spent on the item, rewriting its `spent:`
+// annotation. A `#spent` tag is folded into the annotation.
+func (i *Item) AddSpent(d time.Duration) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot add time spent")
+	}
+	total := i.Spent() + d
+
+	words := []string{}
+	for _, word := range strings.Split(i.Text(), " ") {
+		if strings.HasPrefix(word, spentAnnotation) {
+			continue
+		}
+		if IsTagToken(word) && newTag(word).name == "spent" {
+			continue
+		}
+		words = append(words, word)
+	}
+	words = append(words, spentAnnotation+FormatSpent(total))
+	return i.SetText(strings.Join(words, " "))
+}
+
+// FormatSpent writes a time spent to the minute, eg "1h25m", "2h", or
+// "25m".
+func FormatSpent(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	switch {
+	case minutes >= 60 && minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	case minutes >= 60:
+		return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,90 @@
+package tuido
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// syntax reads and writes the item lines of one file format. Items are
+// handled in their [x]it form, eg "[x] call mom #completed=2024-01-02",
+// whatever the format of their file: a syntax translates each line to
+// that form when it is read, and back again when it is written.
+type syntax interface {
+	// isItem reports whether raw is an item line.
+	isItem(raw string) bool
+	// decode returns the [x]it form of the item line raw.
+	decode(raw string) string
+	// encode returns the line which replaces raw, the item's current
+	// line, to write the [x]it form xit. raw is "" for new items.
+	encode(raw string, xit string) string
+	// tags returns the tags of the item text, in [x]it form.
+	tags(text string) []Tag
+}
+
+// syntaxes return the syntax of a file, for the file formats other than
+// [x]it, or nil for other files. Files with none of them are read as
+// [x]it, relaxed as described by IsTuido.
+var syntaxes = []func(file string) syntax{todoTxtSyntax, codeSyntax}
+
+// syntaxFor returns the syntax of file's item lines.
+func syntaxFor(file string) syntax {
+	for _, s := range syntaxes {
+		if found := s(file); found != nil {
+			return found
+		}
+	}
+	return xit{}
+}
+
+// Parse returns the item on the lineth line of file, raw, or nil if the
+// line is not an item in the syntax of file.
+func Parse(file string, line int, raw string) *Item {
+	if !syntaxFor(file).isItem(raw) {
+		return nil
+	}
+	return &Item{
+		file: file,
+		line: line,
+		raw:  raw,
+	}
+}
+
+// FromCache returns the item of raw at line of file, as it was parsed
+// before (eg, by an earlier run), without checking raw's syntax again.
+func FromCache(file string, line int, raw string) *Item {
+	return &Item{
+		file: file,
+		line: line,
+		raw:  raw,
+	}
+}
+
+// xit is the syntax of [x]it files, markdown, and code comments.
+type xit struct{}
+
+func (xit) isItem(raw string) bool {
+	return IsTuido(raw)
+}
+
+func (xit) decode(raw string) string {
+	return trim(raw)
+}
+
+// encode keeps the indentation, bullet, or comment prefix of raw.
+func (xit) encode(raw string, x string) string {
+	return strings.Replace(raw, trim(raw), "", 1) + x
+}
+
+func (xit) tags(text string) []Tag {
+	return Tags(text)
+}
+
+// todoTxtSyntax is the syntax of todo.txt files: todo.txt or done.txt,
+// or any file with the .todo extension.
+func todoTxtSyntax(file string) syntax {
+	base := strings.ToLower(filepath.Base(file))
+	if base == "todo.txt" || base == "done.txt" || filepath.Ext(base) == ".todo" {
+		return todoTxt{}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package tuido
 import (
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,17 +27,17 @@ func repl(s string) string {
 	// returned directly, w/ shorthand durations
 	switch s[0] {
 	case 'r':
-		return "#repeat=" + s[1:]
+		return TagSigil + "repeat=" + s[1:]
 	case 'e':
-		return "#estimate=" + s[1:]
+		return TagSigil + "estimate=" + s[1:]
 	}
 
 	// durations converted to dates, then returned
 	switch s[0] {
 	case 'd':
-		ret += "#due="
+		ret += TagSigil + "due="
 	case 'a':
-		ret += "#active="
+		ret += TagSigil + "active="
 	}
 
 	t := toDate(s[1:])
@@ -122,3 +123,64 @@ func ToDuration(dStr string) *time.Duration {
 
 	return &d
 }
+
+var xitDue regexp.Regexp = *regexp.MustCompile(`(^|\s)-> (\S+)`)
+
+// xitDueDate reads an [x]it! due date (`-> DATE`) from s. Dates are
+// taken as the last day of the period they name:
+//   - 2022-06-01 or 2022/06/01 (a day)
+//   - 2022-W22 (an ISO week, ending on Sunday)
+//   - 2022-06 (a month)
+//   - 2022-Q2 (a quarter)
+//   - 2022 (a year)
+func xitDueDate(s string) *time.Time {
+	match := xitDue.FindStringSubmatch(s)
+	if match == nil {
+		return nil
+	}
+	date := strings.ReplaceAll(match[2], "/", "-")
+
+	var due time.Time
+	if d, err := time.Parse("2006-01-02", date); err == nil {
+		due = d
+	} else if d, err := time.Parse("2006-01", date); err == nil {
+		due = d.AddDate(0, 1, -1)
+	} else if d, err := time.Parse("2006", date); err == nil {
+		due = d.AddDate(1, 0, -1)
+	} else if len(date) > 6 && (date[5] == 'Q' || date[5] == 'W') {
+		return xitPeriodEnd(date)
+	} else {
+		return nil
+	}
+	return &due
+}
+
+// xitPeriodEnd returns the last day of a 2022-Q2 quarter or 2022-W22 week.
+func xitPeriodEnd(date string) *time.Time {
+	year, err := strconv.Atoi(date[:4])
+	if err != nil || date[4] != '-' {
+		return nil
+	}
+	n, err := strconv.Atoi(date[6:])
+	if err != nil {
+		return nil
+	}
+
+	var end time.Time
+	switch date[5] {
+	case 'Q':
+		if n < 1 || n > 4 {
+			return nil
+		}
+		end = time.Date(year, time.Month(n*3+1), 0, 0, 0, 0, 0, time.UTC)
+	case 'W':
+		if n < 1 || n > 53 {
+			return nil
+		}
+		// ISO week 1 is the week holding January 4th
+		jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+		monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+		end = monday.AddDate(0, 0, (n-1)*7+6)
+	}
+	return &end
+}
@@ -0,0 +1,126 @@
+package tuido
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// todoTxt is the syntax of todo.txt files, eg
+//
+//	x 2024-01-02 (A) 2023-12-30 call mom @phone +family due:2024-01-03
+//
+// Every non-blank line is an item. Items are read as open, or as checked
+// if marked complete with a leading "x ". Completion and creation dates
+// are read as #completed and #created tags, and key:value extensions as
+// tags of the same name, and are written back in place. Contexts (@phone)
+// and projects (+family) are read as tags, eg #phone and #family, but are
+// left as written.
+//
+// NB: todo.txt has no ongoing, review, or obsolete status. Ongoing and
+// review items are written as open, and obsolete items as complete.
+type todoTxt struct{}
+
+// todoTxtExt matches key:value tags. Values beginning with "//" are left
+// alone, so that urls are not read as tags.
+var todoTxtExt = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):(/?[^\s/]\S*)$`)
+
+// todoTxtPriority matches a priority, eg "(A) ".
+var todoTxtPriority = regexp.MustCompile(`^\([A-Z]\) `)
+
+func (todoTxt) isItem(raw string) bool {
+	return strings.TrimSpace(raw) != ""
+}
+
+func (todoTxt) decode(raw string) string {
+	rest := strings.TrimSpace(raw)
+	status := Open
+	dates := []string{}
+
+	if strings.HasPrefix(rest, "x ") {
+		status = Checked
+		rest = rest[2:]
+		if date, ok := leadingDate(rest); ok {
+			dates = append(dates, Tag{"completed", date}.Token())
+			rest = rest[len(date)+1:]
+		}
+	}
+	priority := todoTxtPriority.FindString(rest)
+	rest = rest[len(priority):]
+	if date, ok := leadingDate(rest); ok {
+		dates = append(dates, Tag{"created", date}.Token())
+		rest = rest[len(date)+1:]
+	}
+
+	words := []string{}
+	for _, token := range strings.Split(rest, " ") {
+		if m := todoTxtExt.FindStringSubmatch(token); m != nil {
+			token = Tag{m[1], m[2]}.Token()
+		}
+		words = append(words, token)
+	}
+	words = append(words, dates...)
+
+	return status.String() + " " + priority + strings.Join(words, " ")
+}
+
+func (todoTxt) encode(raw string, x string) string {
+	status := strToStatus(x)
+	done := status.Finished()
+
+	text := strings.TrimPrefix(x[3:], " ")
+	priority := todoTxtPriority.FindString(text)
+	text = text[len(priority):]
+
+	completed, created := "", ""
+	words := []string{}
+	for _, token := range strings.Split(text, " ") {
+		if IsTagToken(token) {
+			t := newTag(token)
+			if _, ok := leadingDate(t.value + " "); ok {
+				if t.name == "completed" && done && completed == "" {
+					completed = t.value + " "
+					continue
+				}
+				if t.name == "created" && created == "" {
+					created = t.value + " "
+					continue
+				}
+			}
+			if t.value != "" {
+				token = t.name + ":" + t.value
+			}
+		}
+		words = append(words, token)
+	}
+
+	line := priority + created + strings.TrimSpace(strings.Join(words, " "))
+	if done {
+		return "x " + completed + line
+	}
+	return line
+}
+
+// tags include the item's contexts and projects.
+func (todoTxt) tags(text string) []Tag {
+	tags := Tags(text)
+	for _, token := range strings.Split(text, " ") {
+		if len(token) > 1 && (token[0] == '@' || token[0] == '+') {
+			tags = append(tags, Tag{name: token[1:]})
+		}
+	}
+	return tags
+}
+
+// leadingDate returns the YYYY-MM-DD date beginning s, if s begins with
+// one followed by a space.
+func leadingDate(s string) (string, bool) {
+	l := len("2006-01-02")
+	if len(s) <= l || s[l] != ' ' {
+		return "", false
+	}
+	if _, err := time.Parse("2006-01-02", s[:l]); err != nil {
+		return "", false
+	}
+	return s[:l], true
+}
@@ -1,7 +1,6 @@
 package tuido
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"os"
@@ -17,36 +16,95 @@ import (
 	"github.com/nilock/tuido/utils"
 )
 
-type status string
+type Status string
 
 const (
-	Open     status = "open"
-	Ongoing  status = "ongoing"
-	Checked  status = "checked"
-	Obsolete status = "obsolete"
-	unknown  status = "unknown"
+	Open     Status = "open"
+	Ongoing  Status = "ongoing"
+	Review   Status = "review"
+	Checked  Status = "checked"
+	Obsolete Status = "obsolete"
+	unknown  Status = "unknown"
 )
 
-var statuses []status = []status{Open, Ongoing, Checked, Obsolete}
+var statuses []Status = []Status{Open, Ongoing, Review, Checked, Obsolete}
 
-func (s status) String() string {
+// Markers are additional status markers, eg "[>]", recognized alongside
+// the built-in markers. An item keeps its marker until its status is
+// changed, which writes the built-in marker of the new status.
+var Markers = map[string]Status{}
+
+// CustomStatus is an additional status, eg blocked, with a marker of its
+// own, eg "[?]". Items of a Done status count as finished, and the rest
+// as pending.
+type CustomStatus struct {
+	Name   Status
+	Marker string
+	Done   bool
+}
+
+// CustomStatuses are the additional statuses, recognized and written
+// alongside the built-in statuses.
+var CustomStatuses = []CustomStatus{}
+
+// custom returns the custom status s, if it is one.
+func (s Status) custom() (CustomStatus, bool) {
+	for _, c := range CustomStatuses {
+		if c.Name == s {
+			return c, true
+		}
+	}
+	return CustomStatus{}, false
+}
+
+// Pending reports whether s is a status of unfinished items: open,
+// ongoing, review, or a custom status which is not Done.
+func (s Status) Pending() bool {
+	if c, ok := s.custom(); ok {
+		return !c.Done
+	}
+	return s == Open || s == Ongoing || s == Review
+}
+
+// Finished reports whether s is a status of finished items: checked,
+// obsolete, or a Done custom status.
+func (s Status) Finished() bool {
+	if c, ok := s.custom(); ok {
+		return c.Done
+	}
+	return s == Checked || s == Obsolete
+}
+
+// StampCompleted and StampCreated stamp items with the date that they are
+// finished, as #completed, and created, as #created.
+var StampCompleted, StampCreated = true, false
+
+// ReadOnly, when set, makes every item read-only, and refuses every file
+// write, eg for review of a shared directory.
+var ReadOnly = false
+
+func (s Status) String() string {
 	switch s {
 
 	case Open:
 		return "[ ]"
 	case Ongoing:
 		return "[@]"
+	case Review:
+		return "[r]"
 	case Checked:
 		return "[x]" // [✔] [✓] ?
 	case Obsolete:
 		return "[~]"
 	case unknown:
 		return "[?]"
-	default:
-		return ""
 	}
+	if c, ok := s.custom(); ok {
+		return c.Marker
+	}
+	return ""
 }
-func strToStatus(s string) status {
+func strToStatus(s string) Status {
 	s = s[:3]
 
 	if s == "[ ]" {
@@ -55,12 +113,23 @@ func strToStatus(s string) status {
 	if s == "[@]" {
 		return Ongoing
 	}
+	if s == "[r]" {
+		return Review
+	}
 	if s == "[x]" || s == "[X]" {
 		return Checked
 	}
 	if s == "[~]" {
 		return Obsolete
 	}
+	for _, c := range CustomStatuses {
+		if s == c.Marker {
+			return c.Name
+		}
+	}
+	if status, ok := Markers[s]; ok {
+		return status
+	}
 	return unknown
 }
 
@@ -73,6 +142,16 @@ type Item struct {
 	// item data
 
 	raw string
+
+	// readOnly items are displayed, but cannot be written back to disk
+	readOnly bool
+
+	// subtask relationships, by indentation. See Nest.
+	parent   *Item
+	children []*Item
+
+	// committed is when the item's line was committed to git. See Blame.
+	committed *time.Time
 }
 
 func (i *Item) Location() string {
@@ -83,12 +162,25 @@ func (i *Item) Location() string {
 	return fmt.Sprintf("%s:%d", i.file, i.line)
 }
 
+// File returns the path of the item's source file.
+func (i Item) File() string {
+	return i.file
+}
+
+// Line returns the item's line number in its source file.
+func (i Item) Line() int {
+	return i.line
+}
+
 // Status returns the status of the item. One of:
 //  - open (ie, noted but not begun)
 //  - ongoing (ie, in progress)
+//  - review (ie, in progress, but awaiting review)
 //  - checked (ie, completed)
 //  - obsolete (ie, no longer necessary)
-func (i Item) Satus() status {
+//
+// or one of the CustomStatuses.
+func (i Item) Satus() Status {
 	return strToStatus(i.trimmed())
 }
 
@@ -96,7 +188,7 @@ func (i Item) Satus() status {
 // on disk and updates the status of the in-memory item.
 //
 // If the disk write fails, the in-memory update is abandoned.
-func (i *Item) SetStatus(s status) error {
+func (i *Item) SetStatus(s Status) error {
 	if i == nil {
 		return fmt.Errorf("item is nil - cannot update status")
 	}
@@ -116,45 +208,43 @@ func (i *Item) SetStatus(s status) error {
 			// marked "done". It's only been pushed into the future
 			return nil
 		}
-		// [ ] add #completed=[currentDate] if s == Checked?
 	}
 
-	newRaw := i.scrap() + s.String() + " " + i.Text()
+	wasOngoing := i.Satus() == Ongoing
 
-	err := fileInsert(i.file, i.line, i.raw, newRaw)
+	err := i.rewrite(s.String() + " " + i.Text())
 	if err != nil {
 		return err
 	}
 
-	i.raw = newRaw
-	return nil
-}
-
-func (i *Item) IncrementTimeSpent(seconds int) {
-	if i == nil {
-		return
-	}
-	previouslySpent := 0.0
-
-	for _, t := range i.Tags() {
-		if t.name == "spent" {
-			var err error
-
-			previouslySpent, err = strconv.ParseFloat(t.value, 64)
-			if err != nil {
-				return
-			}
-			break
+	// stamp items as they are begun, for detection of stalled items
+	if s == Ongoing && !wasOngoing {
+		if err := i.setTag(Tag{
+			name:  "started",
+			value: time.Now().Format("2006-01-02"),
+		}); err != nil {
+			return err
 		}
 	}
-	asMinutes := float64(seconds) / 60
 
-	asStr := fmt.Sprintf("%.2f", previouslySpent+asMinutes)
+	// stamp finished items with a completion date, and clear the
+	// stamp from items which are reopened
+	if s.Finished() && StampCompleted {
+		return i.setTag(Tag{
+			name:  "completed",
+			value: time.Now().Format("2006-01-02"),
+		})
+	}
+	if !s.Finished() && i.Completed() != nil {
+		return i.RemoveTag("completed")
+	}
+	return nil
+}
 
-	i.setTag(Tag{
-		name:  "spent",
-		value: asStr,
-	})
+// IncrementTimeSpent adds the seconds of a finished pomodoro to the time
+// spent on the item. See AddSpent.
+func (i *Item) IncrementTimeSpent(seconds int) {
+	i.AddSpent(time.Duration(seconds) * time.Second)
 }
 
 // SetText writes the updated text to the item's file
@@ -168,8 +258,52 @@ func (i *Item) SetText(t string) error {
 		return fmt.Errorf("item is nil - cannot update text")
 	}
 
-	newRaw := i.scrap() + i.Satus().String() + " " + t
-	err := fileInsert(i.file, i.line, i.raw, newRaw)
+	// the marker of the item's status is written, which normalizes eg [X]
+	// to [x], but for a marker of the markers config, which is kept
+	marker := i.trimmed()[:3]
+	if _, configured := Markers[marker]; !configured {
+		marker = i.Satus().String()
+	}
+	return i.rewrite(marker + " " + t)
+}
+
+// Raw returns the item's whole source line, including any indentation,
+// bullet, or comment prefix.
+func (i Item) Raw() string {
+	return i.raw
+}
+
+// SetRaw replaces the item's whole source line, eg to edit its marker,
+// on disk and in memory. The new line must still parse as an item.
+func (i *Item) SetRaw(raw string) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot update line")
+	}
+	if !syntaxFor(i.file).isItem(raw) {
+		return fmt.Errorf("not an item: %q has no status marker", raw)
+	}
+	return i.write(raw)
+}
+
+// rewrite replaces the item's line with the [x]it form x, written in the
+// syntax of its file.
+func (i *Item) rewrite(x string) error {
+	return i.write(syntaxFor(i.file).encode(i.raw, x))
+}
+
+// write replaces the item's line on disk with newRaw, and then
+// updates the in-memory item.
+func (i *Item) write(newRaw string) error {
+	if i.ReadOnly() {
+		return fmt.Errorf("item is read-only - cannot update %s", i.Location())
+	}
+
+	var err error
+	if pending != nil {
+		err = pending.replace(i, newRaw)
+	} else {
+		err = fileInsert(i.file, i.line, i.raw, newRaw)
+	}
 	if err != nil {
 		return err
 	}
@@ -177,6 +311,19 @@ func (i *Item) SetText(t string) error {
 	return nil
 }
 
+// ReadOnly reports whether the item cannot be written back to its file,
+// as it was read from a source that cannot be written, or as tuido is in
+// read-only mode.
+func (i Item) ReadOnly() bool {
+	return i.readOnly || ReadOnly
+}
+
+// SetReadOnly prevents the item from being written back to its file, eg
+// for items read from a source that cannot be written.
+func (i *Item) SetReadOnly() {
+	i.readOnly = true
+}
+
 // GetContext reads and returns some surrounding text from the item's source file.
 //
 // The returned integer is the line number of the item's text inside the returned context.
@@ -229,42 +376,37 @@ func (i *Item) Snooze() error {
 	return i.setTag(Tag{"zzz", fmt.Sprint(count)})
 }
 
-// Escalate increases the "importance" of an item by prefixing it
-// with an exclamation point.
+// Escalate increases the priority of an item by prefixing it with an
+// exclamation point.
 func (i *Item) Escalate() error {
 	txt := i.Text()
 	if len(txt) == 0 {
 		return i.SetText("!")
 	}
 
-	if txt[0] == '!' {
+	if i.PriorityMarker() != "" {
 		return i.SetText("!" + txt)
 	}
 
 	return i.SetText("! " + txt)
 }
 
-// Deescalate decreases the "importance" of an item by removing
-// a leading exclamation mark.
-//
-// NB: deescalate is not up to [x]it spec wrt items prefixed with
-//     both periods and exclamations, and will fail to deescalate,
-//     eg, "..!!! do this"
-//
-// [ ] make [x]it spec compliant
-// [ ] wants unit tests
+// Deescalate decreases the priority of an item by removing an
+// exclamation mark from its priority marker, eg "..!!! do this" becomes
+// "..!! do this". A marker left without exclamation marks is removed.
 func (i *Item) Deescalate() error {
 	txt := i.Text()
-
-	if strings.HasPrefix(txt, "!!") {
-		return i.SetText(txt[1:])
+	marker := i.PriorityMarker()
+	if marker == "" {
+		return fmt.Errorf("item already has priority 0")
 	}
 
-	if strings.HasPrefix(txt, "! ") {
-		return i.SetText(txt[2:])
+	rest := txt[len(marker):]
+	marker = strings.Replace(marker, "!", "", 1)
+	if !strings.Contains(marker, "!") {
+		return i.SetText(strings.TrimPrefix(rest, " "))
 	}
-
-	return fmt.Errorf("item already has priority 0")
+	return i.SetText(marker + rest)
 }
 
 func fib(n int) int {
@@ -292,6 +434,67 @@ func (i *Item) snoozeCount() int {
 	return 0
 }
 
+// AddTag writes tag t to the item, replacing the value of an existing
+// tag of the same name.
+func (i *Item) AddTag(t Tag) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot add tag")
+	}
+	return i.setTag(t)
+}
+
+// RemoveTag removes all instances of the named tag from the item.
+func (i *Item) RemoveTag(name string) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot remove tag")
+	}
+
+	// whole tag tokens are matched, so that removing #foo leaves #foobar
+	kept := []string{}
+	found := false
+	for _, word := range strings.Split(i.Text(), " ") {
+		if IsTagToken(word) && newTag(word).name == name {
+			found = true
+			continue
+		}
+		kept = append(kept, word)
+	}
+	if !found {
+		return fmt.Errorf("item has no #%s tag", name)
+	}
+
+	return i.SetText(strings.TrimSpace(strings.Join(kept, " ")))
+}
+
+// RenameTag renames every instance of tag from to to, keeping their
+// values, eg #wip=2 becomes #in-progress=2. The contexts and projects of
+// todo.txt items (@wip, +wip) are renamed in place.
+func (i *Item) RenameTag(from, to string) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot rename tag")
+	}
+	_, todoTxtItem := syntaxFor(i.file).(todoTxt)
+
+	words := strings.Split(i.Text(), " ")
+	found := false
+	for n, word := range words {
+		if IsTagToken(word) {
+			if t := newTag(word); t.name == from {
+				words[n] = Tag{to, t.value}.Token()
+				found = true
+			}
+		} else if todoTxtItem && len(word) > 1 && (word[0] == '@' || word[0] == '+') && word[1:] == from {
+			words[n] = word[:1] + to
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("item has no #%s tag", from)
+	}
+
+	return i.SetText(strings.Join(words, " "))
+}
+
 // setTag replaces the value of an existing tag, or appends a new tag.
 func (i *Item) setTag(t Tag) error {
 	// replace existing value, if exists
@@ -304,49 +507,124 @@ func (i *Item) setTag(t Tag) error {
 	}
 
 	// else, append new tag
-	txt := i.Text() + " #" + t.String()
+	txt := i.Text() + " " + t.Token()
 	return i.SetText(txt)
 }
 
 // fileInsert replaces the lineNumberth line of file with updated, as long
-// it finds that the current contents of that line are as expected.
+// it finds that the current contents of that line are as expected, and
+// otherwise returns a ConflictError.
+//
+// The file is re-read on each call, so that successive inserts to one
+// file do not clobber one another, and is replaced atomically, via a
+// temp file and rename. Line endings (LF or CRLF) and the presence of a
+// final newline are preserved.
 func fileInsert(file string, lineNumber int, expected string, updated string) error {
-	f, err := os.OpenFile(file, os.O_RDWR, os.ModeExclusive)
-	defer f.Close()
-
+	content, err := os.ReadFile(file)
 	if err != nil {
 		fmt.Printf("error opening file for setStatus: %s", err)
 		return err
 	}
-	scanner := bufio.NewScanner(f)
 
-	lines := []string{""} // blank line to offset
-
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+	content, err = replaceLine(file, content, lineNumber, expected, updated)
+	if err != nil {
+		return err
 	}
+	return writeAtomic(file, content)
+}
 
-	if lines[lineNumber] != expected {
-		fmt.Printf("error finding todo: %s != %s", lines[lineNumber], expected)
-		return fmt.Errorf("todo no longer in expected location, or changed on disk...")
+// replaceLine replaces the lineNumberth line of content, the content of
+// file, with updated, as fileInsert does.
+func replaceLine(file string, content []byte, lineNumber int, expected string, updated string) ([]byte, error) {
+	text := string(content)
+	finalEOL := strings.HasSuffix(text, "\n")
+	lines := append([]string{""}, strings.Split(strings.TrimSuffix(text, "\n"), "\n")...) // blank line to offset
+
+	// a byte order mark is kept at the start of the file, but is not part
+	// of the first line
+	bom := ""
+	if lineNumber == 1 && strings.HasPrefix(lines[1], utf8BOM) {
+		bom = utf8BOM
 	}
 
-	_, err = f.Seek(0, 0)
-	if err != nil {
-		fmt.Printf("seek error: %s", err)
+	if lineNumber < 1 || lineNumber >= len(lines) ||
+		!forcing && strings.TrimSuffix(strings.TrimPrefix(lines[lineNumber], bom), "\r") != expected {
+		return nil, &ConflictError{file, lineNumber}
 	}
+	updated = bom + updated
 
+	if strings.HasSuffix(lines[lineNumber], "\r") {
+		updated += "\r" // keep the line's CRLF ending
+	}
 	lines[lineNumber] = updated
 
-	for _, l := range lines[1:] {
-		_, err := f.Write([]byte(l + "\n"))
-		if err != nil {
-			return err
-		}
+	text = strings.Join(lines[1:], "\n")
+	if finalEOL {
+		text += "\n"
+	}
+	return []byte(text), nil
+}
+
+// writeAtomic replaces file with data, as WriteAtomic does, unless in
+// read-only mode.
+func writeAtomic(file string, data []byte) error {
+	if ReadOnly {
+		return fmt.Errorf("read-only mode - cannot write to %s", file)
+	}
+	return WriteAtomic(file, data)
+}
+
+// WriteAtomic replaces file with data, or creates it, by writing a temp
+// file alongside it and renaming it into place, so that file is never
+// left partly written. The file keeps its permissions. Unlike the writes
+// of items, it is not refused in read-only mode, so that programs can use
+// it for their own files, eg the app's sidecar notes.
+func WriteAtomic(file string, data []byte) error {
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(file); err == nil {
+		perm = info.Mode().Perm()
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".tuido-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	// flush the new content to disk before it replaces the old, so that
+	// a crash leaves one version or the other
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
 	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return err
+	}
+	syncDir(filepath.Dir(file))
 	return nil
 }
 
+// syncDir flushes a directory entry change, eg a rename, to disk. It is
+// best effort: not every platform can sync a directory.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
 // String returns the item status box plus body text. EG, for the item
 //  - [x] this one
 //
@@ -371,37 +649,46 @@ func (i Item) Text() string {
 }
 
 func (i Item) Tags() []Tag {
-	return Tags(i.Text())
+	return syntaxFor(i.file).tags(i.Text())
 }
 
 // Active returns the "active" status for snoozed items.
-// Items with `active` tags later than the current date will not
-// be shown in the regular view. Defaults to true.
+// Items snoozed (with `snooze:` annotations or `active` tags) until
+// later than the current date will not be shown in the regular view.
+// Defaults to true.
 func (i Item) Active() bool {
-	for _, t := range i.Tags() {
-		if t.name == "active" {
-			return parseTagDate(t).Before(time.Now())
-		}
-	}
-	return true
+	until := i.SnoozedUntil()
+	return until == nil || until.Before(time.Now())
 }
 
-// Importance returns the number of leading '!'s in
-// the item's Text.
+// Importance returns the item's Priority.
+//
+// Deprecated: use Priority.
 func (i Item) Importance() int {
-	count := 0
-	txt := i.Text()
+	return i.Priority()
+}
 
-	for _, ch := range txt {
-		if ch == '!' {
-			count++
-		} else if ch == '.' {
-		} else {
-			return count
-		}
-	}
+// Priority returns the number of '!'s in the item's [x]it! priority: a
+// run of '!' and '.' characters at the start of its text, followed by a
+// space or the end of the text. Text such as "!important" has no
+// priority.
+func (i Item) Priority() int {
+	marker := i.PriorityMarker()
+	return strings.Count(marker, "!")
+}
 
-	return count
+// PriorityMarker returns the leading '!' and '.' run of the item's text,
+// eg "..!", or "" if the item has no priority.
+func (i Item) PriorityMarker() string {
+	txt := i.Text()
+	marker := txt[:len(txt)-len(strings.TrimLeft(txt, "!."))]
+	if marker == "" || len(marker) < len(txt) && txt[len(marker)] != ' ' {
+		return ""
+	}
+	if !strings.Contains(marker, "!") {
+		return ""
+	}
+	return marker
 }
 
 func (i Item) Created() *time.Time {
@@ -422,6 +709,45 @@ func (i Item) Created() *time.Time {
 	return nil
 }
 
+// Started returns the date the item was last set ongoing, from its
+// #started tag, if it has one.
+func (i Item) Started() *time.Time {
+	for _, t := range i.Tags() {
+		if t.name == "started" {
+			return parseTagDate(t)
+		}
+	}
+	return nil
+}
+
+// Stalled reports whether the item has been ongoing for longer than
+// threshold, counting from when it was started, or else from its creation.
+func (i Item) Stalled(threshold time.Duration) bool {
+	if i.Satus() != Ongoing {
+		return false
+	}
+
+	since := i.Started()
+	if since == nil {
+		since = i.Created()
+	}
+	if since == nil {
+		return false
+	}
+	return time.Since(*since) > threshold
+}
+
+// Completed returns the date the item was checked or made obsolete,
+// from its #completed tag, if it has one.
+func (i Item) Completed() *time.Time {
+	for _, t := range i.Tags() {
+		if t.name == "completed" {
+			return parseTagDate(t)
+		}
+	}
+	return nil
+}
+
 func (i Item) Repeat() *time.Duration {
 	for _, t := range i.Tags() {
 		if t.name == "repeat" {
@@ -432,13 +758,39 @@ func (i Item) Repeat() *time.Duration {
 	return nil
 }
 
+// Due returns the item's due date, from a `#due=` tag or else an [x]it!
+// style `-> 2022-06-01` due date, or nil if it has none. Malformed dates
+// are taken as no due date.
 func (i Item) Due() *time.Time {
 	for _, t := range i.Tags() {
 		if t.name == "due" { //  [ ]!  make a const enum somewhere - appTags or something
-			return parseTagDate(t)
+			if due := parseTagDate(t); !due.IsZero() {
+				return due
+			}
+			return nil
 		}
 	}
-	return nil
+	return xitDueDate(i.Text())
+}
+
+// Overdue reports whether the item's due date has passed.
+func (i Item) Overdue() bool {
+	due := i.Due()
+	if due == nil {
+		return false
+	}
+	y, m, d := time.Now().Date()
+	return due.Before(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+}
+
+// DueMarker returns the item's [x]it! style due date as written, eg
+// `-> 2022-06`, or "" if it has none, or if the date is malformed.
+func (i Item) DueMarker() string {
+	text := i.Text()
+	if xitDueDate(text) == nil {
+		return ""
+	}
+	return strings.TrimSpace(xitDue.FindString(text))
 }
 
 func parseTagDate(t Tag) *time.Time {
@@ -458,7 +810,7 @@ func parseTagDate(t Tag) *time.Time {
 //
 // [ ] unit #test this w/ a bunch of expected passes & failures
 // [ ] #maybe allow numbered md lists (1. [ ] ...)
-// [ ] #maybe include a language map for code-comment parsing. ie, {".rb": "#", ".go": "//"}
+// [x] #maybe include a language map for code-comment parsing. ie, {".rb": "#", ".go": "//"}
 //  [ ]! #maybe require a file extension for this fcn. Allows for PL specific rules, as well as md
 func IsTuido(raw string) bool {
 	trimmed := trim(raw)
@@ -468,6 +820,16 @@ func IsTuido(raw string) bool {
 			return true
 		}
 	}
+	for _, c := range CustomStatuses {
+		if strings.HasPrefix(trimmed, c.Marker) {
+			return true
+		}
+	}
+	for marker := range Markers {
+		if strings.HasPrefix(trimmed, marker) {
+			return true
+		}
+	}
 
 	return false
 }
@@ -492,12 +854,9 @@ func trim(raw string) string {
 	return trimmed
 }
 
+// trimmed is the item's line in [x]it form, less any prefix.
 func (i Item) trimmed() string {
-	return trim(i.raw)
-}
-
-func (i Item) scrap() string {
-	return strings.Replace(i.raw, i.trimmed(), "", 1)
+	return syntaxFor(i.file).decode(i.raw)
 }
 
 func New(
@@ -505,47 +864,130 @@ func New(
 	line int,
 	raw string,
 ) Item {
-	// [ ] !!!!!!! replace this magic # w/ better named ctors
 	if line < 0 { // this is a new item authored in-tui
-		newItemRaw := "[ ] "
+		item, _ := Create(file, Open, "")
+		return item
+	}
+	return Item{
+		file: file,
+		line: line,
+		raw:  raw,
+	}
+}
 
-		// append new blank todo to `file`
-		fInfo, err := os.Stat(file)
+// Create appends a new item with status s and body text to file, and
+// returns it. If file is a directory, the item is appended to a
+// datestamped .xit file inside of it. Date shorthands in text are
+// expanded, as with SetText. Items are stamped #created if StampCreated
+// is set.
+func Create(file string, s Status, text string) (Item, error) {
+	// append new todo to `file`
+	file = appendTarget(file)
+	text = stampCreated(expandDateShorthands(text))
+	newItemRaw := syntaxFor(file).encode("", s.String()+" "+text)
+
+	line, err := appendLine(file, newItemRaw)
+	if err != nil {
+		return Item{}, err
+	}
+	return Item{
+		file: file,
+		line: line,
+		raw:  newItemRaw,
+	}, nil
+}
 
-		if err != nil {
-			fmt.Printf("error checking if %s is a directory: %s\n", fInfo, err)
+// stampCreated adds a #created date of today to text, if StampCreated is
+// set and text has none.
+func stampCreated(text string) string {
+	if !StampCreated {
+		return text
+	}
+	for _, t := range Tags(text) {
+		if t.name == "created" {
+			return text
 		}
+	}
+	return strings.TrimSpace(text + " " + Tag{"created", time.Now().Format("2006-01-02")}.Token())
+}
 
-		if fInfo.IsDir() {
-			file = filepath.Join(file, time.Now().Format("2006-01-02")+".xit") // xit, md, tbd
+// appendLine appends raw to file as a new line, and returns its line
+// number. Within a Batch which has already changed file, the line is
+// appended to the batch's copy of the file.
+func appendLine(file string, raw string) (int, error) {
+	if ReadOnly {
+		return 0, fmt.Errorf("read-only mode - cannot write to %s", file)
+	}
+	if pending != nil {
+		if content, ok := pending.files[file]; ok {
+			text := string(content)
+			if text != "" && !strings.HasSuffix(text, "\n") {
+				text += "\n"
+			}
+			text += raw + "\n"
+			pending.files[file] = []byte(text)
+			return strings.Count(text, "\n"), nil
 		}
+	}
 
-		f, err := os.OpenFile(file, os.O_APPEND|os.O_RDWR|os.O_CREATE, 0777)
-		if err != nil {
-			return Item{}
-		}
-		defer f.Close()
-		f.WriteString(newItemRaw)
-
-		// get the line # of the new item
-		f.Seek(0, 0) // reset to beginning of f
-		scanner := bufio.NewScanner(f)
-		newItemLine := 0
-		for scanner.Scan() {
-			newItemLine++
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_RDWR|os.O_CREATE, 0777)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// begin a new line if the file does not end with one
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			f.WriteString("\n")
 		}
+	}
+	if _, err := f.WriteString(raw + "\n"); err != nil {
+		return 0, err
+	}
 
-		return Item{
-			file: file,
-			line: newItemLine,
-			raw:  newItemRaw,
+	// get the line # of the new item
+	f.Seek(0, 0) // reset to beginning of f
+	scanner := NewLineScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+	}
+	return line, nil
+}
+
+// appendTarget is the file written to when appending to target, which
+// is either a file, or a directory receiving datestamped .xit files.
+func appendTarget(target string) string {
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return filepath.Join(target, time.Now().Format("2006-01-02")+".xit") // xit, md, tbd
+	}
+	return target
+}
+
+// ParseStatus reads a status from its name (eg, "ongoing") or its
+// marker (eg, "[@]"), among the built-in and custom statuses.
+func ParseStatus(s string) (Status, error) {
+	for _, status := range statuses {
+		if s == string(status) || s == status.String() {
+			return status, nil
 		}
 	}
-	return Item{
-		file: file,
-		line: line,
-		raw:  raw,
+	for _, c := range CustomStatuses {
+		if s == string(c.Name) || s == c.Marker {
+			return c.Name, nil
+		}
 	}
+	return unknown, fmt.Errorf("unknown status %q", s)
+}
+
+// TagSigil is the prefix which marks tags in item text. Defaults to "#".
+var TagSigil = "#"
+
+// IsTagToken reports whether a space-delimited token of item text is a tag.
+func IsTagToken(token string) bool {
+	return strings.HasPrefix(token, TagSigil) && len(token) > len(TagSigil)
 }
 
 func Tags(s string) []Tag {
@@ -553,8 +995,8 @@ func Tags(s string) []Tag {
 	split := strings.Split(s, " ")
 
 	for _, token := range split {
-		if strings.HasPrefix(token, "#") && len(token) > 1 {
-			tags = append(tags, newTag(token[1:]))
+		if IsTagToken(token) {
+			tags = append(tags, newTag(token[len(TagSigil):]))
 		}
 	}
 
@@ -569,6 +1011,16 @@ type Tag struct {
 func (t Tag) Name() string {
 	return t.name
 }
+
+// Value returns the tag's value, or "" if it has none.
+func (t Tag) Value() string {
+	return t.value
+}
+
+// Token returns the tag as written in item text, eg "#due=2022-06-01".
+func (t Tag) Token() string {
+	return TagSigil + t.String()
+}
 func (t Tag) String() string {
 	if t.value != "" {
 		return fmt.Sprintf("%s=%s", t.name, t.value)
@@ -579,8 +1031,8 @@ func (t Tag) String() string {
 
 // newTag splits a string token "#name=value" into a Tag struct.
 func newTag(s string) Tag {
-	if strings.HasPrefix(s, "#") && len(s) > 1 {
-		s = s[1:]
+	if IsTagToken(s) {
+		s = s[len(TagSigil):]
 	}
 
 	split := strings.Split(s, "=")
@@ -1,7 +1,15 @@
 package tuido
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 func TestNewTag(t *testing.T) {
@@ -65,15 +73,15 @@ func TestImportance(t *testing.T) {
 			raw:  "[ ] not important at all",
 		},
 		{
-			"",
-			-1,
-			"[ ] ! a bit important",
+			file: "",
+			line: -1,
+			raw:  "[ ] ! a bit important",
 		},
 		{
-			"", -1, "[ ] !! a little more",
+			file: "", line: -1, raw: "[ ] !! a little more",
 		},
 		{
-			"", -1, "[ ] ..!!! has leading periods, but should still be 3",
+			file: "", line: -1, raw: "[ ] ..!!! has leading periods, but should still be 3",
 		},
 	}
 
@@ -83,3 +91,1084 @@ func TestImportance(t *testing.T) {
 		}
 	}
 }
+
+func TestPriority(t *testing.T) {
+	tests := map[string]int{
+		"[ ] !!! urgent":          3,
+		"[ ] .!! padded":          2,
+		"[ ] !":                   1,
+		"[ ] ... no exclamations": 0,
+		"[ ] !important word":     0,
+		"[ ] plain":               0,
+	}
+	for raw, expected := range tests {
+		item := Item{file: "", line: -1, raw: raw}
+		if item.Priority() != expected {
+			t.Errorf("expected priority %d for %q, but found %d", expected, raw, item.Priority())
+		}
+	}
+}
+
+func TestDeescalate(t *testing.T) {
+	tests := map[string]string{
+		"[ ] ..!!! do this": "[ ] ..!! do this",
+		"[ ] ! do this":     "[ ] do this",
+		"[ ] !! do this":    "[ ] ! do this",
+		"[ ] .! do this":    "[ ] do this",
+	}
+	for raw, expected := range tests {
+		file := filepath.Join(t.TempDir(), "todo.xit")
+		os.WriteFile(file, []byte(raw+"\n"), 0644)
+		item := Item{file: file, line: 1, raw: raw}
+
+		if err := item.Deescalate(); err != nil {
+			t.Fatalf("deescalating %q: %s", raw, err)
+		}
+		if item.String() != expected {
+			t.Errorf("expected %q deescalated to %q, but found %q", raw, expected, item.String())
+		}
+	}
+
+	item := Item{file: "", line: -1, raw: "[ ] !important word"}
+	if err := item.Deescalate(); err == nil {
+		t.Errorf("expected an error deescalating an item without priority")
+	}
+}
+
+func TestBatch(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] one\n[ ] two\n"), 0644)
+	one := Item{file: file, line: 1, raw: "[ ] one"}
+	two := Item{file: file, line: 2, raw: "[ ] two"}
+
+	err := Batch(func() error {
+		if err := one.SetText("one #a"); err != nil {
+			return err
+		}
+		if err := two.SetText("two #b"); err != nil {
+			return err
+		}
+		// no write reaches the file until the batch ends
+		if data, _ := os.ReadFile(file); string(data) != "[ ] one\n[ ] two\n" {
+			t.Errorf("expected writes deferred, but found %q", data)
+		}
+		return one.SetText("one #c")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "[ ] one #c\n[ ] two #b\n" {
+		t.Errorf("unexpected file content %q", data)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	item := Item{file: "todo.xit", line: 3, raw: "- [@] write the thing #due=2022-06-01"}
+
+	b, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"id":"15e74df7","file":"todo.xit","line":3,"status":"ongoing","due":"2022-06-01","text":"write the thing #due=2022-06-01","tags":["due=2022-06-01"]}`
+	if string(b) != expected {
+		t.Errorf("expected %s, but found %s", expected, string(b))
+	}
+}
+
+func TestCSV(t *testing.T) {
+	items := []*Item{
+		{file: "todo.xit", line: 3, raw: "- [@] write the thing, quickly #due=2022-06-01 #docs"},
+		{file: "todo.xit", line: 4, raw: "[x] read it"},
+	}
+
+	b, err := CSV(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "id,file,line,status,due,text,tags\n" +
+		items[0].ID() + ",todo.xit,3,ongoing,2022-06-01,\"write the thing, quickly #due=2022-06-01 #docs\",due=2022-06-01 docs\n" +
+		items[1].ID() + ",todo.xit,4,checked,,read it,\n"
+	if string(b) != expected {
+		t.Errorf("expected %q, but found %q", expected, string(b))
+	}
+}
+
+func TestFrontMatter(t *testing.T) {
+	lines := []string{
+		"---",
+		"title: weekend",
+		"todos:",
+		"  - fix the gutter",
+		`  - "[x] mow the lawn"`,
+		"  - [ ] left to the line parser",
+		"tags: [house]",
+		"  - not a todo",
+		"---",
+		"todos:",
+		"  - not front matter",
+	}
+	expected := map[int]string{
+		4: "[ ] fix the gutter",
+		5: "[x] mow the lawn",
+	}
+
+	fm := FrontMatter{}
+	found := 0
+	for _, l := range lines {
+		item := fm.Scan("notes.md", l)
+		if item == nil {
+			continue
+		}
+		found++
+		if expected[item.line] != item.String() {
+			t.Errorf("expected %q on line %d, but found %q", expected[item.line], item.line, item.String())
+		}
+		if !item.ReadOnly() {
+			t.Errorf("expected front matter item on line %d to be read-only", item.line)
+		}
+	}
+	if found != len(expected) {
+		t.Errorf("expected %d front matter items, but found %d", len(expected), found)
+	}
+}
+
+func TestID(t *testing.T) {
+	item := Item{file: "todo.xit", line: 3, raw: "[ ] call the plumber"}
+	moved := Item{file: "todo.xit", line: 9, raw: "[x] call the plumber #completed=2022-06-01"}
+	other := Item{file: "other.xit", line: 3, raw: "[ ] call the plumber"}
+	inline := Item{file: "todo.xit", line: 3, raw: "[ ] call the plumber §a1b2"}
+
+	if item.ID() != moved.ID() {
+		t.Errorf("expected id to survive moves, status and tag changes: %s != %s", item.ID(), moved.ID())
+	}
+	if item.ID() == other.ID() {
+		t.Errorf("expected items in different files to have different ids")
+	}
+	if inline.ID() != "a1b2" {
+		t.Errorf("expected inline id a1b2, but found %s", inline.ID())
+	}
+	if annotated := (Item{file: "todo.xit", raw: "[ ] call the plumber id:plumber"}); annotated.ID() != "plumber" {
+		t.Errorf("expected inline id plumber, but found %s", annotated.ID())
+	}
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	markers := map[string]Status{
+		"[ ]": Open,
+		"[@]": Ongoing,
+		"[r]": Review,
+		"[x]": Checked,
+		"[~]": Obsolete,
+	}
+
+	for marker, expected := range markers {
+		if s := strToStatus(marker); s != expected {
+			t.Errorf("expected %s to parse as %s, but found %s", marker, expected, s)
+		}
+		if expected.String() != marker {
+			t.Errorf("expected %s to write as %s, but found %s", expected, marker, expected.String())
+		}
+	}
+
+	for _, name := range []string{"ongoing", "[@]"} {
+		if s, err := ParseStatus(name); err != nil || s != Ongoing {
+			t.Errorf("expected %s to parse as ongoing, but found %s (%v)", name, s, err)
+		}
+	}
+	if _, err := ParseStatus("someday"); err == nil {
+		t.Errorf("expected an error for an unknown status")
+	}
+
+	// [X] is read as checked, and normalized to [x] on write
+	if strToStatus("[X]") != Checked {
+		t.Errorf("expected [X] to parse as checked")
+	}
+}
+
+func TestSetStatusWritesMarker(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	lines := "[ ] open\n- [@] ongoing\n    [~] obsolete\n// [x] checked\n"
+	if err := os.WriteFile(file, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []Item{
+		New(file, 1, "[ ] open"),
+		New(file, 2, "- [@] ongoing"),
+		New(file, 3, "    [~] obsolete"),
+		New(file, 4, "// [x] checked"),
+	}
+
+	for _, s := range statuses {
+		for n := range items {
+			item := &items[n]
+			if err := item.SetStatus(s); err != nil {
+				t.Fatal(err)
+			}
+			if item.Satus() != s {
+				t.Errorf("expected status %s, but found %s", s, item.Satus())
+			}
+		}
+	}
+
+	written, _ := os.ReadFile(file)
+	for n, line := range strings.Split(strings.TrimSpace(string(written)), "\n") {
+		if !strings.Contains(line, "[~] ") {
+			t.Errorf("expected line %d to be written with the [~] marker, but found %q", n+1, line)
+		}
+	}
+}
+
+func TestTagSigil(t *testing.T) {
+	TagSigil = "+"
+	defer func() { TagSigil = "#" }()
+
+	tags := Tags("call mom +family +due=2022-06-01 #notatag")
+	if len(tags) != 2 {
+		t.Fatalf("expected 2 tags, but found %d", len(tags))
+	}
+	if tags[0].Name() != "family" || tags[1].Name() != "due" || tags[1].value != "2022-06-01" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if tags[1].Token() != "+due=2022-06-01" {
+		t.Errorf("expected token +due=2022-06-01, but found %s", tags[1].Token())
+	}
+	if newTag("+family").Name() != "family" {
+		t.Errorf("expected newTag to strip the + sigil")
+	}
+	if expanded := expandDateShorthands("call mom r1w"); expanded != "call mom +repeat=1w" {
+		t.Errorf("expected shorthand to expand with the + sigil, but found %s", expanded)
+	}
+}
+
+func TestNest(t *testing.T) {
+	raws := []string{
+		"- [ ] pack for the beach",
+		"  - [x] towels",
+		"  - [ ] sunscreen",
+		"    - [ ] spf 50",
+		"- [ ] book the car",
+		"\t[ ] tabbed subtask",
+	}
+	items := []*Item{}
+	for n, raw := range raws {
+		item := New("beach.md", n+1, raw)
+		items = append(items, &item)
+	}
+
+	Nest(items)
+
+	parents := []int{-1, 0, 0, 2, -1, 4}
+	for n, p := range parents {
+		parent := items[n].Parent()
+		if p < 0 && parent != nil {
+			t.Errorf("expected item %d to have no parent, but found %s", n, parent)
+		}
+		if p >= 0 && parent != items[p] {
+			t.Errorf("expected item %d to be a subtask of item %d", n, p)
+		}
+	}
+
+	if finished, total := items[0].Progress(); finished != 1 || total != 2 {
+		t.Errorf("expected progress 1/2, but found %d/%d", finished, total)
+	}
+}
+
+func TestFileInsertPreservesLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"crlf.xit":      "[ ] one\r\n[ ] a longer item\r\n[ ] three\r\n",
+		"no-eol.xit":    "[ ] one\n[ ] a longer item\n[ ] three",
+		"mixed-eol.xit": "[ ] one\n[ ] a longer item\r\n[ ] three\n",
+	}
+	expected := map[string]string{
+		"crlf.xit":      "[ ] one\r\n[x] two\r\n[ ] three\r\n",
+		"no-eol.xit":    "[ ] one\n[x] two\n[ ] three",
+		"mixed-eol.xit": "[ ] one\n[x] two\r\n[ ] three\n",
+	}
+
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := fileInsert(file, 2, "[ ] a longer item", "[x] two"); err != nil {
+			t.Fatal(err)
+		}
+		if err := fileInsert(file, 4, "[ ] four", "[x] four"); err == nil {
+			t.Errorf("expected an error inserting past the end of %s", name)
+		}
+
+		written, _ := os.ReadFile(file)
+		if string(written) != expected[name] {
+			t.Errorf("expected %s to be written as %q, but found %q", name, expected[name], string(written))
+		}
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != len(files) {
+		t.Errorf("expected no temp files left behind, but found %d files", len(entries))
+	}
+}
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "todo.xit")
+	if err := os.WriteFile(file, []byte("[x] one\n[ ] two\n  [~] three\n[x] four\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []*Item{}
+	for n, raw := range []string{"[x] one", "  [~] three", "[x] four"} {
+		item := New(file, []int{1, 3, 4}[n], raw)
+		items = append(items, &item)
+	}
+
+	archive := filepath.Join(dir, "archive")
+	changed, err := Archive(items, archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != file {
+		t.Errorf("expected %s to be changed, but found %v", file, changed)
+	}
+
+	if written, _ := os.ReadFile(file); string(written) != "[ ] two\n" {
+		t.Errorf("expected archived lines to be removed, but found %q", string(written))
+	}
+
+	// archived lines are stamped with their completion and origin
+	today := time.Now().Format("2006-01-02")
+	expected := ""
+	for n, x := range []string{"[x] one", "[~] three", "[x] four"} {
+		expected += fmt.Sprintf("%s #completed=%s #from=%s:%d\n", x, today, file, []int{1, 3, 4}[n])
+	}
+	archived, _ := os.ReadFile(appendTarget(archive))
+	if string(archived) != expected {
+		t.Errorf("expected archived lines to be appended to the archive, but found %q", string(archived))
+	}
+
+	// stale items are refused, rather than removing the wrong lines, and
+	// nothing is archived with them
+	two := New(file, 1, "[ ] two")
+	if _, err := Archive([]*Item{&two, items[0]}, archive); err == nil {
+		t.Errorf("expected an error archiving an item no longer in its file")
+	}
+	if again, _ := os.ReadFile(appendTarget(archive)); string(again) != expected {
+		t.Errorf("expected the archive unchanged after a refusal, but found %q", string(again))
+	}
+	if written, _ := os.ReadFile(file); string(written) != "[ ] two\n" {
+		t.Errorf("expected the file unchanged after a refusal, but found %q", string(written))
+	}
+}
+
+func TestArchiveWithBOM(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "todo.xit")
+	if err := os.WriteFile(file, []byte(utf8BOM+"[x] one\r\n[ ] two\r\n[x] three\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first line is matched without the byte order mark, which is kept
+	one := New(file, 1, "[x] one")
+	archive := filepath.Join(dir, "done.xit")
+	if _, err := Archive([]*Item{&one}, archive); err != nil {
+		t.Fatal(err)
+	}
+	if written, _ := os.ReadFile(file); string(written) != utf8BOM+"[ ] two\r\n[x] three\r\n" {
+		t.Errorf("expected the first line to be archived, keeping the BOM, but found %q", string(written))
+	}
+	if archived, _ := os.ReadFile(archive); strings.Count(string(archived), "[x] one") != 1 {
+		t.Errorf("expected the item to be archived once, but found %q", string(archived))
+	}
+
+	two := New(file, 1, "[ ] two")
+	if err := Delete(&two); err != nil {
+		t.Fatal(err)
+	}
+	if written, _ := os.ReadFile(file); string(written) != utf8BOM+"[x] three\r\n" {
+		t.Errorf("expected the first line to be deleted, keeping the BOM, but found %q", string(written))
+	}
+
+	// restored lines keep the file's BOM and CRLF endings
+	if err := Restore(&two); err != nil {
+		t.Fatal(err)
+	}
+	if written, _ := os.ReadFile(file); string(written) != utf8BOM+"[ ] two\r\n[x] three\r\n" {
+		t.Errorf("expected the line to be restored, keeping the BOM and CRLF, but found %q", string(written))
+	}
+}
+
+func TestDue(t *testing.T) {
+	tests := map[string]string{
+		"[ ] file taxes -> 2022-04-18":      "2022-04-18",
+		"[ ] file taxes -> 2022/04/18 soon": "2022-04-18",
+		"[ ] plan the month -> 2022-06":     "2022-06-30",
+		"[ ] plan the quarter -> 2022-Q1":   "2022-03-31",
+		"[ ] plan the week -> 2022-W01":     "2022-01-09",
+		"[ ] plan the week -> 2020-W53":     "2021-01-03",
+		"[ ] plan the year -> 2022":         "2022-12-31",
+		"[ ] tagged #due=2022-05-01":        "2022-05-01",
+		"[ ] malformed -> 2022-13-45":       "",
+		"[ ] malformed #due=tomorrow":       "",
+		"[ ] arrow->2022-04-18 unspaced":    "",
+		"[ ] no due date":                   "",
+	}
+
+	for raw, expected := range tests {
+		item := Item{file: "todo.xit", line: 1, raw: raw}
+		due := item.Due()
+		if expected == "" {
+			if due != nil {
+				t.Errorf("expected no due date for %q, but found %s", raw, due.Format("2006-01-02"))
+			}
+			continue
+		}
+		if due == nil || due.Format("2006-01-02") != expected {
+			t.Errorf("expected due date %s for %q, but found %v", expected, raw, due)
+		}
+		if marker := item.DueMarker(); strings.HasPrefix(raw[4:], "tagged") != (marker == "") {
+			t.Errorf("expected a due marker for only the [x]it! dates of %q, but found %q", raw, marker)
+		}
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	Markers = map[string]Status{"[>]": Ongoing}
+	defer func() { Markers = map[string]Status{} }()
+
+	if !IsTuido("- [>] carried over") {
+		t.Errorf("expected a custom marker to be parsed")
+	}
+	if IsTuido("[<] not configured") {
+		t.Errorf("expected an unconfigured marker not to be parsed")
+	}
+
+	item := Item{raw: "[>] carried over"}
+	if item.Satus() != Ongoing || item.Text() != "carried over" {
+		t.Errorf("expected an ongoing item 'carried over', but found %s %q", item.Satus(), item.Text())
+	}
+
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[>] carried over\n"), 0644)
+	item = Item{file: file, line: 1, raw: "[>] carried over"}
+	if err := item.SetText("carried over again"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "[>] carried over again\n" {
+		t.Errorf("expected an edit to keep the custom marker, but found %q", data)
+	}
+
+	// the built-in markers are normalized
+	os.WriteFile(file, []byte("[X] shouted\n"), 0644)
+	item = Item{file: file, line: 1, raw: "[X] shouted"}
+	if err := item.SetText("shouted again"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "[x] shouted again\n" {
+		t.Errorf("expected an edit to write [x], but found %q", data)
+	}
+}
+
+func TestSurrounding(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "notes.md")
+	os.WriteFile(file, []byte("# notes\nabout the parser\n[ ] fix the parser\r\nit drops lines\n"), 0644)
+
+	item := Item{file: file, line: 3, raw: "[ ] fix the parser"}
+
+	before, after, err := item.Surrounding(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(before, "|") != "# notes|about the parser" || strings.Join(after, "|") != "it drops lines" {
+		t.Errorf("unexpected context %q, %q", before, after)
+	}
+
+	if before, _, _ := item.Surrounding(1); strings.Join(before, "|") != "about the parser" {
+		t.Errorf("expected one line of context, but found %q", before)
+	}
+
+	moved := Item{file: file, line: 9, raw: "[ ] fix the parser"}
+	if _, _, err := moved.Surrounding(2); err == nil {
+		t.Errorf("expected an error for an item no longer at its line")
+	}
+}
+
+func TestSetRaw(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "notes.md")
+	os.WriteFile(file, []byte("# notes\n  - [ ] fix the parser\n"), 0644)
+	item := Item{file: file, line: 2, raw: "  - [ ] fix the parser"}
+
+	if err := item.SetRaw("not an item"); err == nil {
+		t.Errorf("expected an error for a line without a status marker")
+	}
+	if err := item.SetRaw("- [@] fix the parser #bug"); err != nil {
+		t.Fatal(err)
+	}
+	if item.Raw() != "- [@] fix the parser #bug" || item.Satus() != Ongoing {
+		t.Errorf("unexpected item %q", item.Raw())
+	}
+	if data, _ := os.ReadFile(file); string(data) != "# notes\n- [@] fix the parser #bug\n" {
+		t.Errorf("unexpected file content %q", data)
+	}
+}
+
+func TestTodoTxt(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "todo.txt")
+	raw := "(A) 2023-12-30 call mom @phone +family due:2024-01-03"
+	if err := os.WriteFile(file, []byte(raw+"\nx 2024-01-02 water the plants\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	item := Parse(file, 1, raw)
+	if item == nil {
+		t.Fatalf("expected %q to parse as an item", raw)
+	}
+	if item.Satus() != Open {
+		t.Errorf("expected an open item, but found %s", item.Satus())
+	}
+	tags := []string{}
+	for _, tag := range item.Tags() {
+		tags = append(tags, tag.String())
+	}
+	expected := "due=2024-01-03 created=2023-12-30 phone family"
+	if strings.Join(tags, " ") != expected {
+		t.Errorf("expected tags %q, but found %q", expected, strings.Join(tags, " "))
+	}
+	if item.dueString() != "2024-01-03" {
+		t.Errorf("expected due date 2024-01-03, but found %q", item.dueString())
+	}
+
+	done := Parse(file, 2, "x 2024-01-02 water the plants")
+	if done.Satus() != Checked || done.Completed() == nil || done.Completed().Format("2006-01-02") != "2024-01-02" {
+		t.Errorf("expected a checked item completed 2024-01-02, but found %q", done.String())
+	}
+	if Parse(file, 3, "  ") != nil {
+		t.Errorf("expected a blank line not to parse as an item")
+	}
+
+	// completion is written back in todo.txt form, and reopening reverts it
+	if err := item.SetStatus(Checked); err != nil {
+		t.Fatal(err)
+	}
+	checked := "x " + time.Now().Format("2006-01-02") + " " + raw
+	if item.Raw() != checked {
+		t.Errorf("expected %q, but found %q", checked, item.Raw())
+	}
+	if err := item.SetStatus(Open); err != nil {
+		t.Fatal(err)
+	}
+	if item.Raw() != raw {
+		t.Errorf("expected %q, but found %q", raw, item.Raw())
+	}
+	if written, _ := os.ReadFile(file); !strings.HasPrefix(string(written), raw+"\n") {
+		t.Errorf("expected the reopened item on disk, but found %q", string(written))
+	}
+
+	// xit items archived to a todo.txt file are translated
+	xitFile := filepath.Join(dir, "todo.xit")
+	os.WriteFile(xitFile, []byte("- [x] phoned #completed=2024-01-05 #who=mom\n"), 0644)
+	archived := New(xitFile, 1, "- [x] phoned #completed=2024-01-05 #who=mom")
+	if _, err := Archive([]*Item{&archived}, filepath.Join(dir, "done.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if written, _ := os.ReadFile(filepath.Join(dir, "done.txt")); string(written) != "x 2024-01-05 phoned who:mom from:"+xitFile+":1\n" {
+		t.Errorf("expected a todo.txt line in done.txt, but found %q", string(written))
+	}
+}
+
+func TestCodeComments(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "main.go")
+	TodoComments = []string{"TODO", "FIXME"}
+	defer func() { TodoComments = []string{} }()
+
+	tests := map[string]string{
+		`	x := 1 // [ ] name this better`:     "[ ] name this better",
+		`// TODO: frobnicate #backend`:        "[ ] frobnicate #backend",
+		`	// FIXME handle errors`:             "[ ] handle errors",
+		`	// [x] TODO: already marked`:        "[x] TODO: already marked",
+		`	s := "// [ ] in a string"`:          "",
+		"	s := `[ ] in a raw string` // code": "",
+		`	fmt.Println("[ ] not an item")`:     "",
+		`	// TODOS are not items`:             "",
+		`	r := '"' // [@] after a rune`:       "[@] after a rune",
+	}
+
+	for raw, expected := range tests {
+		item := Parse(file, 1, raw)
+		if item == nil {
+			if expected != "" {
+				t.Errorf("expected %q to parse as %q", raw, expected)
+			}
+			continue
+		}
+		if expected == "" {
+			t.Errorf("expected %q not to parse, but found %q", raw, item.String())
+		} else if item.String() != expected {
+			t.Errorf("expected %q to parse as %q, but found %q", raw, expected, item.String())
+		}
+	}
+
+	// a TODO keeps its keyword while open, and is marked once done
+	raw := "	go run() // TODO: frobnicate"
+	os.WriteFile(file, []byte(raw+"\n"), 0644)
+	item := Parse(file, 1, raw)
+	if err := item.SetText("frobnicate #backend"); err != nil {
+		t.Fatal(err)
+	}
+	if item.Raw() != "	go run() // TODO: frobnicate #backend" {
+		t.Errorf("expected the TODO keyword kept, but found %q", item.Raw())
+	}
+	if err := item.SetStatus(Checked); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(item.Raw(), "	go run() // [x] frobnicate #backend #completed=") {
+		t.Errorf("expected a checked marker, but found %q", item.Raw())
+	}
+}
+
+func TestRecur(t *testing.T) {
+	from := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	for every, expected := range map[string]string{
+		"day":       "2024-02-01",
+		"2d":        "2024-02-02",
+		"week":      "2024-02-07",
+		"month":     "2024-02-29", // the last day of a shorter month
+		"3months":   "2024-04-30",
+		"y":         "2025-01-31",
+		"weekday":   "2024-02-01",
+		"3weekdays": "2024-02-05", // skipping the weekend
+	} {
+		next, err := nextOccurrence(from, every)
+		if err != nil {
+			t.Errorf("every:%s: %s", every, err)
+		} else if next.Format("2006-01-02") != expected {
+			t.Errorf("expected every:%s after %s to be %s, but found %s", every, from.Format("2006-01-02"), expected, next.Format("2006-01-02"))
+		}
+	}
+	if _, err := nextOccurrence(from, "fortnight"); err == nil {
+		t.Errorf("expected an error for an unknown interval")
+	}
+
+	file := filepath.Join(t.TempDir(), "todo.md")
+	os.WriteFile(file, []byte("- [ ] water plants every:week #due=2024-01-31\n- [ ] other\n"), 0644)
+	item := Item{file: file, line: 1, raw: "- [ ] water plants every:week #due=2024-01-31"}
+	if err := item.SetStatus(Checked); err != nil {
+		t.Fatal(err)
+	}
+	next, err := item.Recur()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Raw() != "- [ ] water plants every:week #due=2024-02-07" || next.Line() != 3 {
+		t.Errorf("expected the next occurrence on line 3, but found %q on line %d", next.Raw(), next.Line())
+	}
+	if data, _ := os.ReadFile(file); !strings.HasSuffix(string(data), "- [ ] other\n- [ ] water plants every:week #due=2024-02-07\n") {
+		t.Errorf("unexpected file content %q", data)
+	}
+
+	// [x]it due markers are moved on, and other items do not recur
+	item = Item{file: file, line: 2, raw: "[x] review budget -> 2024-01-31 #every=month"}
+	if item.Every() != "month" {
+		t.Errorf("expected an #every tag read, but found %q", item.Every())
+	}
+	os.WriteFile(file, []byte("foo\n[x] review budget -> 2024-01-31 #every=month\n"), 0644)
+	if next, _ := item.Recur(); next == nil || next.Raw() != "[ ] review budget -> 2024-02-29 #every=month" {
+		t.Errorf("unexpected next occurrence %v", next)
+	}
+	if next, err := (&Item{file: file, line: 1, raw: "[ ] once"}).Recur(); next != nil || err != nil {
+		t.Errorf("expected no recurrence, but found %v, %v", next, err)
+	}
+}
+
+func TestStamps(t *testing.T) {
+	defer func() { StampCompleted, StampCreated = true, false }()
+	today := time.Now().Format("2006-01-02")
+	file := filepath.Join(t.TempDir(), "todo.xit")
+
+	StampCreated = true
+	item, err := Create(file, Open, "call mom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Raw() != "[ ] call mom #created="+today {
+		t.Errorf("expected a #created stamp, but found %q", item.Raw())
+	}
+	if created := item.Created(); created == nil || created.Format("2006-01-02") != today {
+		t.Errorf("expected the item created today, but found %v", created)
+	}
+
+	StampCompleted = false
+	if err := item.SetStatus(Checked); err != nil {
+		t.Fatal(err)
+	}
+	if item.Completed() != nil {
+		t.Errorf("expected no #completed stamp, but found %q", item.Raw())
+	}
+}
+
+func TestSnoozeUntil(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[ ] call mom #active=2000-01-01 #zzz=2\n"), 0644)
+	item := Item{file: file, line: 1, raw: "[ ] call mom #active=2000-01-01 #zzz=2"}
+
+	if err := item.SnoozeUntil("2999-01-01"); err != nil {
+		t.Fatal(err)
+	}
+	if item.Raw() != "[ ] call mom snooze:2999-01-01" {
+		t.Errorf("expected the earlier snooze replaced, but found %q", item.Raw())
+	}
+	if item.Active() {
+		t.Errorf("expected the snoozed item inactive")
+	}
+
+	if err := item.SnoozeUntil("3d"); err != nil {
+		t.Fatal(err)
+	}
+	expected := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	if until := item.SnoozedUntil(); until == nil || until.Format("2006-01-02") != expected {
+		t.Errorf("expected the item snoozed until %s, but found %q", expected, item.Raw())
+	}
+
+	if err := item.SnoozeUntil("someday"); err == nil {
+		t.Errorf("expected an error for a malformed date")
+	}
+
+	if err := item.SnoozeUntil(""); err != nil {
+		t.Fatal(err)
+	}
+	if item.Raw() != "[ ] call mom" || !item.Active() {
+		t.Errorf("expected the item woken, but found %q", item.Raw())
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	dir := t.TempDir()
+	for file, expected := range map[string][2]string{
+		"todo.xit": {"[ ] call mom #wip #wipe #wip=2", "[ ] call mom #doing #wipe #doing=2"},
+		"todo.txt": {"call mom @wip +wip wip:1", "call mom @doing +doing doing:1"},
+	} {
+		path := filepath.Join(dir, file)
+		os.WriteFile(path, []byte(expected[0]+"\n"), 0644)
+		item := Parse(path, 1, expected[0])
+		if err := item.RenameTag("wip", "doing"); err != nil {
+			t.Fatal(err)
+		}
+		if item.Raw() != expected[1] {
+			t.Errorf("%s: expected %q, but found %q", file, expected[1], item.Raw())
+		}
+	}
+	if err := (&Item{file: "todo.xit", raw: "[ ] none"}).RenameTag("wip", "doing"); err == nil {
+		t.Errorf("expected an error for an item without the tag")
+	}
+}
+
+func TestRemoveTag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(path, []byte("[ ] x #foo #foobar #foo=2\n"), 0644)
+	item := Parse(path, 1, "[ ] x #foo #foobar #foo=2")
+	if err := item.RemoveTag("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if item.Raw() != "[ ] x #foobar" {
+		t.Errorf("expected only the #foo tags to be removed, but found %q", item.Raw())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "[ ] x #foobar\n" {
+		t.Errorf("expected the file to keep #foobar, but found %q", data)
+	}
+	if err := item.RemoveTag("foo"); err == nil {
+		t.Errorf("expected an error for an item without the tag")
+	}
+}
+
+func TestICS(t *testing.T) {
+	items := []*Item{
+		{file: "todo.xit", line: 1, raw: "[ ] !! pay rent, on time #due=2022-03-01"},
+		{file: "todo.xit", line: 2, raw: "[x] undated"},
+		{file: "todo.xit", line: 3, raw: "[x] file taxes #due=2022-04-15 #completed=2022-04-10"},
+	}
+
+	data, err := ICS(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ics := string(data)
+	if strings.Count(ics, "BEGIN:VTODO") != 2 || strings.Contains(ics, "undated") {
+		t.Errorf("expected a VTODO per dated item, but found %q", ics)
+	}
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:" + items[0].ID() + "@tuido\r\n",
+		"DUE;VALUE=DATE:20220301\r\n",
+		"SUMMARY:!! pay rent\\, on time #due=2022-03-01\r\n",
+		"STATUS:NEEDS-ACTION\r\nPRIORITY:3\r\n",
+		"STATUS:COMPLETED\r\nCOMPLETED:20220410T000000Z\r\n",
+		"TRIGGER;RELATED=END:PT9H\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, expected) {
+			t.Errorf("expected %q in %q", expected, ics)
+		}
+	}
+
+	// times of other locations are given in UTC
+	tokyo := time.Date(2022, 4, 10, 9, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+	if stamp := icsTime(tokyo); stamp != "20220410T003000Z" {
+		t.Errorf("expected the time in UTC, but found %s", stamp)
+	}
+
+	long := icsFold(strings.Repeat("é", 50))
+	for _, line := range strings.Split(long, "\r\n") {
+		if len(line) > 75 || !utf8.ValidString(strings.TrimPrefix(line, " ")) {
+			t.Errorf("expected folded lines of valid UTF-8 of at most 75 octets, but found %q", line)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	soon := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	raws := []string{
+		"[ ] fix the parser #work #urgent",
+		"[@] write docs #work #due=" + soon,
+		"[ ] weed the beds #garden #someday",
+		"[x] paint the fence #home",
+		"[ ] pay rent #home #due=2022-03-01",
+	}
+	items := []*Item{}
+	for n, raw := range raws {
+		items = append(items, &Item{file: "todo.xit", line: n + 1, raw: raw})
+	}
+
+	for query, expected := range map[string][]int{
+		"#work and #urgent ":             {0},
+		"#home or #garden ":              {2, 3, 4},
+		"#home or #garden not #someday ": {3, 4},
+		"not #someday ":                  {0, 1, 3, 4},
+		"!(#work or #home) ":             {2},
+		"is:ongoing":                     {1},
+		"is:pending #home ":              {4},
+		"is:done":                        {3},
+		"is:overdue":                     {4},
+		"due:<7d":                        {1, 4},
+		"due:>today":                     {1},
+		"due:none #work ":                {0},
+		"due:<=2022-03-01":               {4},
+		"PARSER or fence":                {0, 3},
+		"#wor":                           {0, 1},
+		"#wor ":                          {},
+		"#work and":                      {0, 1},
+		"(#home or #garden":              {2, 3, 4},
+		"#garden ) or #urgent":           {0, 2},
+	} {
+		q := ParseQuery(query)
+		if q.Err() != nil {
+			t.Errorf("%q: unexpected error %s", query, q.Err())
+			continue
+		}
+		matched := []int{}
+		for n, item := range items {
+			if q.Match(item) {
+				matched = append(matched, n)
+			}
+		}
+		if fmt.Sprint(matched) != fmt.Sprint(expected) {
+			t.Errorf("%q: expected items %v, but matched %v", query, expected, matched)
+		}
+	}
+
+	for _, bad := range []string{"is:finished", "due:<soon"} {
+		if q := ParseQuery(bad); q.Err() == nil || q.Match(items[0]) {
+			t.Errorf("%q: expected an error, and no matches", bad)
+		}
+	}
+
+	if words := ParseQuery("parser not fence").Words(); fmt.Sprint(words) != "[parser]" {
+		t.Errorf("expected the words other than negated ones, but found %v", words)
+	}
+
+	for s, expected := range map[string]bool{
+		"#work #urgent": false, "fix parser": false, "#a or #b": true, "is:open": true, "!(#a)": true, "-#a": false,
+	} {
+		if IsQuery(s) != expected {
+			t.Errorf("expected IsQuery(%q) to be %t", s, expected)
+		}
+	}
+}
+
+func TestURLs(t *testing.T) {
+	tests := map[string][]string{
+		"[ ] review https://github.com/foo/bar/pull/1":                 {"https://github.com/foo/bar/pull/1"},
+		"[ ] read (see https://example.com/a_(b)) and http://x.org/y.": {"https://example.com/a_(b)", "http://x.org/y"},
+		"[ ] no links, just ftp://host and #tags":                      {},
+	}
+
+	for raw, expected := range tests {
+		item := Item{file: "todo.xit", line: 1, raw: raw}
+		if urls := item.URLs(); strings.Join(urls, " ") != strings.Join(expected, " ") {
+			t.Errorf("expected %v in %q, but found %v", expected, raw, urls)
+		}
+	}
+}
+
+func TestBlame(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "todo.xit")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE=2022-01-02T12:00:00Z", "GIT_COMMITTER_DATE=2022-01-02T12:00:00Z",
+			"GIT_AUTHOR_NAME=a", "GIT_AUTHOR_EMAIL=a@example.com", "GIT_COMMITTER_NAME=a", "GIT_COMMITTER_EMAIL=a@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	os.WriteFile(file, []byte("[ ] committed\n"), 0644)
+	git("init", "-q")
+	git("add", "todo.xit")
+	git("commit", "-q", "-m", "items")
+	os.WriteFile(file, []byte("[ ] committed\n[ ] not yet\n[ ] stamped #created=2021-06-01\n"), 0644)
+
+	items := []*Item{
+		{file: file, line: 1, raw: "[ ] committed"},
+		{file: file, line: 2, raw: "[ ] not yet"},
+		{file: file, line: 3, raw: "[ ] stamped #created=2021-06-01"},
+		{file: filepath.Join(t.TempDir(), "untracked.xit"), line: 1, raw: "[ ] elsewhere"},
+	}
+	Blame(items)
+
+	if c := items[0].Committed(); c == nil || c.UTC().Format("2006-01-02") != "2022-01-02" {
+		t.Errorf("expected the line's commit date, but found %v", c)
+	}
+	if age, ok := items[1].Age(); !ok || age > 24*time.Hour {
+		t.Errorf("expected an uncommitted line to be new, but found %v", age)
+	}
+	if age, _ := items[2].Age(); age < time.Since(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))-time.Minute {
+		t.Errorf("expected the #created stamp to set the age, but found %v", age)
+	}
+	if _, ok := items[3].Age(); ok {
+		t.Errorf("expected no age outside a repository")
+	}
+}
+
+func TestSpent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[@] write the report #spent=30.00 #work\n"), 0644)
+	item := &Item{file: file, line: 1, raw: "[@] write the report #spent=30.00 #work"}
+	id := item.ID()
+	if spent := item.Spent(); spent != 30*time.Minute {
+		t.Errorf("expected the #spent minutes counted, but found %s", spent)
+	}
+	item.AddSpent(55 * time.Minute)
+	if item.Raw() != "[@] write the report #work spent:1h25m" {
+		t.Errorf("expected the #spent tag folded into the annotation, but found %q", item.Raw())
+	}
+	item.AddSpent(35 * time.Minute)
+	if item.Raw() != "[@] write the report #work spent:2h" {
+		t.Errorf("expected the time spent accumulated, but found %q", item.Raw())
+	}
+	if item.ID() != id {
+		t.Errorf("expected the time spent to leave the ID be")
+	}
+	if s := FormatSpent(25 * time.Minute); s != "25m" {
+		t.Errorf("expected 25m, but found %s", s)
+	}
+}
+
+func TestHasExtension(t *testing.T) {
+	extensions := []string{"xit", "md", "go", "gitignore"}
+
+	tests := map[string]bool{
+		"cargo":              false,
+		"main.pogo":          false,
+		"foo.go":             true,
+		"foo.GO":             true,
+		"notes/todo.Md":      true,
+		".gitignore":         false,
+		"dir/.gitignore":     false,
+		".notes.md":          true,
+		"archive.xit.tar":    false,
+		"logo.png":           false,
+		"dir.md/README":      false,
+		"2022-06-01.xit":     true,
+		"some/path/todo.xit": true,
+	}
+
+	for path, expected := range tests {
+		if HasExtension(path, extensions) != expected {
+			t.Errorf("expected HasExtension(%q) to be %t", path, expected)
+		}
+	}
+
+	if !HasExtension("todo.md", []string{".md"}) {
+		t.Errorf("expected configured extensions to match with a leading dot")
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "notes"), 0755)
+	os.MkdirAll(filepath.Join(dir, "node_modules"), 0755)
+	os.WriteFile(filepath.Join(dir, "todo.xit"), []byte("[ ] fix the parser #bug\n[x] ship it #bug\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes", "plan.md"), []byte("- [ ] write the docs\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "node_modules", "dep.md"), []byte("- [ ] not ours\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "binary.txt"), []byte("[ ] \x00\n"), 0644)
+
+	skipped := []string{}
+	c, err := Scan([]string{dir}, ScanOptions{Skipped: func(path, reason string) {
+		skipped = append(skipped, filepath.Base(path)+": "+reason)
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Items) != 3 || c.Items[0].Text() != "write the docs" {
+		t.Fatalf("expected the items of the scanned files, in file order, but found %v", c.Items)
+	}
+	if strings.Join(skipped, ",") != "binary.txt: binary file" {
+		t.Errorf("expected the binary file reported as skipped, but found %v", skipped)
+	}
+
+	pending, err := c.Filter("#bug not is:done")
+	if err != nil || len(pending) != 1 || pending[0].Text() != "fix the parser #bug" {
+		t.Errorf("expected the pending #bug item, but found %v (%v)", pending, err)
+	}
+	if _, err := c.Filter("is:finished"); err == nil {
+		t.Errorf("expected a malformed query refused")
+	}
+
+	c.Batch(func() error {
+		c.Find(pending[0].ID()).SetStatus(Ongoing)
+		_, err := c.Add(filepath.Join(dir, "todo.xit"), Open, "add a test")
+		return err
+	})
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Items) != 4 || c.Items[1].Satus() != Ongoing || c.Items[3].Text() != "add a test" {
+		t.Errorf("expected the changes written, but found %v", c.Items)
+	}
+
+	if _, err := Scan([]string{filepath.Join(dir, "missing")}, ScanOptions{}); err == nil {
+		t.Errorf("expected a missing root to be an error")
+	}
+}
+
+func TestSetOngoingClearsCompleted(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	os.WriteFile(file, []byte("[x] thing #completed=2022-01-01\n"), 0644)
+	item := New(file, 1, "[x] thing #completed=2022-01-01")
+	if err := item.SetStatus(Ongoing); err != nil {
+		t.Fatal(err)
+	}
+	if item.Completed() != nil || strings.Contains(item.Raw(), "#completed") {
+		t.Errorf("expected a reopened item to lose its completion date, but found %q", item.Raw())
+	}
+	if !strings.Contains(item.Raw(), "#started=") {
+		t.Errorf("expected the item stamped #started, but found %q", item.Raw())
+	}
+}